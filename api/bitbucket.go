@@ -45,11 +45,20 @@ type PullRequest struct {
 		Approved bool   `json:"approved"`
 		Status   string `json:"status"`
 	} `json:"reviewers"`
-	Links struct {
+	Properties PullRequestProperties `json:"properties"`
+	Links      struct {
 		Self []Link `json:"self"`
 	} `json:"links"`
 }
 
+// PullRequestProperties are the counters Bitbucket attaches to a pull
+// request.
+type PullRequestProperties struct {
+	CommentCount      int `json:"commentCount,omitempty"`
+	OpenTaskCount     int `json:"openTaskCount,omitempty"`
+	ResolvedTaskCount int `json:"resolvedTaskCount,omitempty"`
+}
+
 type Ref struct {
 	ID           string     `json:"id"`
 	DisplayID    string     `json:"displayId"`
@@ -62,6 +71,29 @@ type Repository struct {
 	ID      int     `json:"id"`
 	Name    string  `json:"name"`
 	Project Project `json:"project"`
+	Links   struct {
+		Clone []CloneLink `json:"clone"`
+	} `json:"links"`
+}
+
+// CloneLink is a repository clone URL; Name is the protocol, "http" or
+// "ssh".
+type CloneLink struct {
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+// CloneURL returns the clone URL for protocol, falling back to any.
+func (r Repository) CloneURL(protocol string) string {
+	for _, l := range r.Links.Clone {
+		if l.Name == protocol {
+			return l.Href
+		}
+	}
+	if len(r.Links.Clone) > 0 {
+		return r.Links.Clone[0].Href
+	}
+	return ""
 }
 
 type Project struct {
@@ -94,16 +126,19 @@ type PagedResponse struct {
 }
 
 type Comment struct {
-	ID          int            `json:"id"`
-	Version     int            `json:"version"`
-	Text        string         `json:"text"`
-	Author      User           `json:"author"`
-	CreatedDate int64          `json:"createdDate"`
-	UpdatedDate int64          `json:"updatedDate"`
-	Severity    string         `json:"severity,omitempty"`
-	State       string         `json:"state,omitempty"`
-	Anchor      *CommentAnchor `json:"anchor,omitempty"`
-	Comments    []Comment      `json:"comments,omitempty"`
+	ID          int    `json:"id"`
+	Version     int    `json:"version"`
+	Text        string `json:"text"`
+	Author      User   `json:"author"`
+	CreatedDate int64  `json:"createdDate"`
+	UpdatedDate int64  `json:"updatedDate"`
+	Severity    string `json:"severity,omitempty"`
+	State       string `json:"state,omitempty"`
+	// ThreadResolved is set on the root comment of a resolved thread
+	// (Bitbucket 8.0+). Tasks use State instead.
+	ThreadResolved bool           `json:"threadResolved,omitempty"`
+	Anchor         *CommentAnchor `json:"anchor,omitempty"`
+	Comments       []Comment      `json:"comments,omitempty"`
 }
 
 type Commit struct {
@@ -141,6 +176,27 @@ func (c *BitbucketClient) ListPullRequests(ctx context.Context, project, repo st
 	return response.Values, nil
 }
 
+// ListPullRequestsFromBranch returns the open pull requests whose source is
+// branch.
+func (c *BitbucketClient) ListPullRequestsFromBranch(ctx context.Context, project, repo, branch string) ([]PullRequest, error) {
+	params := url.Values{}
+	params.Set("at", "refs/heads/"+strings.TrimPrefix(branch, "refs/heads/"))
+	params.Set("direction", "OUTGOING")
+	params.Set("state", "OPEN")
+
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests", project, repo)
+
+	var response struct {
+		Values []PullRequest `json:"values"`
+	}
+
+	if err := c.Get(ctx, path, params, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Values, nil
+}
+
 func (c *BitbucketClient) GetPullRequest(ctx context.Context, project, repo string, prID int) (*PullRequest, error) {
 	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d", project, repo, prID)
 
@@ -196,12 +252,13 @@ func (c *BitbucketClient) ListCommits(ctx context.Context, project, repo string,
 	return response.Values, nil
 }
 
-func (c *BitbucketClient) MergePullRequest(ctx context.Context, project, repo string, prID int, version int) error {
-	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/merge?version=%d", project, repo, prID, version)
-	return c.Post(ctx, path, nil, nil)
+func (c *BitbucketClient) CreatePullRequest(ctx context.Context, project, repo string, title, description, fromBranch, toBranch string, reviewers []string) (*PullRequest, error) {
+	return c.CreatePullRequestFrom(ctx, project, repo, project, repo, title, description, fromBranch, toBranch, reviewers)
 }
 
-func (c *BitbucketClient) CreatePullRequest(ctx context.Context, project, repo string, title, description, fromBranch, toBranch string, reviewers []string) (*PullRequest, error) {
+// CreatePullRequestFrom opens a pull request in project/repo from a branch of
+// another repository, typically a fork such as ~USER/repo.
+func (c *BitbucketClient) CreatePullRequestFrom(ctx context.Context, fromProject, fromRepo, project, repo string, title, description, fromBranch, toBranch string, reviewers []string) (*PullRequest, error) {
 	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests", project, repo)
 
 	body := map[string]interface{}{
@@ -210,9 +267,9 @@ func (c *BitbucketClient) CreatePullRequest(ctx context.Context, project, repo s
 		"fromRef": map[string]interface{}{
 			"id": fmt.Sprintf("refs/heads/%s", fromBranch),
 			"repository": map[string]interface{}{
-				"slug": repo,
+				"slug": fromRepo,
 				"project": map[string]string{
-					"key": project,
+					"key": fromProject,
 				},
 			},
 		},
@@ -361,22 +418,11 @@ func (c *BitbucketClient) UpdatePullRequest(ctx context.Context, project, repo s
 	return &pr, nil
 }
 
+// GetPullRequestCommits returns up to limit commits of a pull request,
+// newest first, all of them when limit is 0.
 func (c *BitbucketClient) GetPullRequestCommits(ctx context.Context, project, repo string, prID int, limit int) ([]Commit, error) {
-	params := url.Values{}
-	params.Set("limit", strconv.Itoa(limit))
-
 	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/commits", project, repo, prID)
-
-	var response struct {
-		Values []Commit `json:"values"`
-	}
-
-	err := c.Get(ctx, path, params, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return response.Values, nil
+	return getPages[Commit](ctx, c, path, url.Values{}, limit)
 }
 
 func (c *BitbucketClient) GetPullRequestChanges(ctx context.Context, project, repo string, prID int, limit int) ([]Change, error) {
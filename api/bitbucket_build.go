@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Build states reported by the build-status API.
+const (
+	BuildSuccessful = "SUCCESSFUL"
+	BuildFailed     = "FAILED"
+	BuildInProgress = "INPROGRESS"
+)
+
+// BuildStatus is the latest result a CI server reported for one build
+// (identified by Key) on a commit.
+type BuildStatus struct {
+	State       string `json:"state"`
+	Key         string `json:"key"`
+	Name        string `json:"name,omitempty"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	DateAdded   int64  `json:"dateAdded"`
+}
+
+// BuildStats counts a commit's builds by state.
+type BuildStats struct {
+	Successful int `json:"successful"`
+	InProgress int `json:"inProgress"`
+	Failed     int `json:"failed"`
+}
+
+func (s BuildStats) Total() int {
+	return s.Successful + s.InProgress + s.Failed
+}
+
+// GetCommitBuildStatuses lists the builds reported for a commit.
+func (c *BitbucketClient) GetCommitBuildStatuses(ctx context.Context, commitID string) ([]BuildStatus, error) {
+	path := fmt.Sprintf("/rest/build-status/1.0/commits/%s", commitID)
+
+	var all []BuildStatus
+	params := url.Values{}
+	params.Set("limit", "100")
+	for start := 0; ; {
+		params.Set("start", strconv.Itoa(start))
+
+		var page struct {
+			Values        []BuildStatus `json:"values"`
+			IsLastPage    bool          `json:"isLastPage"`
+			NextPageStart int           `json:"nextPageStart"`
+		}
+		if err := c.Get(ctx, path, params, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Values...)
+		if page.IsLastPage || len(page.Values) == 0 {
+			return all, nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+// GetBuildStats counts the builds of several commits in one request,
+// keyed by commit ID.
+func (c *BitbucketClient) GetBuildStats(ctx context.Context, commitIDs []string) (map[string]BuildStats, error) {
+	stats := map[string]BuildStats{}
+	if len(commitIDs) == 0 {
+		return stats, nil
+	}
+	if err := c.Post(ctx, "/rest/build-status/1.0/commits/stats", commitIDs, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetBuildStats(t *testing.T) {
+	var gotPath string
+	var gotBody []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"abc":{"successful":2,"inProgress":1,"failed":0},"def":{"successful":0,"inProgress":0,"failed":1}}`))
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	stats, err := client.GetBuildStats(context.Background(), []string{"abc", "def"})
+	if err != nil {
+		t.Fatalf("GetBuildStats returned error: %v", err)
+	}
+
+	if gotPath != "/rest/build-status/1.0/commits/stats" || !reflect.DeepEqual(gotBody, []string{"abc", "def"}) {
+		t.Errorf("request = %s %v", gotPath, gotBody)
+	}
+	if stats["abc"].Total() != 3 || stats["def"].Failed != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 )
@@ -136,6 +138,29 @@ func (c *BitbucketClient) DiscardPendingReview(ctx context.Context, project, rep
 	return c.Delete(ctx, path)
 }
 
+// ReviewCompletion finishes the authenticated user's review: its pending
+// comments are published, with an optional summary comment and a new
+// participant status (APPROVED, NEEDS_WORK or UNAPPROVED; empty keeps the
+// current one).
+type ReviewCompletion struct {
+	CommentText        string `json:"commentText,omitempty"`
+	ParticipantStatus  string `json:"participantStatus,omitempty"`
+	LastReviewedCommit string `json:"lastReviewedCommit,omitempty"`
+}
+
+// CompleteReview publishes the pending review. The endpoint exists since
+// Bitbucket 8.0; older servers answer 404 or 405.
+func (c *BitbucketClient) CompleteReview(ctx context.Context, project, repo string, prID int, review ReviewCompletion) error {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/review", project, repo, prID)
+
+	err := c.Put(ctx, path, review, nil)
+	var unexpected *ErrUnexpectedResponse
+	if errors.As(err, &unexpected) && (unexpected.StatusCode == http.StatusNotFound || unexpected.StatusCode == http.StatusMethodNotAllowed) {
+		return fmt.Errorf("%w (completing a review needs Bitbucket 8.0 or later)", err)
+	}
+	return err
+}
+
 // GetPullRequestComment returns a single comment, including the version
 // required by update and delete.
 func (c *BitbucketClient) GetPullRequestComment(ctx context.Context, project, repo string, prID, commentID int) (*Comment, error) {
@@ -177,3 +202,58 @@ func (c *BitbucketClient) DeletePullRequestComment(ctx context.Context, project,
 	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/comments/%d?version=%d", project, repo, prID, commentID, version)
 	return c.Delete(ctx, path)
 }
+
+// SetPullRequestCommentState resolves (RESOLVED) or reopens (OPEN) a task at
+// the given version.
+func (c *BitbucketClient) SetPullRequestCommentState(ctx context.Context, project, repo string, prID, commentID, version int, state string) (*Comment, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/comments/%d", project, repo, prID, commentID)
+	body := struct {
+		State   string `json:"state"`
+		Version int    `json:"version"`
+	}{State: state, Version: version}
+
+	var comment Comment
+	if err := c.Put(ctx, path, body, &comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// SetPullRequestThreadResolved resolves or reopens the thread a root comment
+// starts, at the given version. Thread resolution exists since Bitbucket 8.0.
+func (c *BitbucketClient) SetPullRequestThreadResolved(ctx context.Context, project, repo string, prID, commentID, version int, resolved bool) (*Comment, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/comments/%d", project, repo, prID, commentID)
+	body := struct {
+		ThreadResolved bool `json:"threadResolved"`
+		Version        int  `json:"version"`
+	}{ThreadResolved: resolved, Version: version}
+
+	var comment Comment
+	if err := c.Put(ctx, path, body, &comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// GetBlockerComments returns up to limit tasks (BLOCKER comments) of a pull
+// request, all of them when limit is 0. state is OPEN, RESOLVED or empty
+// for both.
+func (c *BitbucketClient) GetBlockerComments(ctx context.Context, project, repo string, prID int, state string, limit int) ([]Comment, error) {
+	params := url.Values{}
+	if state != "" {
+		params.Set("state", state)
+	}
+
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/blocker-comments", project, repo, prID)
+	return getPages[Comment](ctx, c, path, params, limit)
+}
+
+// AddCommentReaction reacts to a comment with an emoticon given by its short
+// name, e.g. "thumbsup".
+func (c *BitbucketClient) AddCommentReaction(ctx context.Context, project, repo string, prID, commentID int, emoticon string) error {
+	path := fmt.Sprintf("/rest/comment-likes/latest/projects/%s/repos/%s/pull-requests/%d/comments/%d/reactions/%s",
+		project, repo, prID, commentID, url.PathEscape(emoticon))
+	return c.Put(ctx, path, nil, nil)
+}
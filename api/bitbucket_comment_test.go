@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -131,3 +133,137 @@ func TestCommentAnchorLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestCompleteReview(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	err := client.CompleteReview(context.Background(), "MYPROJ", "myrepo", 140, ReviewCompletion{
+		CommentText:       "Two blockers, rest is nits",
+		ParticipantStatus: "NEEDS_WORK",
+	})
+	if err != nil {
+		t.Fatalf("CompleteReview returned error: %v", err)
+	}
+
+	wantPath := "/rest/api/1.0/projects/MYPROJ/repos/myrepo/pull-requests/140/review"
+	if gotMethod != http.MethodPut || gotPath != wantPath {
+		t.Errorf("request = %s %s, want PUT %s", gotMethod, gotPath, wantPath)
+	}
+	if gotBody["commentText"] != "Two blockers, rest is nits" || gotBody["participantStatus"] != "NEEDS_WORK" {
+		t.Errorf("body = %v", gotBody)
+	}
+	if _, ok := gotBody["lastReviewedCommit"]; ok {
+		t.Errorf("empty lastReviewedCommit should be omitted: %v", gotBody)
+	}
+}
+
+func TestCompleteReviewOldServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	err := client.CompleteReview(context.Background(), "MYPROJ", "myrepo", 140, ReviewCompletion{})
+	if err == nil || !strings.Contains(err.Error(), "Bitbucket 8.0") {
+		t.Errorf("error = %v, want a Bitbucket 8.0 hint", err)
+	}
+}
+
+func TestSetPullRequestCommentState(t *testing.T) {
+	var gotBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/rest/api/1.0/projects/MYPROJ/repos/myrepo/pull-requests/140/comments/331" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"id":331,"version":4,"state":"RESOLVED","severity":"BLOCKER"}`))
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	comment, err := client.SetPullRequestCommentState(context.Background(), "MYPROJ", "myrepo", 140, 331, 3, CommentStateResolved)
+	if err != nil {
+		t.Fatalf("SetPullRequestCommentState returned error: %v", err)
+	}
+	if comment.State != CommentStateResolved || comment.Version != 4 {
+		t.Errorf("comment = %+v", comment)
+	}
+	if gotBody["state"] != CommentStateResolved || gotBody["version"] != float64(3) {
+		t.Errorf("body = %v", gotBody)
+	}
+}
+
+func TestGetBlockerComments(t *testing.T) {
+	var gotPath, gotState string
+	var pages []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotState = r.URL.Path, r.URL.Query().Get("state")
+		pages = append(pages, r.URL.Query().Get("start"))
+		if r.URL.Query().Get("start") == "0" {
+			_, _ = w.Write([]byte(`{"values":[{"id":7,"severity":"BLOCKER","state":"OPEN","text":"add a test"}],"isLastPage":false,"nextPageStart":1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"values":[{"id":9,"severity":"BLOCKER","state":"OPEN","text":"fix the docs"}],"isLastPage":true}`))
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	tasks, err := client.GetBlockerComments(context.Background(), "MYPROJ", "myrepo", 140, CommentStateOpen, 0)
+	if err != nil {
+		t.Fatalf("GetBlockerComments returned error: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != 7 || tasks[1].ID != 9 {
+		t.Errorf("tasks = %+v, want both pages", tasks)
+	}
+	if gotPath != "/rest/api/1.0/projects/MYPROJ/repos/myrepo/pull-requests/140/blocker-comments" || gotState != CommentStateOpen {
+		t.Errorf("request = %s state=%s", gotPath, gotState)
+	}
+	if !reflect.DeepEqual(pages, []string{"0", "1"}) {
+		t.Errorf("pages = %v, want [0 1]", pages)
+	}
+}
+
+func TestAddCommentReaction(t *testing.T) {
+	var gotMethod, gotPath string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	if err := client.AddCommentReaction(context.Background(), "MYPROJ", "myrepo", 140, 331, "thumbsup"); err != nil {
+		t.Fatalf("AddCommentReaction returned error: %v", err)
+	}
+	wantPath := "/rest/comment-likes/latest/projects/MYPROJ/repos/myrepo/pull-requests/140/comments/331/reactions/thumbsup"
+	if gotMethod != http.MethodPut || gotPath != wantPath {
+		t.Errorf("request = %s %s, want PUT %s", gotMethod, gotPath, wantPath)
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Dashboard roles: how the authenticated user is involved in a pull request.
+const (
+	RoleAuthor      = "AUTHOR"
+	RoleReviewer    = "REVIEWER"
+	RoleParticipant = "PARTICIPANT"
+)
+
+// GetDashboardPullRequests lists the pull requests the authenticated user is
+// involved in with role, across every repository. state is OPEN, MERGED,
+// DECLINED or empty for all.
+func (c *BitbucketClient) GetDashboardPullRequests(ctx context.Context, role, state string, limit int) ([]PullRequest, error) {
+	params := url.Values{}
+	if role != "" {
+		params.Set("role", role)
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+	params.Set("order", "NEWEST")
+	return getPages[PullRequest](ctx, c, "/rest/api/1.0/dashboard/pull-requests", params, limit)
+}
+
+// GetInboxPullRequests lists the open pull requests waiting for the
+// authenticated user's review.
+func (c *BitbucketClient) GetInboxPullRequests(ctx context.Context, limit int) ([]PullRequest, error) {
+	params := url.Values{}
+	params.Set("role", RoleReviewer)
+	return getPages[PullRequest](ctx, c, "/rest/api/1.0/inbox/pull-requests", params, limit)
+}
+
+// getPages follows nextPageStart until limit values are collected (all of
+// them when limit is 0) or the last page is reached.
+func getPages[T any](ctx context.Context, c *BitbucketClient, path string, params url.Values, limit int) ([]T, error) {
+	var all []T
+	for start := 0; ; {
+		pageSize := 100
+		if limit > 0 {
+			pageSize = min(limit-len(all), pageSize)
+		}
+		params.Set("start", strconv.Itoa(start))
+		params.Set("limit", strconv.Itoa(pageSize))
+
+		var page struct {
+			Values        []T  `json:"values"`
+			IsLastPage    bool `json:"isLastPage"`
+			NextPageStart int  `json:"nextPageStart"`
+		}
+		if err := c.Get(ctx, path, params, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Values...)
+		if page.IsLastPage || len(page.Values) == 0 || (limit > 0 && len(all) >= limit) {
+			return all, nil
+		}
+		start = page.NextPageStart
+	}
+}
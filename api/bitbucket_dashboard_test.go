@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetDashboardPullRequestsPages(t *testing.T) {
+	var starts []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/dashboard/pull-requests" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("role") != RoleReviewer || q.Get("state") != "OPEN" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		starts = append(starts, q.Get("start"))
+		if q.Get("start") == "0" {
+			fmt.Fprint(w, `{"values":[{"id":1},{"id":2}],"isLastPage":false,"nextPageStart":2}`)
+			return
+		}
+		fmt.Fprint(w, `{"values":[{"id":3,"properties":{"openTaskCount":2}}],"isLastPage":true}`)
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	prs, err := client.GetDashboardPullRequests(context.Background(), RoleReviewer, "OPEN", 0)
+	if err != nil {
+		t.Fatalf("GetDashboardPullRequests returned error: %v", err)
+	}
+	if len(prs) != 3 || prs[2].Properties.OpenTaskCount != 2 {
+		t.Errorf("prs = %+v", prs)
+	}
+	if len(starts) != 2 || starts[1] != "2" {
+		t.Errorf("page starts = %v, want [0 2]", starts)
+	}
+}
+
+func TestGetInboxPullRequestsStopsAtLimit(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/rest/api/1.0/inbox/pull-requests" || r.URL.Query().Get("limit") != "2" {
+			t.Errorf("request = %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"values":[{"id":1},{"id":2}],"isLastPage":false,"nextPageStart":2}`)
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	prs, err := client.GetInboxPullRequests(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("GetInboxPullRequests returned error: %v", err)
+	}
+	if len(prs) != 2 || requests != 1 {
+		t.Errorf("got %d PRs in %d requests, want 2 in 1", len(prs), requests)
+	}
+}
@@ -59,6 +59,11 @@ type FileDiff struct {
 	Destination *Path  `json:"destination"`
 	Hunks       []Hunk `json:"hunks"`
 	Truncated   bool   `json:"truncated"`
+	Binary      bool   `json:"binary,omitempty"`
+
+	// LineComments are the open comments anchored to lines of this file;
+	// DiffLine.CommentIDs says which line each belongs to.
+	LineComments []Comment `json:"lineComments,omitempty"`
 }
 
 type Hunk struct {
@@ -82,6 +87,7 @@ type DiffLine struct {
 	Destination int    `json:"destination"`
 	Line        string `json:"line"`
 	Truncated   bool   `json:"truncated"`
+	CommentIDs  []int  `json:"commentIds,omitempty"`
 }
 
 // GetPullRequestDiffJSON fetches the effective diff as structured JSON.
@@ -141,6 +147,21 @@ func (f *FileDiff) Path() string {
 	return ""
 }
 
+// Stat counts the added and removed lines of the file.
+func (f *FileDiff) Stat() (added, removed int) {
+	for _, h := range f.Hunks {
+		for _, s := range h.Segments {
+			switch s.Type {
+			case SegmentAdded:
+				added += len(s.Lines)
+			case SegmentRemoved:
+				removed += len(s.Lines)
+			}
+		}
+	}
+	return added, removed
+}
+
 // SrcPath is the pre-change path, set only for copies and moves.
 func (f *FileDiff) SrcPath() string {
 	if f.Source == nil || f.Destination == nil {
@@ -273,3 +273,21 @@ func TestGetPullRequestDiffJSONRequestsContextLines(t *testing.T) {
 		t.Errorf("decoded %d file diffs, want 2", len(diff.Diffs))
 	}
 }
+
+func TestFileDiffStat(t *testing.T) {
+	diff := testDiff(t)
+
+	tests := []struct {
+		path           string
+		added, removed int
+	}{
+		{"src/app.js", 2, 1},
+		{"src/new/toolbar.vue", 1, 0},
+	}
+	for _, tt := range tests {
+		added, removed := diff.FindFile(tt.path).Stat()
+		if added != tt.added || removed != tt.removed {
+			t.Errorf("%s: Stat() = +%d -%d, want +%d -%d", tt.path, added, removed, tt.added, tt.removed)
+		}
+	}
+}
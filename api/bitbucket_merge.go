@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// MergeStrategy is one of a repository's merge strategies. IDs are no-ff
+// (merge commit), ff, ff-only, squash, squash-ff-only, rebase-no-ff and
+// rebase-ff-only.
+type MergeStrategy struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// MergeConfig is the set of strategies a repository allows and the one
+// used when none is requested.
+type MergeConfig struct {
+	DefaultStrategy *MergeStrategy  `json:"defaultStrategy"`
+	Strategies      []MergeStrategy `json:"strategies"`
+}
+
+// MergeOptions customizes a merge. Empty fields use the repository's
+// default strategy and message.
+type MergeOptions struct {
+	Message    string `json:"message,omitempty"`
+	StrategyID string `json:"strategyId,omitempty"`
+}
+
+// GetMergeConfig returns the merge strategies in effect for a repository,
+// whether set on the repository, its project or globally.
+func (c *BitbucketClient) GetMergeConfig(ctx context.Context, project, repo string) (*MergeConfig, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/settings/pull-requests", project, repo)
+
+	var settings struct {
+		MergeConfig MergeConfig `json:"mergeConfig"`
+	}
+	if err := c.Get(ctx, path, nil, &settings); err != nil {
+		return nil, err
+	}
+	return &settings.MergeConfig, nil
+}
+
+func (c *BitbucketClient) MergePullRequest(ctx context.Context, project, repo string, prID int, version int, opts MergeOptions) error {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/merge?version=%d", project, repo, prID, version)
+	return c.Post(ctx, path, opts, nil)
+}
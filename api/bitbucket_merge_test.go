@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMergePullRequestSendsStrategy(t *testing.T) {
+	var gotQuery string
+	var gotBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"id":140,"state":"MERGED"}`))
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	err := client.MergePullRequest(context.Background(), "MYPROJ", "myrepo", 140, 7, MergeOptions{Message: "Squashed", StrategyID: "squash"})
+	if err != nil {
+		t.Fatalf("MergePullRequest returned error: %v", err)
+	}
+	if gotQuery != "version=7" {
+		t.Errorf("query = %q", gotQuery)
+	}
+	if gotBody["message"] != "Squashed" || gotBody["strategyId"] != "squash" {
+		t.Errorf("body = %v", gotBody)
+	}
+}
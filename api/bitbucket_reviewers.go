@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+func (c *BitbucketClient) GetRepository(ctx context.Context, project, repo string) (*Repository, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s", project, repo)
+
+	var repository Repository
+	if err := c.Get(ctx, path, nil, &repository); err != nil {
+		return nil, err
+	}
+	return &repository, nil
+}
+
+// GetDefaultReviewers returns the reviewers the repository's default
+// reviewer conditions require for a pull request from sourceRef of
+// sourceRepoID into targetRef of project/repo. Refs are full names such as
+// refs/heads/main. A disabled default reviewers plugin (404) yields none.
+func (c *BitbucketClient) GetDefaultReviewers(ctx context.Context, project, repo string, sourceRepoID, targetRepoID int, sourceRef, targetRef string) ([]User, error) {
+	path := fmt.Sprintf("/rest/default-reviewers/1.0/projects/%s/repos/%s/reviewers", project, repo)
+
+	params := url.Values{}
+	params.Set("sourceRepoId", strconv.Itoa(sourceRepoID))
+	params.Set("targetRepoId", strconv.Itoa(targetRepoID))
+	params.Set("sourceRefId", sourceRef)
+	params.Set("targetRefId", targetRef)
+
+	var users []User
+	if err := c.Get(ctx, path, params, &users); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetDefaultReviewers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/default-reviewers/1.0/projects/PROJ/repos/app/reviewers" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("sourceRepoId") != "12" || q.Get("targetRepoId") != "3" ||
+			q.Get("sourceRefId") != "refs/heads/fix" || q.Get("targetRefId") != "refs/heads/main" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[{"name":"alice","displayName":"Alice"},{"name":"bob"}]`)
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	users, err := client.GetDefaultReviewers(context.Background(), "PROJ", "app", 12, 3, "refs/heads/fix", "refs/heads/main")
+	if err != nil {
+		t.Fatalf("GetDefaultReviewers returned error: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "alice" || users[1].Name != "bob" {
+		t.Errorf("users = %+v", users)
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreatePullRequestFromFork(t *testing.T) {
+	var body struct {
+		FromRef struct {
+			ID         string     `json:"id"`
+			Repository Repository `json:"repository"`
+		} `json:"fromRef"`
+		ToRef struct {
+			ID         string     `json:"id"`
+			Repository Repository `json:"repository"`
+		} `json:"toRef"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/1.0/projects/PROJ/repos/app/pull-requests" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding body: %v", err)
+		}
+		w.Write([]byte(`{"id":7}`))
+	}))
+	defer server.Close()
+
+	client := NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	pr, err := client.CreatePullRequestFrom(context.Background(), "~jdoe", "app", "PROJ", "app", "Fix", "", "fix-login", "main", nil)
+	if err != nil {
+		t.Fatalf("CreatePullRequestFrom returned error: %v", err)
+	}
+	if pr.ID != 7 {
+		t.Errorf("pr.ID = %d, want 7", pr.ID)
+	}
+	if body.FromRef.ID != "refs/heads/fix-login" || body.FromRef.Repository.Project.Key != "~jdoe" || body.FromRef.Repository.Slug != "app" {
+		t.Errorf("fromRef = %+v", body.FromRef)
+	}
+	if body.ToRef.ID != "refs/heads/main" || body.ToRef.Repository.Project.Key != "PROJ" {
+		t.Errorf("toRef = %+v", body.ToRef)
+	}
+}
+
+func TestRepositoryCloneURL(t *testing.T) {
+	var repo Repository
+	if err := json.Unmarshal([]byte(`{"slug":"app","links":{"clone":[
+		{"href":"https://git.example.com/scm/~jdoe/app.git","name":"http"},
+		{"href":"ssh://git@git.example.com:7999/~jdoe/app.git","name":"ssh"}]}}`), &repo); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.CloneURL("ssh"); got != "ssh://git@git.example.com:7999/~jdoe/app.git" {
+		t.Errorf("CloneURL(ssh) = %q", got)
+	}
+	if got := repo.CloneURL("git"); got != "https://git.example.com/scm/~jdoe/app.git" {
+		t.Errorf("CloneURL(git) = %q, want the first link", got)
+	}
+	if got := (Repository{}).CloneURL("ssh"); got != "" {
+		t.Errorf("CloneURL without links = %q", got)
+	}
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// SearchUsers finds users whose username, display name or email contains
+// filter.
+func (c *BitbucketClient) SearchUsers(ctx context.Context, filter string, limit int) ([]User, error) {
+	params := url.Values{}
+	params.Set("filter", filter)
+	params.Set("limit", strconv.Itoa(limit))
+
+	var response struct {
+		Values []User `json:"values"`
+	}
+
+	err := c.Get(ctx, "/rest/api/1.0/users", params, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Values, nil
+}
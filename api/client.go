@@ -37,6 +37,23 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body io.Rea
 }
 
 func (c *Client) doRequestWithAccept(ctx context.Context, method, path string, body io.Reader, accept string) (*http.Response, error) {
+	headers := map[string]string{"Content-Type": "application/json"}
+	if accept != "" {
+		headers["Accept"] = accept
+	}
+	return c.send(ctx, c.HTTPClient, method, path, body, headers)
+}
+
+// doTransfer sends an authenticated request with exactly the given headers
+// for uploads and downloads, whose duration grows with their size:
+// HTTPClient's whole-request timeout does not apply, only ctx bounds them.
+func (c *Client) doTransfer(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	hc := *c.HTTPClient
+	hc.Timeout = 0
+	return c.send(ctx, &hc, method, path, body, headers)
+}
+
+func (c *Client) send(ctx context.Context, hc *http.Client, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
 	url := c.BaseURL + path
 
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
@@ -50,12 +67,11 @@ func (c *Client) doRequestWithAccept(ctx context.Context, method, path string, b
 		auth := base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Token))
 		req.Header.Set("Authorization", "Basic "+auth)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	if accept != "" {
-		req.Header.Set("Accept", accept)
+	for k, v := range headers {
+		req.Header.Set(k, v)
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := hc.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
 	}
@@ -2,9 +2,13 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+
+	"github.com/lroolle/atlas-cli/pkg/converter"
 )
 
 type JiraClient struct {
@@ -21,28 +25,100 @@ func NewJiraClient(baseURL, username, token string) *JiraClient {
 	}
 }
 
+// IsCloud reports whether the client talks to JIRA Cloud.
+func (c *JiraClient) IsCloud() bool {
+	return c.InstallationType == InstallationTypeCloud
+}
+
+// richTextAPI returns the REST API root for endpoints that carry rich
+// text. Cloud's v3 API sends it as Atlassian Document Format; Server and
+// Data Center only have v2, which uses wiki markup strings.
+func (c *JiraClient) richTextAPI() string {
+	if c.IsCloud() {
+		return "/rest/api/3"
+	}
+	return "/rest/api/2"
+}
+
 type Issue struct {
-	ID     string      `json:"id"`
-	Key    string      `json:"key"`
-	Self   string      `json:"self"`
-	Fields IssueFields `json:"fields"`
+	ID        string      `json:"id"`
+	Key       string      `json:"key"`
+	Self      string      `json:"self"`
+	Fields    IssueFields `json:"fields"`
+	Changelog *Changelog  `json:"changelog,omitempty"`
 }
 
 type IssueFields struct {
-	Summary     string      `json:"summary"`
-	Description string      `json:"description"`
-	Status      Status      `json:"status"`
-	Priority    Priority    `json:"priority"`
-	IssueType   IssueType   `json:"issuetype"`
-	Assignee    *JiraUser   `json:"assignee"`
-	Reporter    JiraUser    `json:"reporter"`
-	Created     string      `json:"created"`
-	Updated     string      `json:"updated"`
-	Resolution  *Resolution `json:"resolution"`
-	Project     JiraProject `json:"project"`
-	IssueLinks  []IssueLink `json:"issuelinks,omitempty"`
-	Parent      *Issue      `json:"parent,omitempty"`
-	Subtasks    []Issue     `json:"subtasks,omitempty"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Status      Status       `json:"status"`
+	Priority    Priority     `json:"priority"`
+	IssueType   IssueType    `json:"issuetype"`
+	Assignee    *JiraUser    `json:"assignee"`
+	Reporter    JiraUser     `json:"reporter"`
+	Created     string       `json:"created"`
+	Updated     string       `json:"updated"`
+	Resolved    string       `json:"resolutiondate,omitempty"`
+	Resolution  *Resolution  `json:"resolution"`
+	Project     JiraProject  `json:"project"`
+	IssueLinks  []IssueLink  `json:"issuelinks,omitempty"`
+	Parent      *Issue       `json:"parent,omitempty"`
+	Subtasks    []Issue      `json:"subtasks,omitempty"`
+	Attachments []Attachment `json:"attachment,omitempty"`
+	Labels      []string     `json:"labels,omitempty"`
+	Components  []Component  `json:"components,omitempty"`
+	FixVersions []Version    `json:"fixVersions,omitempty"`
+
+	// Custom holds the raw customfield_* values, whose IDs and shapes
+	// differ per server.
+	Custom map[string]json.RawMessage `json:"-"`
+}
+
+func (f *IssueFields) UnmarshalJSON(data []byte) error {
+	type plain IssueFields
+	if err := json.Unmarshal(data, (*plain)(f)); err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for id, raw := range all {
+		if !strings.HasPrefix(id, "customfield_") || string(raw) == "null" {
+			continue
+		}
+		if f.Custom == nil {
+			f.Custom = make(map[string]json.RawMessage)
+		}
+		f.Custom[id] = raw
+	}
+	return nil
+}
+
+// CustomNumber returns a numeric custom field such as story points.
+func (f IssueFields) CustomNumber(id string) (float64, bool) {
+	var n float64
+	if raw, ok := f.Custom[id]; !ok || json.Unmarshal(raw, &n) != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+type Component struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type Version struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	StartDate   string `json:"startDate,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	Overdue     bool   `json:"overdue,omitempty"`
 }
 
 type JiraProject struct {
@@ -51,18 +127,31 @@ type JiraProject struct {
 	Name string `json:"name"`
 }
 
+// JiraUser identifies a user by Name (and Key) on Server and by
+// AccountID on Cloud, which hides names.
 type JiraUser struct {
 	Name         string `json:"name"`
+	Key          string `json:"key,omitempty"`
+	AccountID    string `json:"accountId,omitempty"`
 	EmailAddress string `json:"emailAddress"`
 	DisplayName  string `json:"displayName"`
 	Active       bool   `json:"active"`
+	TimeZone     string `json:"timeZone,omitempty"`
 }
 
 type Status struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	IconURL     string `json:"iconUrl"`
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	IconURL        string          `json:"iconUrl"`
+	StatusCategory *StatusCategory `json:"statusCategory,omitempty"`
+}
+
+// StatusCategory is the workflow-independent bucket of a status: "new"
+// (To Do), "indeterminate" (In Progress) or "done".
+type StatusCategory struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
 }
 
 type Priority struct {
@@ -106,15 +195,17 @@ type Transition struct {
 }
 
 type TransitionField struct {
-	Required      bool             `json:"required"`
-	Name          string           `json:"name"`
-	Schema        TransitionSchema `json:"schema"`
-	AllowedValues []AllowedValue   `json:"allowedValues,omitempty"`
+	Required        bool             `json:"required"`
+	Name            string           `json:"name"`
+	Schema          TransitionSchema `json:"schema"`
+	AllowedValues   []AllowedValue   `json:"allowedValues,omitempty"`
+	HasDefaultValue bool             `json:"hasDefaultValue,omitempty"`
 }
 
 type TransitionSchema struct {
-	Type  string `json:"type"`
-	Items string `json:"items,omitempty"`
+	Type   string `json:"type"`
+	Items  string `json:"items,omitempty"`
+	Custom string `json:"custom,omitempty"`
 }
 
 type AllowedValue struct {
@@ -139,20 +230,32 @@ type SearchResult struct {
 	Issues     []Issue `json:"issues"`
 }
 
+// SearchIssues runs a JQL search for up to maxResults issues. The server
+// caps each response (100 issues on Cloud), so the search is paged on
+// startAt until maxResults or the total is reached.
 func (c *JiraClient) SearchIssues(ctx context.Context, jql string, maxResults int) ([]Issue, error) {
 	params := url.Values{}
 	params.Set("jql", jql)
-	params.Set("maxResults", strconv.Itoa(maxResults))
+	return c.searchPaged(ctx, "/rest/api/2/search", params, maxResults)
+}
 
-	path := "/rest/api/2/search"
+// searchPaged pages an issue search endpoint (/search or the agile issue
+// lists) with params for up to maxResults issues.
+func (c *JiraClient) searchPaged(ctx context.Context, path string, params url.Values, maxResults int) ([]Issue, error) {
+	var issues []Issue
+	for {
+		params.Set("startAt", strconv.Itoa(len(issues)))
+		params.Set("maxResults", strconv.Itoa(maxResults-len(issues)))
 
-	var result SearchResult
-	err := c.Get(ctx, path, params, &result)
-	if err != nil {
-		return nil, err
+		var result SearchResult
+		if err := c.Get(ctx, path, params, &result); err != nil {
+			return nil, err
+		}
+		issues = append(issues, result.Issues...)
+		if len(result.Issues) == 0 || len(issues) >= maxResults || len(issues) >= result.Total {
+			return issues, nil
+		}
 	}
-
-	return result.Issues, nil
 }
 
 func (c *JiraClient) GetIssue(ctx context.Context, issueKey string) (*Issue, error) {
@@ -227,43 +330,6 @@ func (c *JiraClient) TransitionIssue(ctx context.Context, issueKey string, trans
 	return c.Post(ctx, path, TransitionBody(transitionID, fields, update), nil)
 }
 
-func (c *JiraClient) AddComment(ctx context.Context, issueKey string, comment string) error {
-	path := fmt.Sprintf("/rest/api/2/issue/%s/comment", issueKey)
-
-	body := map[string]string{
-		"body": comment,
-	}
-
-	return c.Post(ctx, path, body, nil)
-}
-
-type JiraComment struct {
-	ID      string   `json:"id"`
-	Body    string   `json:"body"`
-	Author  JiraUser `json:"author"`
-	Created string   `json:"created"`
-	Updated string   `json:"updated"`
-}
-
-type CommentsResponse struct {
-	StartAt    int           `json:"startAt"`
-	MaxResults int           `json:"maxResults"`
-	Total      int           `json:"total"`
-	Comments   []JiraComment `json:"comments"`
-}
-
-func (c *JiraClient) GetComments(ctx context.Context, issueKey string) ([]JiraComment, error) {
-	path := fmt.Sprintf("/rest/api/2/issue/%s/comment", issueKey)
-
-	var response CommentsResponse
-	err := c.Get(ctx, path, nil, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return response.Comments, nil
-}
-
 type DevelopmentInfo struct {
 	Detail []struct {
 		PullRequests []struct {
@@ -332,13 +398,72 @@ type CreatedIssue struct {
 	Self string `json:"self"`
 }
 
+// adfSystemFields are the system fields that take ADF documents in the v3
+// API.
+var adfSystemFields = []string{"description", "environment"}
+
+// textareaSchema is the custom field type of multi-line text fields, which
+// take ADF documents in the v3 API.
+const textareaSchema = "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+
+// richTextFields returns the IDs of the fields that take ADF in the v3 API.
+// If the field list cannot be read only the system fields are known.
+func (c *JiraClient) richTextFields(ctx context.Context) map[string]bool {
+	ids := make(map[string]bool)
+	for _, name := range adfSystemFields {
+		ids[name] = true
+	}
+	fields, err := c.GetFields(ctx)
+	if err != nil {
+		return ids
+	}
+	for _, f := range fields {
+		if f.Schema.Type == "string" && f.Schema.Custom == textareaSchema {
+			ids[f.ID] = true
+		}
+	}
+	return ids
+}
+
+// toADFFields returns a copy of fields with the string values of rich text
+// fields read as Markdown and converted to ADF.
+func (c *JiraClient) toADFFields(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	hasText := false
+	for _, v := range fields {
+		if _, ok := v.(string); ok {
+			hasText = true
+		}
+	}
+	if !hasText {
+		return fields
+	}
+
+	richText := c.richTextFields(ctx)
+	converted := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if text, ok := v.(string); ok && richText[k] {
+			converted[k] = converter.MarkdownToADF(text)
+			continue
+		}
+		converted[k] = v
+	}
+	return converted
+}
+
+// CreateIssue creates an issue from a fields map. Rich text fields are
+// wiki markup on Server; on Cloud they are read as Markdown and sent as
+// ADF.
 func (c *JiraClient) CreateIssue(ctx context.Context, fields map[string]interface{}) (*CreatedIssue, error) {
+	if c.IsCloud() {
+		fields = c.toADFFields(ctx, fields)
+	}
+
 	body := map[string]interface{}{
 		"fields": fields,
 	}
 
 	var created CreatedIssue
-	err := c.Post(ctx, "/rest/api/2/issue", body, &created)
+	err := c.Post(ctx, c.richTextAPI()+"/issue", body, &created)
 	if err != nil {
 		return nil, err
 	}
@@ -366,8 +491,13 @@ func (c *JiraClient) GetFields(ctx context.Context) ([]JiraField, error) {
 	return fields, nil
 }
 
+// UpdateIssue sets fields of an issue. Rich text fields are read the same
+// way as in CreateIssue.
 func (c *JiraClient) UpdateIssue(ctx context.Context, issueKey string, fields map[string]interface{}) error {
-	path := fmt.Sprintf("/rest/api/2/issue/%s", issueKey)
+	path := fmt.Sprintf("%s/issue/%s", c.richTextAPI(), issueKey)
+	if c.IsCloud() {
+		fields = c.toADFFields(ctx, fields)
+	}
 
 	body := map[string]interface{}{
 		"fields": fields,
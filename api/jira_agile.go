@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type Board struct {
+	ID       int           `json:"id"`
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
+	Location BoardLocation `json:"location"`
+}
+
+type BoardLocation struct {
+	ProjectKey  string `json:"projectKey"`
+	ProjectName string `json:"projectName"`
+}
+
+type Sprint struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	State         string `json:"state"`
+	Goal          string `json:"goal,omitempty"`
+	StartDate     string `json:"startDate,omitempty"`
+	EndDate       string `json:"endDate,omitempty"`
+	CompleteDate  string `json:"completeDate,omitempty"`
+	OriginBoardID int    `json:"originBoardId,omitempty"`
+}
+
+// agilePage is the paging envelope of the /rest/agile/1.0 list endpoints.
+type agilePage[T any] struct {
+	StartAt    int  `json:"startAt"`
+	MaxResults int  `json:"maxResults"`
+	IsLast     bool `json:"isLast"`
+	Values     []T  `json:"values"`
+}
+
+// getAgilePages follows startAt until the server reports the last page.
+func getAgilePages[T any](ctx context.Context, c *JiraClient, path string, params url.Values) ([]T, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+
+	var all []T
+	for {
+		params.Set("startAt", strconv.Itoa(len(all)))
+
+		var page agilePage[T]
+		if err := c.Get(ctx, path, params, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Values...)
+
+		if page.IsLast || len(page.Values) == 0 {
+			return all, nil
+		}
+	}
+}
+
+// GetBoards lists agile boards, optionally restricted to a project and to
+// boards whose name contains name.
+func (c *JiraClient) GetBoards(ctx context.Context, project, name string) ([]Board, error) {
+	params := url.Values{}
+	if project != "" {
+		params.Set("projectKeyOrId", project)
+	}
+	if name != "" {
+		params.Set("name", name)
+	}
+	return getAgilePages[Board](ctx, c, "/rest/agile/1.0/board", params)
+}
+
+// GetBoardSprints lists a board's sprints. states is any of future,
+// active and closed; empty means all.
+func (c *JiraClient) GetBoardSprints(ctx context.Context, boardID int, states ...string) ([]Sprint, error) {
+	path := fmt.Sprintf("/rest/agile/1.0/board/%d/sprint", boardID)
+
+	params := url.Values{}
+	if len(states) > 0 {
+		params.Set("state", strings.Join(states, ","))
+	}
+	return getAgilePages[Sprint](ctx, c, path, params)
+}
+
+func (c *JiraClient) GetSprint(ctx context.Context, sprintID int) (*Sprint, error) {
+	path := fmt.Sprintf("/rest/agile/1.0/sprint/%d", sprintID)
+
+	var sprint Sprint
+	if err := c.Get(ctx, path, nil, &sprint); err != nil {
+		return nil, err
+	}
+
+	return &sprint, nil
+}
+
+// GetSprintIssues returns up to maxResults issues in a sprint, including
+// sub-tasks.
+func (c *JiraClient) GetSprintIssues(ctx context.Context, sprintID int, maxResults int, fields ...string) ([]Issue, error) {
+	path := fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprintID)
+
+	params := url.Values{}
+	if len(fields) > 0 {
+		params.Set("fields", strings.Join(fields, ","))
+	}
+
+	return c.searchPaged(ctx, path, params, maxResults)
+}
+
+// agileMoveBatch is the most issues the agile API accepts per move request.
+const agileMoveBatch = 50
+
+// MoveIssuesToSprint moves issues into a sprint, removing them from any
+// other open sprint.
+func (c *JiraClient) MoveIssuesToSprint(ctx context.Context, sprintID int, issueKeys []string) error {
+	return c.postIssueBatches(ctx, fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprintID), issueKeys)
+}
+
+func (c *JiraClient) postIssueBatches(ctx context.Context, path string, issueKeys []string) error {
+	for start := 0; start < len(issueKeys); start += agileMoveBatch {
+		end := min(start+agileMoveBatch, len(issueKeys))
+		body := map[string][]string{"issues": issueKeys[start:end]}
+		if err := c.Post(ctx, path, body, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MoveIssuesToEpic sets the epic of the given issues. The agile API writes
+// the Epic Link field on Server/Data Center and the parent on Cloud.
+func (c *JiraClient) MoveIssuesToEpic(ctx context.Context, epicKey string, issueKeys []string) error {
+	return c.postIssueBatches(ctx, fmt.Sprintf("/rest/agile/1.0/epic/%s/issue", epicKey), issueKeys)
+}
+
+// RemoveIssuesFromEpic clears the epic of the given issues.
+func (c *JiraClient) RemoveIssuesFromEpic(ctx context.Context, issueKeys []string) error {
+	return c.postIssueBatches(ctx, "/rest/agile/1.0/epic/none/issue", issueKeys)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"strings"
+)
+
+type Attachment struct {
+	ID       string   `json:"id"`
+	Filename string   `json:"filename"`
+	Author   JiraUser `json:"author"`
+	Created  string   `json:"created"`
+	Size     int64    `json:"size"`
+	MimeType string   `json:"mimeType"`
+	Content  string   `json:"content"`
+}
+
+// AddAttachment uploads r as filename. The body is streamed as multipart
+// form data; JIRA rejects uploads without the XSRF opt-out header.
+func (c *JiraClient) AddAttachment(ctx context.Context, issueKey, filename string, r io.Reader) ([]Attachment, error) {
+	path := fmt.Sprintf("/rest/api/2/issue/%s/attachments", issueKey)
+
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	go func() {
+		part, err := form.CreateFormFile("file", filename)
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	resp, err := c.doTransfer(ctx, "POST", path, pr, map[string]string{
+		"Content-Type":      form.FormDataContentType(),
+		"Accept":            "application/json",
+		"X-Atlassian-Token": "no-check",
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		// Unblock the writer if the request failed before reading the body.
+		pr.CloseWithError(err)
+		return nil, err
+	}
+	pr.Close()
+
+	var attachments []Attachment
+	if err := json.NewDecoder(resp.Body).Decode(&attachments); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return attachments, nil
+}
+
+func (c *JiraClient) GetAttachments(ctx context.Context, issueKey string) ([]Attachment, error) {
+	issue, err := c.GetIssueFields(ctx, issueKey, "attachment")
+	if err != nil {
+		return nil, err
+	}
+	return issue.Fields.Attachments, nil
+}
+
+// DownloadAttachment streams an attachment's content to w.
+func (c *JiraClient) DownloadAttachment(ctx context.Context, attachment Attachment, w io.Writer) error {
+	// Content is an absolute URL; requests are made relative to BaseURL.
+	path := strings.TrimPrefix(attachment.Content, c.BaseURL)
+	if path == attachment.Content {
+		path = fmt.Sprintf("/secure/attachment/%s/%s", attachment.ID, url.PathEscape(attachment.Filename))
+	}
+
+	resp, err := c.doTransfer(ctx, "GET", path, nil, nil)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Changelog is an issue's change history, included in issue and search
+// responses with expand=changelog.
+type Changelog struct {
+	StartAt    int             `json:"startAt"`
+	MaxResults int             `json:"maxResults"`
+	Total      int             `json:"total"`
+	Histories  []ChangeHistory `json:"histories"`
+}
+
+// ChangeHistory is one edit: the fields a user changed at one time.
+type ChangeHistory struct {
+	ID      string          `json:"id"`
+	Author  JiraUser        `json:"author"`
+	Created string          `json:"created"`
+	Items   []ChangelogItem `json:"items"`
+}
+
+// ChangelogItem is a change to one field. From and To hold IDs (status
+// IDs, usernames); the String variants hold what the UI shows.
+type ChangelogItem struct {
+	Field      string `json:"field"`
+	FieldType  string `json:"fieldtype"`
+	From       string `json:"from"`
+	FromString string `json:"fromString"`
+	To         string `json:"to"`
+	ToString   string `json:"toString"`
+}
+
+// historyFields are the fields timelines and cycle times need alongside
+// the changelog.
+var historyFields = []string{"summary", "status", "issuetype", "created", "resolutiondate"}
+
+// GetIssueWithChangelog fetches an issue with its full change history. Cloud
+// embeds at most 100 histories, so the rest is paged from /changelog.
+func (c *JiraClient) GetIssueWithChangelog(ctx context.Context, issueKey string) (*Issue, error) {
+	params := url.Values{}
+	params.Set("expand", "changelog")
+	params.Set("fields", strings.Join(historyFields, ","))
+
+	var issue Issue
+	if err := c.Get(ctx, "/rest/api/2/issue/"+issueKey, params, &issue); err != nil {
+		return nil, err
+	}
+	if issue.Changelog == nil || len(issue.Changelog.Histories) >= issue.Changelog.Total || !c.IsCloud() {
+		return &issue, nil
+	}
+
+	histories, err := c.getChangelog(ctx, issueKey, issue.Changelog.Total)
+	if err != nil {
+		return nil, err
+	}
+	issue.Changelog.Histories = histories
+	return &issue, nil
+}
+
+// getChangelog pages an issue's full change history from /changelog.
+func (c *JiraClient) getChangelog(ctx context.Context, issueKey string, total int) ([]ChangeHistory, error) {
+	histories := make([]ChangeHistory, 0, total)
+	for startAt := 0; ; {
+		params := url.Values{}
+		params.Set("startAt", strconv.Itoa(startAt))
+		params.Set("maxResults", "100")
+
+		var page struct {
+			Values []ChangeHistory `json:"values"`
+			IsLast bool            `json:"isLast"`
+		}
+		path := fmt.Sprintf("/rest/api/2/issue/%s/changelog", issueKey)
+		if err := c.Get(ctx, path, params, &page); err != nil {
+			return nil, err
+		}
+		histories = append(histories, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return histories, nil
+		}
+		startAt += len(page.Values)
+	}
+}
+
+// SearchIssuesWithChangelog runs a JQL search returning each issue's
+// change history, paged like SearchIssues. Cloud truncates the changelogs
+// embedded in search results, so those are completed from /changelog.
+func (c *JiraClient) SearchIssuesWithChangelog(ctx context.Context, jql string, maxResults int) ([]Issue, error) {
+	params := url.Values{}
+	params.Set("jql", jql)
+	params.Set("expand", "changelog")
+	params.Set("fields", strings.Join(historyFields, ","))
+
+	issues, err := c.searchPaged(ctx, "/rest/api/2/search", params, maxResults)
+	if err != nil || !c.IsCloud() {
+		return issues, err
+	}
+	for i := range issues {
+		changelog := issues[i].Changelog
+		if changelog == nil || len(changelog.Histories) >= changelog.Total {
+			continue
+		}
+		if changelog.Histories, err = c.getChangelog(ctx, issues[i].Key, changelog.Total); err != nil {
+			return nil, fmt.Errorf("fetching the changelog of %s: %w", issues[i].Key, err)
+		}
+	}
+	return issues, nil
+}
+
+// GetStatuses lists every workflow status with its category, which the
+// changelog does not carry.
+func (c *JiraClient) GetStatuses(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	if err := c.Get(ctx, "/rest/api/2/status", nil, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lroolle/atlas-cli/pkg/converter"
+)
+
+// JiraComment is an issue comment. Body is wiki markup on Server and
+// Markdown converted from ADF on Cloud.
+type JiraComment struct {
+	ID         string             `json:"id"`
+	Body       string             `json:"body"`
+	Author     JiraUser           `json:"author"`
+	Created    string             `json:"created"`
+	Updated    string             `json:"updated"`
+	Visibility *CommentVisibility `json:"visibility,omitempty"`
+}
+
+// CommentVisibility restricts a comment to a project role or a group.
+type CommentVisibility struct {
+	Type  string `json:"type"` // "role" or "group"
+	Value string `json:"value"`
+}
+
+// JiraCommentRequest is the payload for adding or editing a comment. Body
+// follows the same rules as JiraComment.Body.
+type JiraCommentRequest struct {
+	Body       string
+	Visibility *CommentVisibility
+}
+
+type CommentsResponse struct {
+	StartAt    int           `json:"startAt"`
+	MaxResults int           `json:"maxResults"`
+	Total      int           `json:"total"`
+	Comments   []JiraComment `json:"comments"`
+}
+
+// cloudComment decodes a v3 comment; its Body shadows JiraComment.Body.
+type cloudComment struct {
+	JiraComment
+	Body *converter.ADFNode `json:"body"`
+}
+
+func (cc cloudComment) comment() JiraComment {
+	comment := cc.JiraComment
+	comment.Body = converter.ADFToMarkdown(cc.Body)
+	return comment
+}
+
+func (c *JiraClient) commentPayload(req JiraCommentRequest) map[string]interface{} {
+	payload := map[string]interface{}{"body": req.Body}
+	if c.IsCloud() {
+		payload["body"] = converter.MarkdownToADF(req.Body)
+	}
+	if req.Visibility != nil {
+		payload["visibility"] = req.Visibility
+	}
+	return payload
+}
+
+// AddComment adds a comment to an issue. The comment is wiki markup on
+// Server; on Cloud it is read as Markdown and sent as ADF.
+func (c *JiraClient) AddComment(ctx context.Context, issueKey string, comment string) error {
+	_, err := c.CreateComment(ctx, issueKey, JiraCommentRequest{Body: comment})
+	return err
+}
+
+func (c *JiraClient) CreateComment(ctx context.Context, issueKey string, req JiraCommentRequest) (*JiraComment, error) {
+	path := fmt.Sprintf("%s/issue/%s/comment", c.richTextAPI(), issueKey)
+	return c.sendComment(ctx, c.Post, path, req)
+}
+
+func (c *JiraClient) UpdateComment(ctx context.Context, issueKey, commentID string, req JiraCommentRequest) (*JiraComment, error) {
+	path := fmt.Sprintf("%s/issue/%s/comment/%s", c.richTextAPI(), issueKey, commentID)
+	return c.sendComment(ctx, c.Put, path, req)
+}
+
+type sendFunc func(ctx context.Context, path string, body interface{}, result interface{}) error
+
+func (c *JiraClient) sendComment(ctx context.Context, send sendFunc, path string, req JiraCommentRequest) (*JiraComment, error) {
+	if c.IsCloud() {
+		var cc cloudComment
+		if err := send(ctx, path, c.commentPayload(req), &cc); err != nil {
+			return nil, err
+		}
+		comment := cc.comment()
+		return &comment, nil
+	}
+
+	var comment JiraComment
+	if err := send(ctx, path, c.commentPayload(req), &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (c *JiraClient) DeleteComment(ctx context.Context, issueKey, commentID string) error {
+	path := fmt.Sprintf("/rest/api/2/issue/%s/comment/%s", issueKey, commentID)
+	return c.Delete(ctx, path)
+}
+
+func (c *JiraClient) GetComment(ctx context.Context, issueKey, commentID string) (*JiraComment, error) {
+	path := fmt.Sprintf("%s/issue/%s/comment/%s", c.richTextAPI(), issueKey, commentID)
+
+	if c.IsCloud() {
+		var cc cloudComment
+		if err := c.Get(ctx, path, nil, &cc); err != nil {
+			return nil, err
+		}
+		comment := cc.comment()
+		return &comment, nil
+	}
+
+	var comment JiraComment
+	if err := c.Get(ctx, path, nil, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (c *JiraClient) GetComments(ctx context.Context, issueKey string) ([]JiraComment, error) {
+	path := fmt.Sprintf("%s/issue/%s/comment", c.richTextAPI(), issueKey)
+
+	if c.IsCloud() {
+		var response struct {
+			Comments []cloudComment `json:"comments"`
+		}
+		if err := c.Get(ctx, path, nil, &response); err != nil {
+			return nil, err
+		}
+		comments := make([]JiraComment, len(response.Comments))
+		for i, cc := range response.Comments {
+			comments[i] = cc.comment()
+		}
+		return comments, nil
+	}
+
+	var response CommentsResponse
+	err := c.Get(ctx, path, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Comments, nil
+}
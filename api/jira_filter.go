@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type Filter struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	JQL         string   `json:"jql"`
+	Owner       JiraUser `json:"owner"`
+	ViewURL     string   `json:"viewUrl,omitempty"`
+	Favourite   bool     `json:"favourite"`
+}
+
+// GetFavouriteFilters lists the saved filters the current user has starred,
+// including their own.
+func (c *JiraClient) GetFavouriteFilters(ctx context.Context) ([]Filter, error) {
+	var filters []Filter
+	if err := c.Get(ctx, "/rest/api/2/filter/favourite", nil, &filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+func (c *JiraClient) GetFilter(ctx context.Context, id string) (*Filter, error) {
+	path := fmt.Sprintf("/rest/api/2/filter/%s", id)
+
+	var filter Filter
+	if err := c.Get(ctx, path, nil, &filter); err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+// SearchFilters finds filters visible to the user by name. The endpoint
+// only exists on Cloud; Server/Data Center callers should fall back to
+// GetFavouriteFilters.
+func (c *JiraClient) SearchFilters(ctx context.Context, name string) ([]Filter, error) {
+	params := url.Values{}
+	params.Set("filterName", name)
+	params.Set("expand", "jql,owner,viewUrl")
+
+	var page struct {
+		Values []Filter `json:"values"`
+	}
+	if err := c.Get(ctx, "/rest/api/2/filter/search", params, &page); err != nil {
+		return nil, err
+	}
+	return page.Values, nil
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetIssueLinkTypes lists the link types configured on the server, e.g.
+// Blocks ("blocks" / "is blocked by").
+func (c *JiraClient) GetIssueLinkTypes(ctx context.Context) ([]LinkType, error) {
+	var response struct {
+		IssueLinkTypes []LinkType `json:"issueLinkTypes"`
+	}
+
+	if err := c.Get(ctx, "/rest/api/2/issueLinkType", nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.IssueLinkTypes, nil
+}
+
+// LinkIssues creates a link that reads "inwardKey <outward> outwardKey",
+// e.g. for Blocks: inwardKey blocks outwardKey. The naming follows the REST
+// API, where the inward issue is the link's source.
+func (c *JiraClient) LinkIssues(ctx context.Context, linkType, inwardKey, outwardKey, comment string) error {
+	body := map[string]interface{}{
+		"type":         map[string]string{"name": linkType},
+		"inwardIssue":  map[string]string{"key": inwardKey},
+		"outwardIssue": map[string]string{"key": outwardKey},
+	}
+	if comment != "" {
+		body["comment"] = map[string]string{"body": comment}
+	}
+
+	return c.Post(ctx, "/rest/api/2/issueLink", body, nil)
+}
+
+func (c *JiraClient) DeleteIssueLink(ctx context.Context, linkID string) error {
+	path := fmt.Sprintf("/rest/api/2/issueLink/%s", linkID)
+	return c.Delete(ctx, path)
+}
+
+// GetIssueFields fetches an issue restricted to the given fields, which keeps
+// graph walks and bulk reads cheap.
+func (c *JiraClient) GetIssueFields(ctx context.Context, issueKey string, fields ...string) (*Issue, error) {
+	path := fmt.Sprintf("/rest/api/2/issue/%s", issueKey)
+
+	var params url.Values
+	if len(fields) > 0 {
+		params = url.Values{}
+		params.Set("fields", strings.Join(fields, ","))
+	}
+
+	var issue Issue
+	if err := c.Get(ctx, path, params, &issue); err != nil {
+		return nil, err
+	}
+
+	return &issue, nil
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FieldMeta describes a field on a create, edit or transition screen. All
+// three endpoints report fields in the same shape.
+type FieldMeta = TransitionField
+
+// ErrIssueTypeNotFound reports an issue type the project does not offer.
+type ErrIssueTypeNotFound struct {
+	Project   string
+	IssueType string
+	Available []string
+}
+
+func (e *ErrIssueTypeNotFound) Error() string {
+	msg := fmt.Sprintf("issue type %q not found in project %s", e.IssueType, e.Project)
+	if len(e.Available) > 0 {
+		msg += fmt.Sprintf(" (available: %s)", strings.Join(e.Available, ", "))
+	}
+	return msg
+}
+
+type createMetaIssueType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Subtask bool   `json:"subtask"`
+}
+
+// GetCreateMeta returns the fields on the create screen for an issue type in
+// a project, keyed by field ID.
+//
+// Jira 9 replaced the expand-based createmeta endpoint with paged per-project
+// endpoints; the legacy form is used when the new ones are not available.
+func (c *JiraClient) GetCreateMeta(ctx context.Context, project, issueType string) (map[string]FieldMeta, error) {
+	types, err := c.getCreateMetaIssueTypes(ctx, project)
+	if isNotFound(err) {
+		return c.getLegacyCreateMeta(ctx, project, issueType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var typeID string
+	available := make([]string, len(types))
+	for i, t := range types {
+		available[i] = t.Name
+		if strings.EqualFold(t.Name, issueType) {
+			typeID = t.ID
+		}
+	}
+	if typeID == "" {
+		return nil, &ErrIssueTypeNotFound{Project: project, IssueType: issueType, Available: available}
+	}
+
+	path := fmt.Sprintf("/rest/api/2/issue/createmeta/%s/issuetypes/%s", url.PathEscape(project), typeID)
+	params := url.Values{}
+	params.Set("maxResults", "200")
+
+	var response struct {
+		Values []struct {
+			FieldMeta
+			FieldID string `json:"fieldId"`
+		} `json:"values"`
+	}
+	if err := c.Get(ctx, path, params, &response); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]FieldMeta, len(response.Values))
+	for _, v := range response.Values {
+		fields[v.FieldID] = v.FieldMeta
+	}
+	return fields, nil
+}
+
+func (c *JiraClient) getCreateMetaIssueTypes(ctx context.Context, project string) ([]createMetaIssueType, error) {
+	path := fmt.Sprintf("/rest/api/2/issue/createmeta/%s/issuetypes", url.PathEscape(project))
+	params := url.Values{}
+	params.Set("maxResults", "100")
+
+	var response struct {
+		Values []createMetaIssueType `json:"values"`
+	}
+	if err := c.Get(ctx, path, params, &response); err != nil {
+		return nil, err
+	}
+	return response.Values, nil
+}
+
+func (c *JiraClient) getLegacyCreateMeta(ctx context.Context, project, issueType string) (map[string]FieldMeta, error) {
+	params := url.Values{}
+	params.Set("projectKeys", project)
+	params.Set("expand", "projects.issuetypes.fields")
+
+	var response struct {
+		Projects []struct {
+			Key        string `json:"key"`
+			IssueTypes []struct {
+				createMetaIssueType
+				Fields map[string]FieldMeta `json:"fields"`
+			} `json:"issuetypes"`
+		} `json:"projects"`
+	}
+	if err := c.Get(ctx, "/rest/api/2/issue/createmeta", params, &response); err != nil {
+		return nil, err
+	}
+
+	var available []string
+	for _, p := range response.Projects {
+		for _, t := range p.IssueTypes {
+			if strings.EqualFold(t.Name, issueType) {
+				return t.Fields, nil
+			}
+			available = append(available, t.Name)
+		}
+	}
+	return nil, &ErrIssueTypeNotFound{Project: project, IssueType: issueType, Available: available}
+}
+
+// GetEditMeta returns the fields that can be changed on an existing issue,
+// keyed by field ID.
+func (c *JiraClient) GetEditMeta(ctx context.Context, issueKey string) (map[string]FieldMeta, error) {
+	path := fmt.Sprintf("/rest/api/2/issue/%s/editmeta", issueKey)
+
+	var response struct {
+		Fields map[string]FieldMeta `json:"fields"`
+	}
+	if err := c.Get(ctx, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Fields, nil
+}
+
+func isNotFound(err error) bool {
+	var unexpected *ErrUnexpectedResponse
+	return errors.As(err, &unexpected) && unexpected.StatusCode == http.StatusNotFound
+}
@@ -4,14 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func newTestJiraClient(server *httptest.Server) *JiraClient {
 	client := NewJiraClient(server.URL, "tester", "token")
 	client.HTTPClient = server.Client()
+	client.InstallationType = InstallationTypeServer
 	return client
 }
 
@@ -118,3 +124,608 @@ func TestGetFields(t *testing.T) {
 		t.Errorf("epic field parsed wrong: %+v", fields[1])
 	}
 }
+
+func TestGetCreateMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/createmeta/MYPROJ/issuetypes":
+			_, _ = w.Write([]byte(`{"values":[{"id":"1","name":"Bug"},{"id":"7","name":"Story"}]}`))
+		case "/rest/api/2/issue/createmeta/MYPROJ/issuetypes/1":
+			_, _ = w.Write([]byte(`{"values":[
+				{"fieldId":"summary","name":"Summary","required":true,"schema":{"type":"string"}},
+				{"fieldId":"customfield_12600","name":"Root Cause","required":true,"schema":{"type":"option","custom":"com.atlassian.jira.plugin.system.customfieldtypes:select"},
+				 "allowedValues":[{"id":"201","value":"config"}]}
+			]}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	fields, err := client.GetCreateMeta(context.Background(), "MYPROJ", "bug")
+	if err != nil {
+		t.Fatalf("GetCreateMeta returned error: %v", err)
+	}
+	if len(fields) != 2 {
+		t.Fatalf("len(fields) = %d, want 2", len(fields))
+	}
+	rc := fields["customfield_12600"]
+	if !rc.Required || rc.Name != "Root Cause" || rc.AllowedValues[0].Value != "config" {
+		t.Errorf("root cause parsed wrong: %+v", rc)
+	}
+
+	_, err = client.GetCreateMeta(context.Background(), "MYPROJ", "Epic")
+	var notFound *ErrIssueTypeNotFound
+	if !errors.As(err, &notFound) || len(notFound.Available) != 2 {
+		t.Errorf("error = %v, want ErrIssueTypeNotFound listing 2 types", err)
+	}
+}
+
+func TestGetCreateMetaLegacyFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/createmeta" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if got := r.URL.Query().Get("expand"); got != "projects.issuetypes.fields" {
+			t.Errorf("expand = %q", got)
+		}
+		_, _ = w.Write([]byte(`{"projects":[{"key":"MYPROJ","issuetypes":[
+			{"id":"1","name":"Bug","fields":{"summary":{"name":"Summary","required":true,"schema":{"type":"string"}}}}
+		]}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	fields, err := client.GetCreateMeta(context.Background(), "MYPROJ", "Bug")
+	if err != nil {
+		t.Fatalf("GetCreateMeta returned error: %v", err)
+	}
+	if !fields["summary"].Required {
+		t.Errorf("summary not parsed from legacy createmeta: %+v", fields)
+	}
+}
+
+func TestLinkIssues(t *testing.T) {
+	var gotBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/2/issueLink" {
+			t.Errorf("request = %s %s, want POST /rest/api/2/issueLink", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	if err := client.LinkIssues(context.Background(), "Blocks", "MYPROJ-1", "MYPROJ-2", ""); err != nil {
+		t.Fatalf("LinkIssues returned error: %v", err)
+	}
+
+	inward, _ := gotBody["inwardIssue"].(map[string]interface{})
+	outward, _ := gotBody["outwardIssue"].(map[string]interface{})
+	if inward["key"] != "MYPROJ-1" || outward["key"] != "MYPROJ-2" {
+		t.Errorf("body = %v, want inward MYPROJ-1 and outward MYPROJ-2", gotBody)
+	}
+	if _, ok := gotBody["comment"]; ok {
+		t.Errorf("empty comment should be omitted: %v", gotBody)
+	}
+}
+
+func TestAddWorklog(t *testing.T) {
+	var gotBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/2/issue/MYPROJ-1/worklog" {
+			t.Errorf("request = %s %s, want POST /rest/api/2/issue/MYPROJ-1/worklog", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"10452","timeSpent":"2h","timeSpentSeconds":7200,"started":"2024-05-06T09:00:00.000+0000"}`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	wl, err := client.AddWorklog(context.Background(), "MYPROJ-1", WorklogRequest{
+		TimeSpent: "2h",
+		Started:   "2024-05-06T09:00:00.000+0000",
+	})
+	if err != nil {
+		t.Fatalf("AddWorklog returned error: %v", err)
+	}
+
+	if gotBody["timeSpent"] != "2h" || gotBody["started"] != "2024-05-06T09:00:00.000+0000" {
+		t.Errorf("body = %v", gotBody)
+	}
+	if _, ok := gotBody["comment"]; ok {
+		t.Errorf("empty comment should be omitted: %v", gotBody)
+	}
+	if wl.ID != "10452" || wl.StartedTime().Hour() != 9 {
+		t.Errorf("worklog = %+v", wl)
+	}
+}
+
+func TestGetBoardSprintsPaging(t *testing.T) {
+	var starts []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/board/7/sprint" {
+			t.Errorf("path = %s, want /rest/agile/1.0/board/7/sprint", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("state"); got != "active,future" {
+			t.Errorf("state = %q, want active,future", got)
+		}
+		start := r.URL.Query().Get("startAt")
+		starts = append(starts, start)
+		if start == "0" {
+			w.Write([]byte(`{"startAt":0,"maxResults":2,"isLast":false,"values":[{"id":1,"name":"S1"},{"id":2,"name":"S2"}]}`))
+			return
+		}
+		w.Write([]byte(`{"startAt":2,"maxResults":2,"isLast":true,"values":[{"id":3,"name":"S3","state":"future"}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	sprints, err := client.GetBoardSprints(context.Background(), 7, "active", "future")
+	if err != nil {
+		t.Fatalf("GetBoardSprints returned error: %v", err)
+	}
+
+	if len(sprints) != 3 || sprints[2].Name != "S3" {
+		t.Errorf("sprints = %+v, want S1..S3", sprints)
+	}
+	if !reflect.DeepEqual(starts, []string{"0", "2"}) {
+		t.Errorf("startAt sequence = %v, want [0 2]", starts)
+	}
+}
+
+func TestSearchIssuesPagesPastServerCap(t *testing.T) {
+	var pages []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		pages = append(pages, q.Get("startAt")+"/"+q.Get("maxResults"))
+		// The server returns at most two issues per page, whatever was asked.
+		if q.Get("startAt") == "0" {
+			w.Write([]byte(`{"startAt":0,"maxResults":2,"total":5,"issues":[{"key":"P-1"},{"key":"P-2"}]}`))
+			return
+		}
+		w.Write([]byte(`{"startAt":2,"maxResults":2,"total":5,"issues":[{"key":"P-3"},{"key":"P-4"}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	issues, err := client.SearchIssues(context.Background(), "project = P", 4)
+	if err != nil {
+		t.Fatalf("SearchIssues returned error: %v", err)
+	}
+	if len(issues) != 4 || issues[3].Key != "P-4" {
+		t.Errorf("issues = %+v, want P-1..P-4", issues)
+	}
+	if !reflect.DeepEqual(pages, []string{"0/4", "2/2"}) {
+		t.Errorf("pages = %v, want [0/4 2/2]", pages)
+	}
+}
+
+func TestGetSprintIssuesPages(t *testing.T) {
+	var pages []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/sprint/42/issue" {
+			t.Errorf("path = %s, want /rest/agile/1.0/sprint/42/issue", r.URL.Path)
+		}
+		q := r.URL.Query()
+		pages = append(pages, q.Get("startAt")+"/"+q.Get("maxResults"))
+		switch q.Get("startAt") {
+		case "0":
+			w.Write([]byte(`{"startAt":0,"maxResults":2,"total":3,"issues":[{"key":"P-1"},{"key":"P-2"}]}`))
+		default:
+			w.Write([]byte(`{"startAt":2,"maxResults":2,"total":3,"issues":[{"key":"P-3"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	issues, err := client.GetSprintIssues(context.Background(), 42, 200, "summary")
+	if err != nil {
+		t.Fatalf("GetSprintIssues returned error: %v", err)
+	}
+	if len(issues) != 3 || issues[2].Key != "P-3" {
+		t.Errorf("issues = %+v, want P-1..P-3", issues)
+	}
+	if !reflect.DeepEqual(pages, []string{"0/200", "2/198"}) {
+		t.Errorf("pages = %v, want [0/200 2/198]", pages)
+	}
+}
+
+func TestAddAttachment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/2/issue/MYPROJ-1/attachments" {
+			t.Errorf("request = %s %s, want POST /rest/api/2/issue/MYPROJ-1/attachments", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("X-Atlassian-Token"); got != "no-check" {
+			t.Errorf("X-Atlassian-Token = %q, want no-check", got)
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("reading form file: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if header.Filename != "test.log" || string(data) != "PASS\n" {
+			t.Errorf("upload = %q with %q, want test.log with PASS", header.Filename, data)
+		}
+
+		w.Write([]byte(`[{"id":"100","filename":"test.log","size":5}]`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	attached, err := client.AddAttachment(context.Background(), "MYPROJ-1", "test.log", strings.NewReader("PASS\n"))
+	if err != nil {
+		t.Fatalf("AddAttachment returned error: %v", err)
+	}
+	if len(attached) != 1 || attached[0].ID != "100" {
+		t.Errorf("attached = %+v", attached)
+	}
+}
+
+func TestAttachmentTransfersOutlastRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		time.Sleep(100 * time.Millisecond)
+		if r.Method == "POST" {
+			w.Write([]byte(`[{"id":"100"}]`))
+			return
+		}
+		w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+	client.HTTPClient.Timeout = 20 * time.Millisecond
+
+	if _, err := client.AddAttachment(context.Background(), "MYPROJ-1", "big.bin", strings.NewReader("data")); err != nil {
+		t.Errorf("AddAttachment returned error: %v", err)
+	}
+	var out strings.Builder
+	if err := client.DownloadAttachment(context.Background(), Attachment{ID: "100", Filename: "big.bin"}, &out); err != nil || out.String() != "content" {
+		t.Errorf("DownloadAttachment = %q, %v", out.String(), err)
+	}
+	if client.HTTPClient.Timeout != 20*time.Millisecond {
+		t.Error("transfers must not change the shared client's timeout")
+	}
+}
+
+func TestCloudCommentsUseADF(t *testing.T) {
+	var gotBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/MYPROJ-1/comment" {
+			t.Errorf("path = %q, want /rest/api/3/issue/MYPROJ-1/comment", r.URL.Path)
+		}
+		if r.Method == "POST" {
+			if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+				t.Fatalf("decoding request body: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"1"}`))
+			return
+		}
+		w.Write([]byte(`{"comments":[{"id":"10","author":{"displayName":"Jane"},"created":"2024-05-06T09:00:00.000+0000",
+			"body":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"done","marks":[{"type":"strong"}]}]}]}}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+	client.InstallationType = InstallationTypeCloud
+
+	if err := client.AddComment(context.Background(), "MYPROJ-1", "**shipped**"); err != nil {
+		t.Fatalf("AddComment returned error: %v", err)
+	}
+	doc, ok := gotBody["body"].(map[string]interface{})
+	if !ok || doc["type"] != "doc" {
+		t.Errorf("comment body = %v, want an ADF doc", gotBody["body"])
+	}
+
+	comments, err := client.GetComments(context.Background(), "MYPROJ-1")
+	if err != nil {
+		t.Fatalf("GetComments returned error: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Body != "**done**" || comments[0].Author.DisplayName != "Jane" {
+		t.Errorf("comments = %+v", comments)
+	}
+}
+
+// cloudFieldsHandler serves the field list on Cloud, with one multi-line
+// and one single-line text custom field, and passes other requests on.
+func cloudFieldsHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/field" {
+			w.Write([]byte(`[
+				{"id":"description","name":"Description","schema":{"type":"string","system":"description"}},
+				{"id":"customfield_10200","name":"Root Cause","custom":true,"schema":{"type":"string","custom":"com.atlassian.jira.plugin.system.customfieldtypes:textarea"}},
+				{"id":"customfield_10201","name":"Team","custom":true,"schema":{"type":"string","custom":"com.atlassian.jira.plugin.system.customfieldtypes:textfield"}}
+			]`))
+			return
+		}
+		next(w, r)
+	}
+}
+
+func TestCloudCreateIssueSendsADFDescription(t *testing.T) {
+	var gotPath string
+	var gotBody struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+
+	server := httptest.NewServer(cloudFieldsHandler(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"1","key":"MYPROJ-1"}`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+	client.InstallationType = InstallationTypeCloud
+
+	fields := map[string]interface{}{
+		"summary":           "s",
+		"description":       "# Steps",
+		"customfield_10200": "config **drift**",
+		"customfield_10201": "Platform",
+	}
+	if _, err := client.CreateIssue(context.Background(), fields); err != nil {
+		t.Fatalf("CreateIssue returned error: %v", err)
+	}
+
+	if gotPath != "/rest/api/3/issue" {
+		t.Errorf("path = %q, want /rest/api/3/issue", gotPath)
+	}
+	for _, id := range []string{"description", "customfield_10200"} {
+		if doc, ok := gotBody.Fields[id].(map[string]interface{}); !ok || doc["type"] != "doc" {
+			t.Errorf("%s = %v, want an ADF doc", id, gotBody.Fields[id])
+		}
+	}
+	if gotBody.Fields["summary"] != "s" || gotBody.Fields["customfield_10201"] != "Platform" {
+		t.Errorf("plain text fields = %v, %v; want them unchanged", gotBody.Fields["summary"], gotBody.Fields["customfield_10201"])
+	}
+	if fields["description"] != "# Steps" {
+		t.Errorf("caller's fields were modified: %v", fields)
+	}
+}
+
+func TestCloudUpdateIssueSendsADF(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+
+	server := httptest.NewServer(cloudFieldsHandler(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+	client.InstallationType = InstallationTypeCloud
+
+	fields := map[string]interface{}{"description": "Fixed in **1.2**", "customfield_10200": "config"}
+	if err := client.UpdateIssue(context.Background(), "MYPROJ-1", fields); err != nil {
+		t.Fatalf("UpdateIssue returned error: %v", err)
+	}
+
+	if gotMethod != http.MethodPut || gotPath != "/rest/api/3/issue/MYPROJ-1" {
+		t.Errorf("request = %s %s, want PUT /rest/api/3/issue/MYPROJ-1", gotMethod, gotPath)
+	}
+	for _, id := range []string{"description", "customfield_10200"} {
+		if doc, ok := gotBody.Fields[id].(map[string]interface{}); !ok || doc["type"] != "doc" {
+			t.Errorf("%s = %v, want an ADF doc", id, gotBody.Fields[id])
+		}
+	}
+}
+
+func TestUpdateCommentSendsVisibility(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.Write([]byte(`{"id":"10452","body":"new","visibility":{"type":"role","value":"Developers"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	comment, err := client.UpdateComment(context.Background(), "MYPROJ-1", "10452", JiraCommentRequest{
+		Body:       "new",
+		Visibility: &CommentVisibility{Type: "role", Value: "Developers"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateComment returned error: %v", err)
+	}
+
+	if gotMethod != "PUT" || gotPath != "/rest/api/2/issue/MYPROJ-1/comment/10452" {
+		t.Errorf("request = %s %s", gotMethod, gotPath)
+	}
+	want := map[string]interface{}{
+		"body":       "new",
+		"visibility": map[string]interface{}{"type": "role", "value": "Developers"},
+	}
+	if !reflect.DeepEqual(gotBody, want) {
+		t.Errorf("body = %v, want %v", gotBody, want)
+	}
+	if comment.ID != "10452" || comment.Visibility == nil || comment.Visibility.Value != "Developers" {
+		t.Errorf("comment = %+v", comment)
+	}
+}
+
+func TestSearchUsersQueryByInstallation(t *testing.T) {
+	var gotPath string
+	var gotQuery url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.Query()
+		w.Write([]byte(`[{"name":"jdoe","displayName":"Jane Doe"}]`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+	users, err := client.SearchUsers(context.Background(), "jane", 5)
+	if err != nil {
+		t.Fatalf("SearchUsers returned error: %v", err)
+	}
+	if gotPath != "/rest/api/2/user/search" || gotQuery.Get("username") != "jane" || gotQuery.Get("maxResults") != "5" {
+		t.Errorf("server request = %s?%s", gotPath, gotQuery.Encode())
+	}
+	if len(users) != 1 || users[0].Name != "jdoe" {
+		t.Errorf("users = %+v", users)
+	}
+
+	client.InstallationType = InstallationTypeCloud
+	if _, err := client.SearchUsers(context.Background(), "jane", 5); err != nil {
+		t.Fatalf("SearchUsers returned error: %v", err)
+	}
+	if gotPath != "/rest/api/3/user/search" || gotQuery.Get("query") != "jane" {
+		t.Errorf("cloud request = %s?%s", gotPath, gotQuery.Encode())
+	}
+}
+
+func TestGetIssueWithChangelogPagesOnCloud(t *testing.T) {
+	var pages []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/MYPROJ-1":
+			if r.URL.Query().Get("expand") != "changelog" {
+				t.Errorf("expand = %q", r.URL.Query().Get("expand"))
+			}
+			w.Write([]byte(`{"key":"MYPROJ-1","fields":{"resolutiondate":"2024-05-03T10:00:00.000+0000"},
+				"changelog":{"startAt":0,"maxResults":1,"total":2,"histories":[{"id":"1"}]}}`))
+		case "/rest/api/2/issue/MYPROJ-1/changelog":
+			startAt := r.URL.Query().Get("startAt")
+			pages = append(pages, startAt)
+			if startAt == "0" {
+				w.Write([]byte(`{"values":[{"id":"1","items":[{"field":"status","from":"1","fromString":"Open","to":"3","toString":"In Progress"}]}],"isLast":false}`))
+			} else {
+				w.Write([]byte(`{"values":[{"id":"2"}],"isLast":true}`))
+			}
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+	client.InstallationType = InstallationTypeCloud
+
+	issue, err := client.GetIssueWithChangelog(context.Background(), "MYPROJ-1")
+	if err != nil {
+		t.Fatalf("GetIssueWithChangelog returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(pages, []string{"0", "1"}) {
+		t.Errorf("changelog pages = %v", pages)
+	}
+	histories := issue.Changelog.Histories
+	if len(histories) != 2 || histories[0].Items[0].ToString != "In Progress" || histories[1].ID != "2" {
+		t.Errorf("histories = %+v", histories)
+	}
+	if issue.Fields.Resolved != "2024-05-03T10:00:00.000+0000" {
+		t.Errorf("resolved = %q", issue.Fields.Resolved)
+	}
+}
+
+func TestSearchIssuesWithChangelogCompletesTruncatedHistories(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"@"+r.URL.Query().Get("startAt"))
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			if r.URL.Query().Get("expand") != "changelog" {
+				t.Errorf("expand = %q", r.URL.Query().Get("expand"))
+			}
+			if r.URL.Query().Get("startAt") == "0" {
+				w.Write([]byte(`{"total":2,"issues":[{"key":"P-1","changelog":{"total":2,"histories":[{"id":"1"}]}}]}`))
+				return
+			}
+			w.Write([]byte(`{"total":2,"issues":[{"key":"P-2","changelog":{"total":1,"histories":[{"id":"9"}]}}]}`))
+		case "/rest/api/2/issue/P-1/changelog":
+			w.Write([]byte(`{"values":[{"id":"1"},{"id":"2"}],"isLast":true}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+	client.InstallationType = InstallationTypeCloud
+
+	issues, err := client.SearchIssuesWithChangelog(context.Background(), "project = P", 100)
+	if err != nil {
+		t.Fatalf("SearchIssuesWithChangelog returned error: %v", err)
+	}
+	if len(issues) != 2 || len(issues[0].Changelog.Histories) != 2 || len(issues[1].Changelog.Histories) != 1 {
+		t.Errorf("issues = %+v", issues)
+	}
+	want := []string{"/rest/api/2/search@0", "/rest/api/2/search@1", "/rest/api/2/issue/P-1/changelog@0"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestUpdateVersion(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.Write([]byte(`{"id":"100","name":"1.4.0","released":true,"releaseDate":"2024-05-31"}`))
+	}))
+	defer server.Close()
+
+	client := newTestJiraClient(server)
+
+	version, err := client.UpdateVersion(context.Background(), "100", map[string]interface{}{"released": true, "releaseDate": "2024-05-31"})
+	if err != nil {
+		t.Fatalf("UpdateVersion returned error: %v", err)
+	}
+
+	if gotMethod != "PUT" || gotPath != "/rest/api/2/version/100" {
+		t.Errorf("request = %s %s", gotMethod, gotPath)
+	}
+	if gotBody["released"] != true || gotBody["releaseDate"] != "2024-05-31" {
+		t.Errorf("body = %v", gotBody)
+	}
+	if !version.Released || version.ReleaseDate != "2024-05-31" {
+		t.Errorf("version = %+v", version)
+	}
+}
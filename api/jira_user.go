@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// SearchUsers finds users whose name, display name or email starts with
+// query. Inactive users are included on Server.
+func (c *JiraClient) SearchUsers(ctx context.Context, query string, maxResults int) ([]JiraUser, error) {
+	params := url.Values{}
+	params.Set("maxResults", strconv.Itoa(maxResults))
+
+	path := "/rest/api/2/user/search"
+	if c.IsCloud() {
+		path = "/rest/api/3/user/search"
+		params.Set("query", query)
+	} else {
+		params.Set("username", query)
+		params.Set("includeInactive", "true")
+	}
+
+	var users []JiraUser
+	if err := c.Get(ctx, path, params, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetUser fetches a user by username on Server or account ID on Cloud.
+func (c *JiraClient) GetUser(ctx context.Context, id string) (*JiraUser, error) {
+	params := url.Values{}
+	path := "/rest/api/2/user"
+	if c.IsCloud() {
+		path = "/rest/api/3/user"
+		params.Set("accountId", id)
+	} else {
+		params.Set("username", id)
+	}
+
+	var user JiraUser
+	if err := c.Get(ctx, path, params, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetMyself fetches the user the client is authenticated as.
+func (c *JiraClient) GetMyself(ctx context.Context) (*JiraUser, error) {
+	var user JiraUser
+	if err := c.Get(ctx, "/rest/api/2/myself", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
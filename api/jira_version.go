@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// VersionRequest creates a project version. Dates are YYYY-MM-DD.
+type VersionRequest struct {
+	Name        string `json:"name"`
+	Project     string `json:"project"`
+	Description string `json:"description,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	Released    bool   `json:"released,omitempty"`
+}
+
+// GetProjectVersions lists all versions of a project, released and
+// archived ones included.
+func (c *JiraClient) GetProjectVersions(ctx context.Context, project string) ([]Version, error) {
+	path := fmt.Sprintf("/rest/api/2/project/%s/versions", project)
+
+	var versions []Version
+	if err := c.Get(ctx, path, nil, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (c *JiraClient) CreateVersion(ctx context.Context, req VersionRequest) (*Version, error) {
+	var version Version
+	if err := c.Post(ctx, "/rest/api/2/version", req, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// UpdateVersion changes the given fields of a version, e.g. released,
+// releaseDate or archived.
+func (c *JiraClient) UpdateVersion(ctx context.Context, versionID string, fields map[string]interface{}) (*Version, error) {
+	path := fmt.Sprintf("/rest/api/2/version/%s", versionID)
+
+	var version Version
+	if err := c.Put(ctx, path, fields, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
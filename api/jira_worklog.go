@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// JiraTimeLayout is the timestamp format JIRA uses in created, updated and
+// worklog started fields, and expects when one is sent.
+const JiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+type Worklog struct {
+	ID               string   `json:"id"`
+	IssueID          string   `json:"issueId"`
+	Author           JiraUser `json:"author"`
+	Comment          string   `json:"comment"`
+	Started          string   `json:"started"`
+	TimeSpent        string   `json:"timeSpent"`
+	TimeSpentSeconds int      `json:"timeSpentSeconds"`
+}
+
+// StartedTime parses Started; the zero time is returned if it is malformed.
+func (w Worklog) StartedTime() time.Time {
+	t, _ := time.Parse(JiraTimeLayout, w.Started)
+	return t
+}
+
+// WorklogRequest is the payload for adding or updating a worklog. Empty
+// fields are left unchanged on update.
+type WorklogRequest struct {
+	TimeSpent string `json:"timeSpent,omitempty"`
+	Started   string `json:"started,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+func (c *JiraClient) AddWorklog(ctx context.Context, issueKey string, req WorklogRequest) (*Worklog, error) {
+	if req.TimeSpent == "" {
+		return nil, fmt.Errorf("time spent required")
+	}
+
+	path := fmt.Sprintf("/rest/api/2/issue/%s/worklog", issueKey)
+
+	var worklog Worklog
+	if err := c.Post(ctx, path, req, &worklog); err != nil {
+		return nil, err
+	}
+
+	return &worklog, nil
+}
+
+func (c *JiraClient) GetWorklogs(ctx context.Context, issueKey string) ([]Worklog, error) {
+	path := fmt.Sprintf("/rest/api/2/issue/%s/worklog", issueKey)
+
+	params := url.Values{}
+	params.Set("maxResults", "5000")
+
+	var response struct {
+		Worklogs []Worklog `json:"worklogs"`
+	}
+	if err := c.Get(ctx, path, params, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Worklogs, nil
+}
+
+func (c *JiraClient) UpdateWorklog(ctx context.Context, issueKey, worklogID string, req WorklogRequest) (*Worklog, error) {
+	path := fmt.Sprintf("/rest/api/2/issue/%s/worklog/%s", issueKey, worklogID)
+
+	var worklog Worklog
+	if err := c.Put(ctx, path, req, &worklog); err != nil {
+		return nil, err
+	}
+
+	return &worklog, nil
+}
+
+func (c *JiraClient) DeleteWorklog(ctx context.Context, issueKey, worklogID string) error {
+	path := fmt.Sprintf("/rest/api/2/issue/%s/worklog/%s", issueKey, worklogID)
+	return c.Delete(ctx, path)
+}
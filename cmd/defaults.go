@@ -8,14 +8,19 @@ import (
 )
 
 // parseRepoArg parses repository argument with default fallback
-// Supports: PROJECT/REPO, REPO, or empty (uses defaults)
+// Supports: PROJECT/REPO, REPO, or empty. An empty or project-less argument
+// is completed from the checkout's Bitbucket remote, then from the
+// bitbucket.default_project/default_repo config.
 func parseRepoArg(arg string) (project, repo string, err error) {
 	if arg == "" {
+		if project, repo, ok := repoFromGit(); ok {
+			return project, repo, nil
+		}
 		// Use defaults from config
 		project = viper.GetString("bitbucket.default_project")
 		repo = viper.GetString("bitbucket.default_repo")
 		if project == "" || repo == "" {
-			return "", "", fmt.Errorf("no default project/repo configured, use PROJECT/REPO")
+			return "", "", fmt.Errorf("no repository: use PROJECT/REPO, run inside a clone with a Bitbucket remote, or set bitbucket.default_project/default_repo")
 		}
 		return project, repo, nil
 	}
@@ -25,9 +30,12 @@ func parseRepoArg(arg string) (project, repo string, err error) {
 	case 2:
 		return parts[0], parts[1], nil
 	case 1:
-		// Only repo name provided, use default project
-		project = viper.GetString("bitbucket.default_project")
+		// Only repo name provided, use the checkout's or the default project
 		repo = parts[0]
+		if gitProject, _, ok := repoFromGit(); ok {
+			return gitProject, repo, nil
+		}
+		project = viper.GetString("bitbucket.default_project")
 		if project == "" {
 			return "", "", fmt.Errorf("no default project configured, use PROJECT/REPO format")
 		}
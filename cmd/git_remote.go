@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// defaultRemotePreference is the order remotes are tried in when
+// bitbucket.remotes is not configured: in a fork workflow "upstream" is the
+// repository pull requests target.
+var defaultRemotePreference = []string{"upstream", "origin"}
+
+type gitRemote struct {
+	Name string
+	URL  string
+}
+
+// gitRemotes lists the fetch URLs of the current checkout's remotes.
+func gitRemotes() ([]gitRemote, error) {
+	out, err := exec.Command("git", "remote", "-v").Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing git remotes: %w", err)
+	}
+	return parseGitRemotes(string(out)), nil
+}
+
+// parseGitRemotes reads `git remote -v` output, keeping the fetch URL of
+// each remote in the order git lists them.
+func parseGitRemotes(out string) []gitRemote {
+	var remotes []gitRemote
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (len(fields) > 2 && fields[2] != "(fetch)") {
+			continue
+		}
+		remotes = append(remotes, gitRemote{Name: fields[0], URL: fields[1]})
+	}
+	return remotes
+}
+
+// parseRemoteURL extracts the host and the Bitbucket project and repository
+// from a clone URL:
+//
+//	ssh://git@git.example.com:7999/proj/repo.git
+//	git@git.example.com:proj/repo.git
+//	https://git.example.com/scm/proj/repo.git
+//
+// The last two path segments are used, so context paths
+// (https://host/bitbucket/scm/...) work too. Project keys are upper-cased;
+// personal projects (~user) are kept as is.
+func parseRemoteURL(raw string) (host, project, repo string, ok bool) {
+	var path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", "", "", false
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax: [user@]host:path
+		hostPart, pathPart, found := strings.Cut(raw, ":")
+		if !found {
+			return "", "", "", false
+		}
+		if _, h, hasUser := strings.Cut(hostPart, "@"); hasUser {
+			hostPart = h
+		}
+		host, path = hostPart, pathPart
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", "", false
+	}
+	project, repo = parts[len(parts)-2], parts[len(parts)-1]
+	if !strings.HasPrefix(project, "~") {
+		project = strings.ToUpper(project)
+	}
+	return strings.ToLower(host), project, repo, true
+}
+
+// remotePreference is the remote order: bitbucket.remotes when configured
+// (explicit), else defaultRemotePreference.
+func remotePreference() (names []string, explicit bool) {
+	if names := viper.GetStringSlice("bitbucket.remotes"); len(names) > 0 {
+		return names, true
+	}
+	return defaultRemotePreference, false
+}
+
+// bitbucketHost is the host name of the configured Bitbucket server, or ""
+// when none is configured.
+func bitbucketHost() string {
+	server := viper.GetString("bitbucket.server")
+	if server == "" {
+		return ""
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// bitbucketRemote is a remote that points at a repository on the Bitbucket
+// server.
+type bitbucketRemote struct {
+	gitRemote
+	Project string
+	Repo    string
+}
+
+// bitbucketRemotes returns the remotes pointing at host (any host when
+// empty), preferred names first, the rest in git's order. Explicitly
+// configured remotes are kept whatever their host, for SSH hosts that differ
+// from the web host.
+func bitbucketRemotes(remotes []gitRemote, host string, preference []string, explicit bool) []bitbucketRemote {
+	var matched []bitbucketRemote
+	for _, r := range remotes {
+		remoteHost, project, repo, ok := parseRemoteURL(r.URL)
+		if !ok {
+			continue
+		}
+		if host != "" && remoteHost != host && !(explicit && slices.Contains(preference, r.Name)) {
+			continue
+		}
+		matched = append(matched, bitbucketRemote{gitRemote: r, Project: project, Repo: repo})
+	}
+
+	rank := func(name string) int {
+		if i := slices.Index(preference, name); i >= 0 {
+			return i
+		}
+		return len(preference)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return rank(matched[i].Name) < rank(matched[j].Name)
+	})
+	return matched
+}
+
+// localBitbucketRemotes lists the current checkout's Bitbucket remotes in
+// preference order; outside a checkout it returns none.
+func localBitbucketRemotes() []bitbucketRemote {
+	remotes, err := gitRemotes()
+	if err != nil {
+		return nil
+	}
+	preference, explicit := remotePreference()
+	return bitbucketRemotes(remotes, bitbucketHost(), preference, explicit)
+}
+
+// repoFromGit returns the repository of the preferred Bitbucket remote.
+func repoFromGit() (project, repo string, ok bool) {
+	remotes := localBitbucketRemotes()
+	if len(remotes) == 0 {
+		return "", "", false
+	}
+	return remotes[0].Project, remotes[0].Repo, true
+}
+
+// remoteFor returns the name of the local remote that points at
+// project/repo.
+func remoteFor(project, repo string) (string, bool) {
+	for _, r := range localBitbucketRemotes() {
+		if strings.EqualFold(r.Project, project) && strings.EqualFold(r.Repo, repo) {
+			return r.Name, true
+		}
+	}
+	return "", false
+}
+
+// branchUpstream returns the Bitbucket remote local branch tracks and the
+// name of the branch it tracks there.
+func branchUpstream(branch string) (bitbucketRemote, string, bool) {
+	name, err := exec.Command("git", "config", "branch."+branch+".remote").Output()
+	if err != nil {
+		return bitbucketRemote{}, "", false
+	}
+	merge, err := exec.Command("git", "config", "branch."+branch+".merge").Output()
+	if err != nil {
+		return bitbucketRemote{}, "", false
+	}
+	for _, r := range localBitbucketRemotes() {
+		if r.Name == strings.TrimSpace(string(name)) {
+			return r, strings.TrimPrefix(strings.TrimSpace(string(merge)), "refs/heads/"), true
+		}
+	}
+	return bitbucketRemote{}, "", false
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestParseGitRemotes(t *testing.T) {
+	out := `origin	ssh://git@git.example.com:7999/me/app.git (fetch)
+origin	ssh://git@git.example.com:7999/me/app.git (push)
+upstream	https://git.example.com/scm/team/app.git (fetch)
+upstream	no-pushing (push)
+`
+	remotes := parseGitRemotes(out)
+	if len(remotes) != 2 {
+		t.Fatalf("remotes = %+v, want origin and upstream", remotes)
+	}
+	if remotes[0].Name != "origin" || remotes[1].Name != "upstream" || remotes[1].URL != "https://git.example.com/scm/team/app.git" {
+		t.Errorf("remotes = %+v", remotes)
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		url, host, project, repo string
+	}{
+		{"ssh://git@git.example.com:7999/proj/my-repo.git", "git.example.com", "PROJ", "my-repo"},
+		{"git@git.example.com:proj/my-repo.git", "git.example.com", "PROJ", "my-repo"},
+		{"https://git.example.com/scm/proj/my-repo.git", "git.example.com", "PROJ", "my-repo"},
+		{"https://alice@Git.Example.com/scm/proj/my-repo.git", "git.example.com", "PROJ", "my-repo"},
+		{"https://git.example.com/bitbucket/scm/proj/my-repo.git", "git.example.com", "PROJ", "my-repo"},
+		{"https://git.example.com/scm/~alice/scratch.git", "git.example.com", "~alice", "scratch"},
+		{"ssh://git@git.example.com:7999/proj/my-repo", "git.example.com", "PROJ", "my-repo"},
+	}
+	for _, tt := range tests {
+		host, project, repo, ok := parseRemoteURL(tt.url)
+		if !ok || host != tt.host || project != tt.project || repo != tt.repo {
+			t.Errorf("parseRemoteURL(%q) = %q %q %q %v, want %q %q %q", tt.url, host, project, repo, ok, tt.host, tt.project, tt.repo)
+		}
+	}
+
+	for _, url := range []string{"", "/local/path", "https://git.example.com/repo.git"} {
+		if _, _, _, ok := parseRemoteURL(url); ok {
+			t.Errorf("parseRemoteURL(%q) should fail", url)
+		}
+	}
+}
+
+func TestBitbucketRemotes(t *testing.T) {
+	remotes := []gitRemote{
+		{Name: "github", URL: "git@github.com:me/app.git"},
+		{Name: "origin", URL: "ssh://git@git.example.com:7999/~me/app.git"},
+		{Name: "backup", URL: "https://git.example.com/scm/ops/app.git"},
+		{Name: "upstream", URL: "https://git.example.com/scm/team/app.git"},
+		{Name: "corp", URL: "ssh://git@ssh.example.com:7999/team/app.git"},
+	}
+
+	got := bitbucketRemotes(remotes, "git.example.com", defaultRemotePreference, false)
+	want := []string{"upstream", "origin", "backup"}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %v", got, want)
+	}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Errorf("remote %d = %s, want %s", i, got[i].Name, name)
+		}
+	}
+	if got[0].Project != "TEAM" || got[0].Repo != "app" {
+		t.Errorf("upstream = %s/%s, want TEAM/app", got[0].Project, got[0].Repo)
+	}
+
+	// A configured remote is used even when its SSH host differs.
+	got = bitbucketRemotes(remotes, "git.example.com", []string{"corp"}, true)
+	if len(got) == 0 || got[0].Name != "corp" {
+		t.Errorf("explicit preference: got %+v, want corp first", got)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -20,7 +21,7 @@ var issueCmd = &cobra.Command{
 }
 
 var issueListCmd = &cobra.Command{
-	Use:   "list [search text]",
+	Use:   "list [@query] [search text]",
 	Short: "List JIRA issues",
 	Long: `List JIRA issues with various filters.
 
@@ -30,27 +31,55 @@ You can combine flags to create complex queries:
 
 Use ~ prefix for negation (quote to prevent shell expansion):
   atl issue list -s '~Done'            # status != Done
-  atl issue list --label '~wontfix'    # exclude label`,
+  atl issue list --label '~wontfix'    # exclude label
+
+Named queries from jira.queries in config run with @name; other flags
+narrow them further. {param} placeholders are filled from --var
+param=value, {param:default} supplies a default, and {project} is the
+-p / default project:
+  atl issue list @mine
+  atl issue list @team-bugs --var team=payments -s '~Done'`,
 	Example: `  atl issue list
   atl issue list -t Bug -s Open
   atl issue list -e MYPROJ-100 -a me
   atl issue list -e 18421              # auto-prefix with default project
-  atl issue list -q "created >= -7d"
+  atl issue list --created -7d --sprint current
+  atl issue list --updated week --watching
+  atl issue list --resolved 2024-05-01..2024-05-31 --order-by resolved
+  atl issue list --text "timeout" --fix-version '~1.2.0'
   atl issue list --order-by updated --reverse`,
 	Aliases: []string{"ls", "search"},
-	Args:    cobra.MaximumNArgs(1),
+	Args:    issueListArgs,
 	RunE:    runIssueList,
 }
 
 func runIssueList(cmd *cobra.Command, args []string) error {
-	ctx := cmd.Context()
 	var conditions []string
 
 	project, _ := cmd.Flags().GetString("project")
+	explicitProject := project != ""
 	if project == "" {
 		project = viper.GetString("jira.default_project")
 	}
-	if project != "" {
+
+	// A named query defines its own scope, so the default project only
+	// applies when no query is used or -p is given explicitly.
+	var queryOrder string
+	namedQuery := len(args) > 0 && strings.HasPrefix(args[0], "@")
+	if namedQuery {
+		vars, _ := cmd.Flags().GetStringArray("var")
+		query, err := resolveNamedQuery(args[0][1:], vars, project)
+		if err != nil {
+			return err
+		}
+		var queryConditions string
+		queryConditions, queryOrder = splitOrderBy(query)
+		if queryConditions != "" {
+			conditions = append(conditions, "("+queryConditions+")")
+		}
+		args = args[1:]
+	}
+	if project != "" && (explicitProject || !namedQuery) {
 		conditions = append(conditions, fmt.Sprintf("project = '%s'", escapeJQL(project)))
 	}
 
@@ -75,7 +104,13 @@ func runIssueList(cmd *cobra.Command, args []string) error {
 		} else if val == "none" || val == "x" {
 			conditions = append(conditions, "assignee IS EMPTY")
 		} else {
-			conditions = append(conditions, formatCondition("assignee", val))
+			client, err := api.GetJiraClient()
+			cmdutil.ExitIfError(err)
+			cond, err := assigneeCondition(cmd.Context(), client, val)
+			if err != nil {
+				return err
+			}
+			conditions = append(conditions, cond)
 		}
 	}
 
@@ -105,11 +140,39 @@ func runIssueList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if vals, _ := cmd.Flags().GetStringArray("fix-version"); len(vals) > 0 {
+		if cond := formatMultiCondition("fixVersion", vals); cond != "" {
+			conditions = append(conditions, cond)
+		}
+	}
+
+	if val, _ := cmd.Flags().GetString("sprint"); val != "" {
+		conditions = append(conditions, formatSprintCondition(val))
+	}
+
+	for _, field := range []string{"created", "updated", "resolved"} {
+		if val, _ := cmd.Flags().GetString(field); val != "" {
+			cond, err := formatDateCondition(field, val)
+			if err != nil {
+				return err
+			}
+			conditions = append(conditions, cond)
+		}
+	}
+
+	if val, _ := cmd.Flags().GetString("text"); val != "" {
+		conditions = append(conditions, formatTextCondition(val))
+	}
+
+	if watching, _ := cmd.Flags().GetBool("watching"); watching {
+		conditions = append(conditions, "watcher = currentUser()")
+	}
+
 	if val, _ := cmd.Flags().GetString("jql"); val != "" {
 		if strings.Contains(strings.ToUpper(val), "ORDER BY") {
 			return fmt.Errorf("--jql should not contain ORDER BY, use --order-by flag instead")
 		}
-		conditions = append(conditions, val)
+		conditions = append(conditions, "("+val+")")
 	}
 
 	if len(args) > 0 {
@@ -119,12 +182,16 @@ func runIssueList(cmd *cobra.Command, args []string) error {
 	jql := strings.Join(conditions, " AND ")
 
 	orderBy, _ := cmd.Flags().GetString("order-by")
+	if queryOrder != "" && !cmd.Flags().Changed("order-by") && !cmd.Flags().Changed("reverse") {
+		jql = strings.TrimSpace(jql + " ORDER BY " + queryOrder)
+		return searchAndPrintIssues(cmd, jql)
+	}
 	validOrderFields := map[string]bool{
-		"created": true, "updated": true, "priority": true, "status": true,
-		"key": true, "assignee": true, "reporter": true, "summary": true,
+		"created": true, "updated": true, "resolved": true, "priority": true, "status": true,
+		"key": true, "assignee": true, "reporter": true, "summary": true, "duedate": true, "rank": true,
 	}
 	if !validOrderFields[orderBy] {
-		return fmt.Errorf("invalid --order-by field %q, valid: created, updated, priority, status, key, assignee, reporter, summary", orderBy)
+		return fmt.Errorf("invalid --order-by field %q, valid: created, updated, resolved, priority, status, key, assignee, reporter, summary, duedate, rank", orderBy)
 	}
 
 	reverse, _ := cmd.Flags().GetBool("reverse")
@@ -138,6 +205,12 @@ func runIssueList(cmd *cobra.Command, args []string) error {
 		jql += fmt.Sprintf(" ORDER BY %s %s", orderBy, direction)
 	}
 
+	return searchAndPrintIssues(cmd, jql)
+}
+
+// searchAndPrintIssues runs jql with the command's --limit and prints the
+// standard issue table.
+func searchAndPrintIssues(cmd *cobra.Command, jql string) error {
 	limit, _ := cmd.Flags().GetInt("limit")
 	if limit <= 0 {
 		limit = cmdutil.DefaultLimit
@@ -146,7 +219,7 @@ func runIssueList(cmd *cobra.Command, args []string) error {
 	client, err := api.GetJiraClient()
 	cmdutil.ExitIfError(err)
 
-	issues, err := client.SearchIssues(ctx, jql, limit)
+	issues, err := client.SearchIssues(cmd.Context(), jql, limit)
 	if err != nil {
 		return err
 	}
@@ -189,6 +262,23 @@ func escapeJQL(val string) string {
 	return strings.ReplaceAll(val, "'", "''")
 }
 
+// assigneeCondition matches an assignee given by username, email or name
+// (~ negates), resolving it to what the installation's JQL expects.
+func assigneeCondition(ctx context.Context, client *api.JiraClient, val string) (string, error) {
+	negate := strings.HasPrefix(val, "~")
+	name := strings.TrimPrefix(val, "~")
+
+	user, err := resolveJiraUser(ctx, client, name)
+	if err != nil {
+		return "", fmt.Errorf("resolving --assignee: %w", err)
+	}
+	id := jiraUserID(client, *user)
+	if negate {
+		id = "~" + id
+	}
+	return formatCondition("assignee", id), nil
+}
+
 func formatCondition(field, val string) string {
 	if strings.HasPrefix(val, "~") {
 		return fmt.Sprintf("%s != '%s'", field, escapeJQL(val[1:]))
@@ -224,93 +314,6 @@ func formatMultiCondition(field string, vals []string) string {
 	return strings.Join(parts, " AND ")
 }
 
-var issueViewCmd = &cobra.Command{
-	Use:   "view [issue-key]",
-	Short: "View a JIRA issue",
-	Args:  cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := cmd.Context()
-
-		client, err := api.GetJiraClient()
-		cmdutil.ExitIfError(err)
-
-		issue, err := client.GetIssue(ctx, args[0])
-		if err != nil {
-			return err
-		}
-
-		fmt.Printf("Issue: %s\n", issue.Key)
-		fmt.Printf("Summary: %s\n", issue.Fields.Summary)
-		fmt.Printf("Status: %s\n", issue.Fields.Status.Name)
-		fmt.Printf("Priority: %s\n", issue.Fields.Priority.Name)
-		fmt.Printf("Type: %s\n", issue.Fields.IssueType.Name)
-		fmt.Printf("Reporter: %s\n", issue.Fields.Reporter.DisplayName)
-
-		if issue.Fields.Assignee != nil {
-			fmt.Printf("Assignee: %s\n", issue.Fields.Assignee.DisplayName)
-		} else {
-			fmt.Println("Assignee: Unassigned")
-		}
-
-		fmt.Printf("Created: %s\n", issue.Fields.Created)
-		fmt.Printf("Updated: %s\n", issue.Fields.Updated)
-
-		if issue.Fields.Resolution != nil {
-			fmt.Printf("Resolution: %s\n", issue.Fields.Resolution.Name)
-		}
-
-		if issue.Fields.Description != "" {
-			fmt.Printf("\nDescription:\n%s\n", issue.Fields.Description)
-		}
-
-		if issue.Fields.Parent != nil {
-			fmt.Printf("\nParent: %s (%s)\n", issue.Fields.Parent.Key, issue.Fields.Parent.Fields.Summary)
-		}
-
-		if len(issue.Fields.Subtasks) > 0 {
-			fmt.Printf("\nSubtasks (%d):\n", len(issue.Fields.Subtasks))
-			for _, st := range issue.Fields.Subtasks {
-				status := st.Fields.Status.Name
-				fmt.Printf("  %s [%s] %s\n", st.Key, status, st.Fields.Summary)
-			}
-		}
-
-		if len(issue.Fields.IssueLinks) > 0 {
-			fmt.Printf("\nIssue Links:\n")
-			for _, link := range issue.Fields.IssueLinks {
-				if link.OutwardIssue != nil {
-					fmt.Printf("  %s %s (%s)\n", link.Type.Outward, link.OutwardIssue.Key, link.OutwardIssue.Fields.Summary)
-				}
-				if link.InwardIssue != nil {
-					fmt.Printf("  %s %s (%s)\n", link.Type.Inward, link.InwardIssue.Key, link.InwardIssue.Fields.Summary)
-				}
-			}
-		}
-
-		return nil
-	},
-}
-
-var issueCommentCmd = &cobra.Command{
-	Use:   "comment [issue-key] [comment]",
-	Short: "Add a comment to a JIRA issue",
-	Args:  cobra.ExactArgs(2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := cmd.Context()
-
-		client, err := api.GetJiraClient()
-		cmdutil.ExitIfError(err)
-
-		err = client.AddComment(ctx, args[0], args[1])
-		if err != nil {
-			return err
-		}
-
-		fmt.Printf("Comment added to %s\n", args[0])
-		return nil
-	},
-}
-
 var issueCommentsCmd = &cobra.Command{
 	Use:   "comments [issue-key]",
 	Short: "Show comments for a JIRA issue",
@@ -333,9 +336,12 @@ var issueCommentsCmd = &cobra.Command{
 
 		fmt.Printf("Comments for %s:\n\n", args[0])
 		for i, comment := range comments {
-			fmt.Printf("--- Comment %d ---\n", i+1)
+			fmt.Printf("--- Comment %d (ID %s) ---\n", i+1, comment.ID)
 			fmt.Printf("Author: %s\n", comment.Author.DisplayName)
 			fmt.Printf("Created: %s\n", comment.Created)
+			if comment.Visibility != nil {
+				fmt.Printf("Visible to: %s %s\n", comment.Visibility.Type, comment.Visibility.Value)
+			}
 			fmt.Printf("Body:\n%s\n\n", comment.Body)
 		}
 
@@ -416,7 +422,6 @@ func init() {
 	issueTransitionCmd.Flags().Bool("json", false, "Output transitions as JSON (list mode)")
 	issueTransitionCmd.Flags().Bool("dry-run", false, "Print the transition payload without executing")
 	issueTransitionCmd.Flags().Bool("no-defaults", false, "Ignore jira.transition_defaults from config")
-	issueCmd.AddCommand(issueCommentCmd)
 	issueCmd.AddCommand(issueCommentsCmd)
 	issueCmd.AddCommand(issuePrsCmd)
 
@@ -426,14 +431,22 @@ func init() {
 	f.StringP("type", "t", "", "Filter by issue type (Bug, Story, Task, Epic)")
 	f.StringArrayP("status", "s", nil, "Filter by status (use ~ for negation, e.g., '~Done')")
 	f.StringP("priority", "y", "", "Filter by priority (Blocker, Critical, Major, Minor, Trivial)")
-	f.StringP("assignee", "a", "", "Filter by assignee (use 'me' or 'none'/'x' for unassigned)")
+	f.StringP("assignee", "a", "", "Filter by assignee: username, email or name (use 'me' or 'none'/'x' for unassigned)")
 	f.StringP("reporter", "r", "", "Filter by reporter (use 'me' for current user)")
 	f.StringP("epic", "e", "", "Filter by epic link (issue key, auto-prefixes project if needed)")
 	f.StringP("component", "C", "", "Filter by component")
 	f.StringArrayP("label", "l", nil, "Filter by label (use ~ for negation)")
+	f.StringArray("fix-version", nil, "Filter by fix version (use ~ for negation)")
+	f.String("sprint", "", "Filter by sprint: current, open, future, closed, none, ID or name (use ~ for negation)")
+	f.String("created", "", "Created within: -7d, 2w, today, week, last-week, month, YYYY-MM-DD or FROM..TO")
+	f.String("updated", "", "Updated within (same values as --created)")
+	f.String("resolved", "", "Resolved within (same values as --created)")
+	f.String("text", "", "Search summary and description (use ~ for negation)")
+	f.Bool("watching", false, "Only issues you are watching")
 	f.StringP("project", "p", "", "Filter by project (default from config)")
 	f.StringP("jql", "q", "", "Additional JQL conditions (no ORDER BY)")
-	f.String("order-by", "created", "Order by field (created, updated, priority, status)")
+	f.String("order-by", "created", "Order by field (created, updated, resolved, priority, status, key, duedate, rank, ...)")
 	f.Bool("reverse", false, "Reverse sort order (ASC instead of DESC)")
 	f.Int("limit", cmdutil.DefaultLimit, "Maximum number of results")
+	f.StringArray("var", nil, "Named query parameter as name=value (repeatable)")
 }
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var issueAttachCmd = &cobra.Command{
+	Use:   "attach [issue-key] [file...]",
+	Short: "Attach files to a JIRA issue",
+	Long: `Attach one or more files to a JIRA issue.
+
+Use - to attach standard input; --name sets its file name.`,
+	Example: `  atl issue attach MYPROJ-123 screenshot.png trace.log
+  go test ./... 2>&1 | atl issue attach MYPROJ-123 - --name test-output.log`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		issueKey := args[0]
+		stdinName, _ := cmd.Flags().GetString("name")
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		stdinUsed := false
+		for _, path := range args[1:] {
+			if path == "-" {
+				if stdinUsed {
+					return fmt.Errorf("standard input can only be attached once")
+				}
+				stdinUsed = true
+			}
+
+			attached, err := attachFile(ctx, client, issueKey, path, stdinName)
+			if err != nil {
+				return err
+			}
+			for _, a := range attached {
+				fmt.Printf("Attached %s (%s) to %s\n", a.Filename, formatBytes(a.Size), issueKey)
+			}
+		}
+		return nil
+	},
+}
+
+var issueAttachmentsCmd = &cobra.Command{
+	Use:   "attachments [issue-key]",
+	Short: "List or download a JIRA issue's attachments",
+	Example: `  atl issue attachments MYPROJ-123
+  atl issue attachments MYPROJ-123 --download ./artifacts`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		issueKey := args[0]
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		attachments, err := client.GetAttachments(ctx, issueKey)
+		if err != nil {
+			return err
+		}
+
+		if dir, _ := cmd.Flags().GetString("download"); dir != "" {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+			return downloadAttachments(ctx, client, attachments, dir)
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(attachments)
+		}
+
+		if len(attachments) == 0 {
+			fmt.Printf("No attachments on %s\n", issueKey)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tFILENAME\tSIZE\tAUTHOR\tCREATED")
+		for _, a := range attachments {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.ID, a.Filename, formatBytes(a.Size), a.Author.DisplayName, sprintDate(a.Created))
+		}
+		return w.Flush()
+	},
+}
+
+func attachFile(ctx context.Context, client *api.JiraClient, issueKey, path, stdinName string) ([]api.Attachment, error) {
+	var r io.Reader = os.Stdin
+	name := stdinName
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r, name = f, filepath.Base(path)
+	}
+
+	attached, err := client.AddAttachment(ctx, issueKey, name, r)
+	if err != nil {
+		return nil, fmt.Errorf("attaching %s: %w", name, err)
+	}
+	return attached, nil
+}
+
+func downloadAttachments(ctx context.Context, client *api.JiraClient, attachments []api.Attachment, dir string) error {
+	for i, name := range attachmentFileNames(attachments) {
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		err = client.DownloadAttachment(ctx, attachments[i], f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("downloading %s: %w", attachments[i].Filename, err)
+		}
+		fmt.Printf("Downloaded: %s\n", path)
+	}
+	return nil
+}
+
+// attachmentFileNames picks a local file name per attachment. Server-side
+// names are reduced to their base name so they cannot escape the target
+// directory, and repeated names are prefixed with the attachment ID.
+func attachmentFileNames(attachments []api.Attachment) []string {
+	names := make([]string, len(attachments))
+	seen := map[string]bool{}
+	for i, a := range attachments {
+		name := filepath.Base(filepath.Clean("/" + a.Filename))
+		if name == "/" || name == "." {
+			name = a.ID
+		}
+		if seen[name] {
+			name = a.ID + "-" + name
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	issueCmd.AddCommand(issueAttachCmd)
+	issueCmd.AddCommand(issueAttachmentsCmd)
+
+	issueAttachCmd.Flags().String("name", "stdin.txt", "File name for an attachment read from stdin")
+
+	issueAttachmentsCmd.Flags().StringP("download", "d", "", "Download all attachments into this directory")
+	issueAttachmentsCmd.Flags().Bool("json", false, "Output as JSON")
+}
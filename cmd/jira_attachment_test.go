@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestAttachmentFileNames(t *testing.T) {
+	attachments := []api.Attachment{
+		{ID: "1", Filename: "log.txt"},
+		{ID: "2", Filename: "log.txt"},
+		{ID: "3", Filename: "../../etc/passwd"},
+		{ID: "4", Filename: ""},
+	}
+
+	got := attachmentFileNames(attachments)
+	want := []string{"log.txt", "2-log.txt", "passwd", "4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("attachmentFileNames() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{512, "512 B"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.in); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
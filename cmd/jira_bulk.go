@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var issueBulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Apply one change to every issue matching a JQL query",
+	Long: `Apply one change to every issue matching a JQL query.
+
+Issues are processed a few at a time (--concurrency) and each gets a
+row in the result table. By default the first failure stops any work
+not yet started; --continue-on-error processes every issue. The exit
+status is non-zero if any issue failed.
+
+--dry-run prints the payload each issue would receive, including the
+jira.transition_defaults merge for transitions, without changing
+anything. On a terminal you are asked to confirm unless --yes is given.`,
+	Example: `  atl issue bulk --jql "sprint = 1946 AND status = 'In Review'" transition Done -R Done
+  atl issue bulk -q "labels = flaky" edit -F "Team Zone=Backend / Platform" --dry-run
+  atl issue bulk -q "project = MYPROJ AND assignee IS EMPTY AND type = Bug" assign alice
+  atl issue bulk -q "fixVersion = 1.2.0" comment "Shipped in 1.2.0" --continue-on-error`,
+}
+
+var bulkTransitionCmd = &cobra.Command{
+	Use:   "transition [transition]",
+	Short: "Transition every matching issue",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := transitionOptions{}
+		opts.Resolution, _ = cmd.Flags().GetString("resolution")
+		opts.FixVersions, _ = cmd.Flags().GetStringSlice("fix-version")
+		opts.RawFields, _ = cmd.Flags().GetStringArray("field")
+
+		update := api.TransitionUpdate{}
+		update.Comment, _ = cmd.Flags().GetString("comment")
+		update.TimeSpent, _ = cmd.Flags().GetString("time-spent")
+
+		noDefaults, _ := cmd.Flags().GetBool("no-defaults")
+
+		return runBulk(cmd, "transition", func(ctx context.Context, client *api.JiraClient, issue api.Issue, dryRun bool) (string, interface{}, error) {
+			transitions, err := client.GetTransitions(ctx, issue.Key)
+			if err != nil {
+				return "", nil, err
+			}
+			target, err := matchTransition(transitions, args[0])
+			if err != nil {
+				return "", nil, err
+			}
+
+			issueOpts := opts
+			if !noDefaults {
+				issueOpts.Defaults = transitionDefaults(issue.Fields.IssueType.Name, target.Name)
+			}
+			fields, issueUpdate, err := applyTransitionDefaults(issueOpts, update, target)
+			if err != nil {
+				return "", nil, err
+			}
+
+			detail := fmt.Sprintf("%s -> %s", issue.Fields.Status.Name, target.To.Name)
+			if dryRun {
+				return detail, map[string]interface{}{
+					"to":   target.To.Name,
+					"body": api.TransitionBody(target.ID, fields, issueUpdate),
+				}, nil
+			}
+			return detail, nil, client.TransitionIssue(ctx, issue.Key, target.ID, fields, issueUpdate)
+		})
+	},
+}
+
+var bulkEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit fields on every matching issue",
+	Long: `Edit fields on every matching issue.
+
+Each issue's edit screen is checked as in 'atl issue edit', so an issue
+on which a field cannot be set fails on its own row.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := issueEditOptions{}
+		opts.Priority, _ = cmd.Flags().GetString("priority")
+		opts.Labels, _ = cmd.Flags().GetStringArray("label")
+		opts.FixVersions, _ = cmd.Flags().GetStringSlice("fix-version")
+		opts.Components, _ = cmd.Flags().GetStringSlice("component")
+		opts.RawFields, _ = cmd.Flags().GetStringArray("field")
+
+		// Fail on an empty change before touching the server.
+		if _, err := buildEditFields(opts, nil); err != nil {
+			return err
+		}
+
+		return runBulk(cmd, "edit", func(ctx context.Context, client *api.JiraClient, issue api.Issue, dryRun bool) (string, interface{}, error) {
+			screen, err := client.GetEditMeta(ctx, issue.Key)
+			if err != nil {
+				return "", nil, fmt.Errorf("reading edit screen: %w", err)
+			}
+			fields, err := buildEditFields(opts, screen)
+			if err != nil {
+				return "", nil, err
+			}
+
+			detail := fmt.Sprintf("%d field(s)", len(fields))
+			if dryRun {
+				return detail, map[string]interface{}{"fields": fields}, nil
+			}
+			return detail, nil, client.UpdateIssue(ctx, issue.Key, fields)
+		})
+	},
+}
+
+var bulkAssignCmd = &cobra.Command{
+	Use:   "assign [user]",
+	Short: "Assign every matching issue",
+	Long: `Assign every matching issue to a user, given by username, email or
+name ('me' for yourself, 'none' or 'x' to unassign).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		user := args[0]
+		var assignee interface{}
+		switch user {
+		case "none", "x":
+			user = "Unassigned"
+		default:
+			client, err := api.GetJiraClient()
+			cmdutil.ExitIfError(err)
+
+			resolved, err := resolveJiraUser(cmd.Context(), client, user)
+			if err != nil {
+				return err
+			}
+			assignee = jiraUserRef(client, *resolved)
+			if user == "me" {
+				user = valueOr(resolved.Name, resolved.DisplayName)
+			}
+		}
+		fields := map[string]interface{}{"assignee": assignee}
+
+		return runBulk(cmd, "assign", func(ctx context.Context, client *api.JiraClient, issue api.Issue, dryRun bool) (string, interface{}, error) {
+			from := "Unassigned"
+			if issue.Fields.Assignee != nil {
+				from = issue.Fields.Assignee.DisplayName
+			}
+			detail := fmt.Sprintf("%s -> %s", from, user)
+			if dryRun {
+				return detail, map[string]interface{}{"fields": fields}, nil
+			}
+			return detail, nil, client.UpdateIssue(ctx, issue.Key, fields)
+		})
+	},
+}
+
+var bulkCommentCmd = &cobra.Command{
+	Use:   "comment [text]",
+	Short: "Comment on every matching issue",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		body := args[0]
+
+		return runBulk(cmd, "comment on", func(ctx context.Context, client *api.JiraClient, issue api.Issue, dryRun bool) (string, interface{}, error) {
+			detail := cmdutil.Truncate(firstLine(body), cmdutil.TitleTruncateShort)
+			if dryRun {
+				return detail, map[string]interface{}{"body": body}, nil
+			}
+			return detail, nil, client.AddComment(ctx, issue.Key, body)
+		})
+	},
+}
+
+// bulkOp plans (dryRun) or applies the change to one issue. It returns a
+// short description for the result table and, when planning, the payload.
+type bulkOp func(ctx context.Context, client *api.JiraClient, issue api.Issue, dryRun bool) (string, interface{}, error)
+
+type bulkResult struct {
+	Key     string      `json:"issue"`
+	Status  string      `json:"status"`
+	Detail  string      `json:"detail,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+const (
+	bulkOK      = "ok"
+	bulkFailed  = "failed"
+	bulkSkipped = "skipped"
+	bulkPlanned = "planned"
+)
+
+func runBulk(cmd *cobra.Command, verb string, op bulkOp) error {
+	ctx := cmd.Context()
+
+	jql, _ := cmd.Flags().GetString("jql")
+	limit, _ := cmd.Flags().GetInt("limit")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	issues, err := client.SearchIssues(ctx, jql, limit)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Println("No issues found")
+		return nil
+	}
+	if len(issues) == limit {
+		fmt.Fprintf(os.Stderr, "Warning: the query matched at least %d issues; only the first %d are processed (raise --limit)\n", limit, limit)
+	}
+
+	if !dryRun && !yes && isInteractive() {
+		answer, err := promptLine(fmt.Sprintf("About to %s %d issue(s). Continue? [y/N] ", verb, len(issues)))
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			return fmt.Errorf("cancelled")
+		}
+	}
+
+	results := processBulk(ctx, issues, concurrency, continueOnError || dryRun, func(ctx context.Context, issue api.Issue) (string, interface{}, error) {
+		return op(ctx, client, issue, dryRun)
+	})
+	if dryRun {
+		for i := range results {
+			if results[i].Status == bulkOK {
+				results[i].Status = bulkPlanned
+			}
+		}
+	}
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput || dryRun {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else if err := printBulkResults(results); err != nil {
+		return err
+	}
+
+	return bulkError(results)
+}
+
+// processBulk runs fn over issues with at most concurrency in flight and
+// returns one result per issue, in input order. Unless continueOnError is
+// set, the first failure cancels the issues not yet started.
+func processBulk(ctx context.Context, issues []api.Issue, concurrency int, continueOnError bool, fn func(context.Context, api.Issue) (string, interface{}, error)) []bulkResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]bulkResult, len(issues))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, issue := range issues {
+		results[i] = bulkResult{Key: issue.Key, Status: bulkSkipped}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		if ctx.Err() != nil {
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, issue api.Issue) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			detail, payload, err := fn(ctx, issue)
+			results[i].Detail = detail
+			results[i].Payload = payload
+			switch {
+			case err == nil:
+				results[i].Status = bulkOK
+			case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+				// Stopped because another issue failed.
+			default:
+				results[i].Status = bulkFailed
+				results[i].Error = err.Error()
+				if !continueOnError {
+					cancel()
+				}
+			}
+		}(i, issue)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func printBulkResults(results []bulkResult) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tRESULT\tDETAIL")
+	for _, r := range results {
+		detail := r.Detail
+		if r.Error != "" {
+			detail = r.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.Key, r.Status, detail)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	fmt.Printf("\n%d ok, %d failed, %d skipped\n", counts[bulkOK], counts[bulkFailed], counts[bulkSkipped])
+	return nil
+}
+
+func bulkError(results []bulkResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.Status == bulkFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d issue(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+func init() {
+	issueCmd.AddCommand(issueBulkCmd)
+	issueBulkCmd.AddCommand(bulkTransitionCmd)
+	issueBulkCmd.AddCommand(bulkEditCmd)
+	issueBulkCmd.AddCommand(bulkAssignCmd)
+	issueBulkCmd.AddCommand(bulkCommentCmd)
+
+	pf := issueBulkCmd.PersistentFlags()
+	pf.StringP("jql", "q", "", "JQL selecting the issues (required)")
+	pf.Int("limit", 100, "Maximum number of issues to process")
+	pf.Int("concurrency", 4, "Number of issues processed at once")
+	pf.Bool("dry-run", false, "Print each issue's payload as JSON without changing anything")
+	pf.Bool("continue-on-error", false, "Keep going after an issue fails")
+	pf.Bool("yes", false, "Skip the confirmation prompt")
+	pf.Bool("json", false, "Output results as JSON")
+	_ = issueBulkCmd.MarkPersistentFlagRequired("jql")
+
+	tf := bulkTransitionCmd.Flags()
+	tf.StringP("resolution", "R", "", "Resolution name (e.g. Done, Won't Fix)")
+	tf.StringSlice("fix-version", nil, "Fix version(s) to set")
+	tf.StringArrayP("field", "F", nil, "Screen field as 'name=value' or 'id=value' (repeatable)")
+	tf.StringP("comment", "m", "", "Comment to add with the transition")
+	tf.StringP("time-spent", "T", "", "Log work with the transition (e.g. 2h)")
+	tf.Bool("no-defaults", false, "Ignore jira.transition_defaults from config")
+
+	ef := bulkEditCmd.Flags()
+	ef.String("priority", "", "Priority name")
+	ef.StringArrayP("label", "l", nil, "Label, replaces current labels (repeatable)")
+	ef.StringSlice("fix-version", nil, "Fix version(s), replaces current versions")
+	ef.StringSliceP("component", "C", nil, "Component(s), replaces current components")
+	ef.StringArrayP("field", "F", nil, "Field as 'name=value' or 'id=value', value may be JSON (repeatable)")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func bulkTestIssues(n int) []api.Issue {
+	issues := make([]api.Issue, n)
+	for i := range issues {
+		issues[i] = api.Issue{Key: fmt.Sprintf("P-%d", i+1)}
+	}
+	return issues
+}
+
+func TestProcessBulkContinueOnError(t *testing.T) {
+	results := processBulk(context.Background(), bulkTestIssues(5), 3, true, func(ctx context.Context, issue api.Issue) (string, interface{}, error) {
+		if issue.Key == "P-2" || issue.Key == "P-4" {
+			return "", nil, errors.New("boom")
+		}
+		return "done", nil, nil
+	})
+
+	want := []string{bulkOK, bulkFailed, bulkOK, bulkFailed, bulkOK}
+	for i, r := range results {
+		if r.Key != fmt.Sprintf("P-%d", i+1) || r.Status != want[i] {
+			t.Errorf("results[%d] = %+v, want status %s", i, r, want[i])
+		}
+	}
+	if err := bulkError(results); err == nil || err.Error() != "2 of 5 issue(s) failed" {
+		t.Errorf("bulkError = %v", err)
+	}
+}
+
+func TestProcessBulkStopsOnError(t *testing.T) {
+	var calls int32
+	results := processBulk(context.Background(), bulkTestIssues(10), 1, false, func(ctx context.Context, issue api.Issue) (string, interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		if issue.Key == "P-3" {
+			return "", nil, errors.New("boom")
+		}
+		return "done", nil, nil
+	})
+
+	if calls != 3 {
+		t.Errorf("fn called %d times, want 3 (stop after first failure)", calls)
+	}
+	if results[2].Status != bulkFailed || results[2].Error != "boom" {
+		t.Errorf("results[2] = %+v, want failed", results[2])
+	}
+	for _, r := range results[3:] {
+		if r.Status != bulkSkipped {
+			t.Errorf("%s status = %s, want skipped", r.Key, r.Status)
+		}
+	}
+}
+
+func TestProcessBulkConcurrencyBound(t *testing.T) {
+	var inFlight, peak int32
+	processBulk(context.Background(), bulkTestIssues(20), 4, true, func(ctx context.Context, issue api.Issue) (string, interface{}, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		defer atomic.AddInt32(&inFlight, -1)
+		return "", nil, nil
+	})
+
+	if peak > 4 {
+		t.Errorf("peak concurrency = %d, want <= 4", peak)
+	}
+}
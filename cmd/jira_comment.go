@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/lroolle/atlas-cli/pkg/converter"
+	"github.com/spf13/cobra"
+)
+
+var issueCommentCmd = &cobra.Command{
+	Use:   "comment [issue-key] [comment]",
+	Short: "Add, edit or delete a comment on a JIRA issue",
+	Long: `Add a comment to a JIRA issue, or edit or delete an existing one by
+the ID 'atl issue comments' shows.
+
+@user mentions are looked up with the user search API and turned into
+real mentions when they name a user exactly (username, account ID, email
+or name); anything else stays plain text. Mentions inside code are left
+alone.
+
+--visibility restricts the comment to a project role or a group.`,
+	Example: `  atl issue comment MYPROJ-123 "Fixed in 1.4, @jdoe please verify"
+  atl issue comment MYPROJ-123 --visibility role:Developers -b "internal note"
+  git log -1 --format=%B | atl issue comment MYPROJ-123 --body-file -
+  atl issue comment MYPROJ-123 --edit 10452 "corrected text"
+  atl issue comment MYPROJ-123 --delete 10452`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runIssueComment,
+}
+
+func runIssueComment(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	issueKey := args[0]
+	text := ""
+	if len(args) > 1 {
+		text = args[1]
+	}
+
+	editID, _ := cmd.Flags().GetString("edit")
+	deleteID, _ := cmd.Flags().GetString("delete")
+	if editID != "" && deleteID != "" {
+		return errors.New("--edit and --delete cannot be combined")
+	}
+
+	if deleteID != "" {
+		for _, flag := range []string{"body", "body-file", "visibility", "markdown"} {
+			if cmd.Flags().Changed(flag) {
+				return fmt.Errorf("--%s cannot be combined with --delete", flag)
+			}
+		}
+		if text != "" {
+			return errors.New("--delete takes no comment text")
+		}
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		if err := client.DeleteComment(ctx, issueKey, deleteID); err != nil {
+			return fmt.Errorf("deleting comment %s: %w", deleteID, err)
+		}
+		fmt.Printf("Deleted comment %s from %s\n", deleteID, issueKey)
+		return nil
+	}
+
+	body, err := resolveCommentBody(cmd, text)
+	if err != nil {
+		return err
+	}
+
+	var visibility *api.CommentVisibility
+	if v, _ := cmd.Flags().GetString("visibility"); v != "" {
+		if visibility, err = parseCommentVisibility(v); err != nil {
+			return err
+		}
+	}
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	body = resolveMentions(body, client.IsCloud(), func(query string) (*api.JiraUser, error) {
+		return findMentionedUser(ctx, client, query)
+	})
+	// Cloud comments are sent as ADF, which the client builds from
+	// Markdown itself.
+	if md, _ := cmd.Flags().GetBool("markdown"); md && !client.IsCloud() {
+		body = converter.MarkdownToWiki(body)
+	}
+
+	req := api.JiraCommentRequest{Body: body, Visibility: visibility}
+	var comment *api.JiraComment
+	if editID != "" {
+		if visibility == nil {
+			// An edit without visibility would lift the restriction.
+			current, err := client.GetComment(ctx, issueKey, editID)
+			if err != nil {
+				return fmt.Errorf("fetching comment %s: %w", editID, err)
+			}
+			req.Visibility = current.Visibility
+		}
+		comment, err = client.UpdateComment(ctx, issueKey, editID, req)
+		if err != nil {
+			return fmt.Errorf("updating comment %s: %w", editID, err)
+		}
+	} else {
+		comment, err = client.CreateComment(ctx, issueKey, req)
+		if err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(comment)
+	}
+
+	verb := "Added"
+	if editID != "" {
+		verb = "Updated"
+	}
+	restricted := ""
+	if comment.Visibility != nil {
+		restricted = fmt.Sprintf(", visible to %s %s", comment.Visibility.Type, comment.Visibility.Value)
+	}
+	fmt.Printf("%s comment %s on %s%s\n", verb, comment.ID, issueKey, restricted)
+	return nil
+}
+
+// parseCommentVisibility reads role:NAME or group:NAME.
+func parseCommentVisibility(s string) (*api.CommentVisibility, error) {
+	kind, value, ok := strings.Cut(s, ":")
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	value = strings.TrimSpace(value)
+	if !ok || value == "" || (kind != "role" && kind != "group") {
+		return nil, fmt.Errorf("invalid --visibility %q: use role:NAME or group:NAME", s)
+	}
+	return &api.CommentVisibility{Type: kind, Value: value}, nil
+}
+
+var mentionPattern = regexp.MustCompile(`(^|[\s(])@([A-Za-z0-9][\w.+@-]*\w)`)
+
+// resolveMentions replaces @user mentions outside code with [~name] on
+// Server or [~accountid:ID] on Cloud. Mentions that cannot be resolved are
+// reported on stderr and left as typed.
+func resolveMentions(text string, cloud bool, lookup func(string) (*api.JiraUser, error)) string {
+	resolved := map[string]string{}
+	resolve := func(segment string) string {
+		return mentionPattern.ReplaceAllStringFunc(segment, func(m string) string {
+			sub := mentionPattern.FindStringSubmatch(m)
+			prefix, query := sub[1], sub[2]
+
+			mention, seen := resolved[query]
+			if !seen {
+				user, err := lookup(query)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: @%s left as text: %v\n", query, err)
+				} else if cloud {
+					mention = "[~accountid:" + user.AccountID + "]"
+				} else {
+					mention = "[~" + user.Name + "]"
+				}
+				resolved[query] = mention
+			}
+			if mention == "" {
+				return m
+			}
+			return prefix + mention
+		})
+	}
+
+	lines := strings.Split(text, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "{code") || strings.HasPrefix(trimmed, "{noformat") {
+			inFence = !inFence
+			if strings.Count(trimmed, "{code") > 1 || strings.Count(trimmed, "{noformat") > 1 {
+				inFence = false // {code}x{code} on one line
+			}
+			continue
+		}
+		if inFence {
+			continue
+		}
+		// Even segments between backticks are outside code spans.
+		segments := strings.Split(line, "`")
+		for j := 0; j < len(segments); j += 2 {
+			segments[j] = resolve(segments[j])
+		}
+		lines[i] = strings.Join(segments, "`")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func init() {
+	issueCmd.AddCommand(issueCommentCmd)
+
+	f := issueCommentCmd.Flags()
+	f.StringP("body", "b", "", "Comment text")
+	f.StringP("body-file", "F", "", "Read comment text from file ('-' for stdin)")
+	f.String("edit", "", "Replace the text of an existing comment ID")
+	f.String("delete", "", "Delete an existing comment ID")
+	f.String("visibility", "", "Restrict the comment: role:NAME or group:NAME")
+	f.Bool("markdown", false, "Comment is Markdown (always the case on JIRA Cloud)")
+	f.Bool("json", false, "Output the comment as JSON")
+}
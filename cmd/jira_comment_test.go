@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/pkg/converter"
+)
+
+func TestParseCommentVisibility(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    *api.CommentVisibility
+		wantErr bool
+	}{
+		{"role:Developers", &api.CommentVisibility{Type: "role", Value: "Developers"}, false},
+		{"Group: jira-devs ", &api.CommentVisibility{Type: "group", Value: "jira-devs"}, false},
+		{"role:Service Desk Team", &api.CommentVisibility{Type: "role", Value: "Service Desk Team"}, false},
+		{"Developers", nil, true},
+		{"user:jdoe", nil, true},
+		{"role:", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseCommentVisibility(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCommentVisibility(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseCommentVisibility(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestResolveMentions(t *testing.T) {
+	lookups := 0
+	lookup := func(q string) (*api.JiraUser, error) {
+		lookups++
+		switch q {
+		case "jdoe":
+			return &api.JiraUser{Name: "jdoe", AccountID: "5b10a2"}, nil
+		case "jane@example.com":
+			return &api.JiraUser{Name: "jane", AccountID: "7c20"}, nil
+		}
+		return nil, fmt.Errorf("no JIRA user matches %q", q)
+	}
+
+	in := "@jdoe please check, cc @jane@example.com.\n" +
+		"mail me at me@example.com, @ghost\n" +
+		"`@jdoe` stays\n" +
+		"```\n@jdoe in code\n```\n" +
+		"(@jdoe)"
+
+	server := "[~jdoe] please check, cc [~jane].\n" +
+		"mail me at me@example.com, @ghost\n" +
+		"`@jdoe` stays\n" +
+		"```\n@jdoe in code\n```\n" +
+		"([~jdoe])"
+	if got := resolveMentions(in, false, lookup); got != server {
+		t.Errorf("server mentions:\n got: %q\nwant: %q", got, server)
+	}
+	if lookups != 3 {
+		t.Errorf("lookups = %d, want 3 (one per distinct mention)", lookups)
+	}
+
+	// --markdown on Server converts afterwards; @ghost must stay text.
+	if got := converter.MarkdownToWiki(resolveMentions("@jdoe and @ghost", false, lookup)); got != "[~jdoe] and @ghost" {
+		t.Errorf("converted mentions = %q", got)
+	}
+
+	cloud := resolveMentions("hi @jdoe", true, lookup)
+	if cloud != "hi [~accountid:5b10a2]" {
+		t.Errorf("cloud mention = %q", cloud)
+	}
+}
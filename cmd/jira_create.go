@@ -3,11 +3,14 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/lroolle/atlas-cli/api"
 	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/lroolle/atlas-cli/pkg/converter"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -24,7 +27,7 @@ type issueCreateOptions struct {
 	Description string
 	Parent      string
 	Priority    string
-	Assignee    string
+	Assignee    map[string]string
 	Epic        string
 	Sprint      int
 	StoryPoints float64
@@ -51,11 +54,22 @@ Sub-task types require --parent. Sub-tasks inherit sprint from their
 parent, so --sprint is rejected by the server for sub-task types.
 
 Epic, sprint, and story points are stored in server-specific custom
-fields; they are resolved automatically from the JIRA field registry.`,
+fields; they are resolved automatically from the JIRA field registry.
+--sprint takes an ID or the name of an active or future sprint on the
+project's scrum boards (or on jira.default_board).
+
+Before sending, the payload is checked against the project's create
+screen: --field keys may be display names, enumerated values must be
+allowed, and missing required fields are prompted for on a terminal
+(or reported with their allowed values otherwise). Use --list-fields to
+see the screen, and --no-validate to skip the check.`,
 	Example: `  atl issue create -t Story -s "story title" -e MYPROJ-100 --sprint 1946 --story-points 3
+  atl issue create -t Story -s "story title" --sprint "Sprint 42"
   atl issue create -t Sub-task -P MYPROJ-123 -s "dev subtask title"
   atl issue create -t Bug -s "crash on empty input" -y Critical -a me
-  atl issue create -t Task -s "raw field example" --field 'customfield_10103=5'`,
+  atl issue create -t Task -s "raw field example" --field 'customfield_10103=5'
+  atl issue create -t Bug -s "crash" -F "Root Cause=config"
+  atl issue create -t Bug --list-fields`,
 	Aliases: []string{"new"},
 	Args:    cobra.NoArgs,
 	RunE:    runIssueCreate,
@@ -71,9 +85,9 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 	opts.Description, _ = cmd.Flags().GetString("description")
 	opts.Parent, _ = cmd.Flags().GetString("parent")
 	opts.Priority, _ = cmd.Flags().GetString("priority")
-	opts.Assignee, _ = cmd.Flags().GetString("assignee")
+	assignee, _ := cmd.Flags().GetString("assignee")
 	opts.Epic, _ = cmd.Flags().GetString("epic")
-	opts.Sprint, _ = cmd.Flags().GetInt("sprint")
+	sprint, _ := cmd.Flags().GetString("sprint")
 	opts.StoryPoints, _ = cmd.Flags().GetFloat64("story-points")
 	opts.Labels, _ = cmd.Flags().GetStringArray("label")
 	opts.FixVersions, _ = cmd.Flags().GetStringSlice("fix-version")
@@ -86,16 +100,41 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 	if opts.Project == "" {
 		return fmt.Errorf("project required: use --project or set jira.default_project in config")
 	}
-	if opts.Assignee == "me" {
-		opts.Assignee = viper.GetString("jira.username")
-		if opts.Assignee == "" {
-			opts.Assignee = viper.GetString("username")
-		}
-	}
 
 	client, err := api.GetJiraClient()
 	cmdutil.ExitIfError(err)
 
+	// Cloud descriptions are sent as ADF, which the client builds from
+	// Markdown itself.
+	if md, _ := cmd.Flags().GetBool("markdown"); md && !client.IsCloud() {
+		opts.Description = converter.MarkdownToWiki(opts.Description)
+	}
+
+	if listFields, _ := cmd.Flags().GetBool("list-fields"); listFields {
+		screen, err := client.GetCreateMeta(ctx, opts.Project, opts.Type)
+		if err != nil {
+			return fmt.Errorf("fetching create screen: %w", err)
+		}
+		fmt.Printf("Create fields for %s in %s (* = required):\n", opts.Type, opts.Project)
+		printScreenFields(screen)
+		return nil
+	}
+
+	if assignee != "" {
+		user, err := resolveJiraUser(ctx, client, assignee)
+		if err != nil {
+			return fmt.Errorf("resolving --assignee: %w", err)
+		}
+		opts.Assignee = jiraUserRef(client, *user)
+	}
+
+	if sprint != "" {
+		opts.Sprint, err = resolveSprintID(ctx, client, sprint, "", opts.Project)
+		if err != nil {
+			return fmt.Errorf("resolving --sprint: %w", err)
+		}
+	}
+
 	var agile agileFieldIDs
 	if opts.Epic != "" || opts.Sprint > 0 || opts.StoryPoints > 0 {
 		agile, err = resolveAgileFields(ctx, client)
@@ -109,6 +148,12 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if noValidate, _ := cmd.Flags().GetBool("no-validate"); !noValidate {
+		if err := validateCreateFields(ctx, client, opts, fields); err != nil {
+			return err
+		}
+	}
+
 	created, err := client.CreateIssue(ctx, fields)
 	if err != nil {
 		return err
@@ -153,8 +198,8 @@ func buildIssueFields(opts issueCreateOptions, agile agileFieldIDs) (map[string]
 	if opts.Priority != "" {
 		fields["priority"] = map[string]string{"name": opts.Priority}
 	}
-	if opts.Assignee != "" {
-		fields["assignee"] = map[string]string{"name": opts.Assignee}
+	if opts.Assignee != nil {
+		fields["assignee"] = opts.Assignee
 	}
 	if len(opts.Labels) > 0 {
 		fields["labels"] = opts.Labels
@@ -204,6 +249,45 @@ func buildIssueFields(opts issueCreateOptions, agile agileFieldIDs) (map[string]
 	return fields, nil
 }
 
+// validateCreateFields checks the payload against the create screen and
+// fills in missing required fields, interactively when possible. If the
+// screen cannot be read the server remains the only judge.
+func validateCreateFields(ctx context.Context, client *api.JiraClient, opts issueCreateOptions, fields map[string]interface{}) error {
+	screen, err := client.GetCreateMeta(ctx, opts.Project, opts.Type)
+	var notFound *api.ErrIssueTypeNotFound
+	if errors.As(err, &notFound) {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: skipping field validation, cannot read create screen: %v\n", err)
+		return nil
+	}
+
+	if err := normalizeScreenFields(screen, "create", fields); err != nil {
+		return err
+	}
+	if err := validateAllowedValues(screen, fields); err != nil {
+		return err
+	}
+
+	missing := missingRequiredFields(screen, fields)
+	if len(missing) == 0 {
+		return nil
+	}
+	if !isInteractive() {
+		return requiredFieldsError(screen, missing)
+	}
+	return promptForFields(screen, missing, fields)
+}
+
+// currentJiraUsername is the user "me" refers to in issue flags.
+func currentJiraUsername() string {
+	if name := viper.GetString("jira.username"); name != "" {
+		return name
+	}
+	return viper.GetString("username")
+}
+
 // parseFieldValue decodes JSON-looking values so --field can pass
 // objects and arrays; anything else stays a plain string.
 func parseFieldValue(val string) interface{} {
@@ -243,6 +327,9 @@ func findAgileFields(jiraFields []api.JiraField) agileFieldIDs {
 			agile.Sprint = f.ID
 		case f.Custom && strings.EqualFold(f.Name, "Story Points"):
 			agile.StoryPoints = f.ID
+		case f.Custom && strings.EqualFold(f.Name, "Story point estimate") && agile.StoryPoints == "":
+			// Team-managed Cloud projects use their own estimate field.
+			agile.StoryPoints = f.ID
 		}
 	}
 	return agile
@@ -258,18 +345,20 @@ func init() {
 	f.StringP("summary", "s", "", "Issue summary (required)")
 	f.StringP("project", "p", "", "Project key (default from config)")
 	f.StringP("description", "b", "", "Issue description")
+	f.Bool("markdown", false, "Description is Markdown (always the case on JIRA Cloud)")
 	f.StringP("parent", "P", "", "Parent issue key (required for sub-task types)")
 	f.StringP("priority", "y", "", "Priority name (Blocker, Critical, Major, Minor, Trivial)")
-	f.StringP("assignee", "a", "", "Assignee username (use 'me' for current user)")
+	f.StringP("assignee", "a", "", "Assignee username, email or name (use 'me' for current user)")
 	f.StringP("epic", "e", "", "Epic link (issue key, auto-prefixes project if needed)")
-	f.Int("sprint", 0, "Sprint ID (not valid for sub-task types)")
+	f.String("sprint", "", "Sprint ID or name of an open sprint (not valid for sub-task types)")
 	f.Float64("story-points", 0, "Story points estimate")
 	f.StringArrayP("label", "l", nil, "Label (repeatable)")
 	f.StringSlice("fix-version", nil, "Fix version(s)")
 	f.StringSliceP("component", "C", nil, "Component(s)")
-	f.StringArray("field", nil, "Additional fields as key=value, value may be JSON (repeatable)")
+	f.StringArrayP("field", "F", nil, "Additional fields as name=value or id=value, value may be JSON (repeatable)")
 	f.Bool("json", false, "Output created issue as JSON")
+	f.Bool("list-fields", false, "List the create screen fields for --type and exit")
+	f.Bool("no-validate", false, "Skip checking fields against the create screen")
 
 	_ = issueCreateCmd.MarkFlagRequired("type")
-	_ = issueCreateCmd.MarkFlagRequired("summary")
 }
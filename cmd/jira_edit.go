@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/lroolle/atlas-cli/pkg/converter"
+	"github.com/spf13/cobra"
+)
+
+type issueEditOptions struct {
+	Summary     string
+	Description string
+	Priority    string
+	Assignee    map[string]string
+	Labels      []string
+	FixVersions []string
+	Components  []string
+	RawFields   []string
+}
+
+var issueEditCmd = &cobra.Command{
+	Use:   "edit [issue-key]",
+	Short: "Edit fields of a JIRA issue",
+	Long: `Edit fields of an existing JIRA issue.
+
+Changes are checked against the issue's edit screen before sending:
+--field keys may be display names or field IDs, plain values are
+coerced from the field schema, and enumerated values must be one of
+the allowed values. Fields that are not on the edit screen are
+rejected. Use --list-fields to see what can be changed.
+
+--label, --fix-version and --component replace the current values.`,
+	Example: `  atl issue edit MYPROJ-123 -s "clearer summary"
+  atl issue edit MYPROJ-123 -y Critical -a me
+  atl issue edit MYPROJ-123 -F "Root Cause=config" -F "Story Points=3"
+  atl issue edit MYPROJ-123 --list-fields`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueEdit,
+}
+
+func runIssueEdit(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	issueKey := args[0]
+
+	opts := issueEditOptions{}
+	opts.Summary, _ = cmd.Flags().GetString("summary")
+	opts.Description, _ = cmd.Flags().GetString("description")
+	opts.Priority, _ = cmd.Flags().GetString("priority")
+	assignee, _ := cmd.Flags().GetString("assignee")
+	opts.Labels, _ = cmd.Flags().GetStringArray("label")
+	opts.FixVersions, _ = cmd.Flags().GetStringSlice("fix-version")
+	opts.Components, _ = cmd.Flags().GetStringSlice("component")
+	opts.RawFields, _ = cmd.Flags().GetStringArray("field")
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	// Cloud descriptions are sent as ADF, which the client builds from
+	// Markdown itself.
+	if md, _ := cmd.Flags().GetBool("markdown"); md && !client.IsCloud() {
+		opts.Description = converter.MarkdownToWiki(opts.Description)
+	}
+
+	if assignee != "" {
+		user, err := resolveJiraUser(ctx, client, assignee)
+		if err != nil {
+			return fmt.Errorf("resolving --assignee: %w", err)
+		}
+		opts.Assignee = jiraUserRef(client, *user)
+	}
+
+	screen, err := client.GetEditMeta(ctx, issueKey)
+	if listFields, _ := cmd.Flags().GetBool("list-fields"); listFields {
+		if err != nil {
+			return fmt.Errorf("fetching edit screen: %w", err)
+		}
+		fmt.Printf("Editable fields for %s:\n", issueKey)
+		printScreenFields(screen)
+		return nil
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: skipping field validation, cannot read edit screen: %v\n", err)
+		screen = nil
+	}
+
+	fields, err := buildEditFields(opts, screen)
+	if err != nil {
+		return err
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{"issue": issueKey, "fields": fields})
+	}
+
+	if err := client.UpdateIssue(ctx, issueKey, fields); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated %s\n", issueKey)
+	return nil
+}
+
+// buildEditFields assembles the update payload. With a nil screen (edit
+// metadata unavailable) keys and values pass through unchecked.
+func buildEditFields(opts issueEditOptions, screen map[string]api.FieldMeta) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+
+	if opts.Summary != "" {
+		fields["summary"] = opts.Summary
+	}
+	if opts.Description != "" {
+		fields["description"] = opts.Description
+	}
+	if opts.Priority != "" {
+		fields["priority"] = map[string]string{"name": opts.Priority}
+	}
+	if opts.Assignee != nil {
+		fields["assignee"] = opts.Assignee
+	}
+	if len(opts.Labels) > 0 {
+		fields["labels"] = opts.Labels
+	}
+	if len(opts.FixVersions) > 0 {
+		versions := make([]map[string]string, len(opts.FixVersions))
+		for i, v := range opts.FixVersions {
+			versions[i] = map[string]string{"name": v}
+		}
+		fields["fixVersions"] = versions
+	}
+	if len(opts.Components) > 0 {
+		components := make([]map[string]string, len(opts.Components))
+		for i, c := range opts.Components {
+			components[i] = map[string]string{"name": c}
+		}
+		fields["components"] = components
+	}
+
+	for _, f := range opts.RawFields {
+		key, val, ok := strings.Cut(f, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --field format %q, expected key=value", f)
+		}
+		id, meta, err := resolveScreenField(screen, "edit", key)
+		if err != nil {
+			return nil, err
+		}
+		if meta != nil && !strings.HasPrefix(val, "[") && !strings.HasPrefix(val, "{") {
+			fields[id] = coerceTransitionValue(*meta, val)
+		} else {
+			fields[id] = parseFieldValue(val)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no changes specified")
+	}
+	if screen == nil {
+		return fields, nil
+	}
+
+	for id := range fields {
+		if _, ok := screen[id]; !ok {
+			return nil, fmt.Errorf("field %q is not editable on this issue (see --list-fields)", id)
+		}
+	}
+	if err := validateAllowedValues(screen, fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func init() {
+	issueCmd.AddCommand(issueEditCmd)
+
+	f := issueEditCmd.Flags()
+	f.SortFlags = false
+
+	f.StringP("summary", "s", "", "New summary")
+	f.StringP("description", "b", "", "New description")
+	f.Bool("markdown", false, "Description is Markdown (always the case on JIRA Cloud)")
+	f.StringP("priority", "y", "", "Priority name (Blocker, Critical, Major, Minor, Trivial)")
+	f.StringP("assignee", "a", "", "Assignee username, email or name (use 'me' for current user)")
+	f.StringArrayP("label", "l", nil, "Label, replaces current labels (repeatable)")
+	f.StringSlice("fix-version", nil, "Fix version(s), replaces current versions")
+	f.StringSliceP("component", "C", nil, "Component(s), replaces current components")
+	f.StringArrayP("field", "F", nil, "Field as 'name=value' or 'id=value', value may be JSON (repeatable)")
+	f.Bool("list-fields", false, "List the editable fields and exit")
+	f.Bool("dry-run", false, "Print the update payload without sending")
+}
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var epicCmd = &cobra.Command{
+	Use:   "epic",
+	Short: "Manage JIRA epics",
+	Long: `Commands for listing epics, tracking their progress and moving issues
+in and out of them.
+
+Children are found through the Epic Link field on Server/Data Center and
+through the parent field on Cloud.`,
+}
+
+var epicListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List epics in a project",
+	Aliases: []string{"ls"},
+	Example: `  atl epic list
+  atl epic list -p MYPROJ --all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		conditions := []string{"issuetype = Epic"}
+		if project := projectFromFlags(cmd); project != "" {
+			conditions = append(conditions, fmt.Sprintf("project = '%s'", escapeJQL(project)))
+		}
+		if all, _ := cmd.Flags().GetBool("all"); !all {
+			conditions = append(conditions, "statusCategory != Done")
+		}
+		jql := strings.Join(conditions, " AND ") + " ORDER BY created DESC"
+
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		epics, err := client.SearchIssues(ctx, jql, limit)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(epics)
+		}
+
+		if len(epics) == 0 {
+			fmt.Println("No epics found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tSTATUS\tSUMMARY")
+		for _, epic := range epics {
+			fmt.Fprintf(w, "%s\t%s\t%s\n",
+				epic.Key,
+				epic.Fields.Status.Name,
+				cmdutil.Truncate(epic.Fields.Summary, cmdutil.TitleTruncateLong),
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var epicViewCmd = &cobra.Command{
+	Use:   "view [epic-key]",
+	Short: "Show an epic's child issues and progress",
+	Long: `Show an epic's child issues grouped by status, with progress counted in
+issues and in story points.`,
+	Example: `  atl epic view MYPROJ-100
+  atl epic view 100 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		epicKey := prefixIssueKey(args[0], projectFromFlags(cmd))
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		epic, err := client.GetIssueFields(ctx, epicKey, "summary", "status", "issuetype")
+		if err != nil {
+			return err
+		}
+
+		agile, err := resolveAgileFields(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		jql, err := epicChildrenJQL(client.InstallationType, agile, epicKey)
+		if err != nil {
+			return err
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+		children, err := client.SearchIssues(ctx, jql+" ORDER BY rank ASC", limit)
+		if err != nil {
+			return err
+		}
+
+		progress := computeEpicProgress(children, agile.StoryPoints)
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				"epic":     epic,
+				"progress": progress,
+				"issues":   children,
+			})
+		}
+
+		fmt.Printf("Epic %s: %s [%s]\n", epic.Key, epic.Fields.Summary, epic.Fields.Status.Name)
+		fmt.Printf("Progress: %s\n", progress)
+
+		if len(children) == 0 {
+			fmt.Println("\nNo issues in this epic")
+			return nil
+		}
+
+		for _, group := range groupIssuesByStatus(children) {
+			fmt.Printf("\n%s (%d)\n", group.Status, len(group.Issues))
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, issue := range group.Issues {
+				assignee := "Unassigned"
+				if issue.Fields.Assignee != nil {
+					assignee = issue.Fields.Assignee.DisplayName
+				}
+				points := "-"
+				if p, ok := issue.Fields.CustomNumber(agile.StoryPoints); ok {
+					points = formatPoints(p)
+				}
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n",
+					issue.Key,
+					issue.Fields.IssueType.Name,
+					assignee,
+					points,
+					cmdutil.Truncate(issue.Fields.Summary, cmdutil.TitleTruncateNormal),
+				)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var epicAddCmd = &cobra.Command{
+	Use:   "add [epic-key] [issue-key...]",
+	Short: "Add issues to an epic",
+	Long: `Add issues to an epic, moving them out of any epic they were in.
+
+Bare issue numbers are prefixed with the default project.`,
+	Example: `  atl epic add MYPROJ-100 MYPROJ-123 MYPROJ-124
+  atl epic add 100 123 124`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		project := projectFromFlags(cmd)
+		epicKey := prefixIssueKey(args[0], project)
+		keys := prefixIssueKeys(args[1:], project)
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		if err := client.MoveIssuesToEpic(ctx, epicKey, keys); err != nil {
+			return err
+		}
+
+		fmt.Printf("Added %s to %s\n", strings.Join(keys, ", "), epicKey)
+		return nil
+	},
+}
+
+var epicRemoveCmd = &cobra.Command{
+	Use:     "remove [issue-key...]",
+	Short:   "Remove issues from their epic",
+	Aliases: []string{"rm"},
+	Example: `  atl epic remove MYPROJ-123 MYPROJ-124`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		keys := prefixIssueKeys(args, projectFromFlags(cmd))
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		if err := client.RemoveIssuesFromEpic(ctx, keys); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed %s from their epic\n", strings.Join(keys, ", "))
+		return nil
+	},
+}
+
+func prefixIssueKeys(keys []string, project string) []string {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = prefixIssueKey(key, project)
+	}
+	return prefixed
+}
+
+// epicChildrenJQL selects an epic's children. Cloud links children to
+// epics through the parent field; Server/Data Center uses Epic Link.
+func epicChildrenJQL(installation api.InstallationType, agile agileFieldIDs, epicKey string) (string, error) {
+	if installation == api.InstallationTypeCloud {
+		return fmt.Sprintf("parent = '%s'", escapeJQL(epicKey)), nil
+	}
+	if agile.EpicLink == "" {
+		return "", fmt.Errorf("epic link field not found on this JIRA instance")
+	}
+	return fmt.Sprintf(`"Epic Link" = '%s'`, escapeJQL(epicKey)), nil
+}
+
+type epicProgress struct {
+	Issues     int     `json:"issues"`
+	DoneIssues int     `json:"done_issues"`
+	Points     float64 `json:"points"`
+	DonePoints float64 `json:"done_points"`
+	// Estimated reports whether any child carried story points.
+	Estimated bool `json:"estimated"`
+}
+
+// computeEpicProgress counts children and their story points; an issue is
+// done when its status is in the Done category.
+func computeEpicProgress(issues []api.Issue, pointsField string) epicProgress {
+	var p epicProgress
+	for _, issue := range issues {
+		done := issue.Fields.Status.StatusCategory != nil && issue.Fields.Status.StatusCategory.Key == "done"
+
+		p.Issues++
+		if done {
+			p.DoneIssues++
+		}
+		if points, ok := issue.Fields.CustomNumber(pointsField); ok {
+			p.Estimated = true
+			p.Points += points
+			if done {
+				p.DonePoints += points
+			}
+		}
+	}
+	return p
+}
+
+func (p epicProgress) String() string {
+	s := fmt.Sprintf("%d/%d issues done (%d%%)", p.DoneIssues, p.Issues, percent(float64(p.DoneIssues), float64(p.Issues)))
+	if p.Estimated {
+		s += fmt.Sprintf(", %s/%s story points (%d%%)", formatPoints(p.DonePoints), formatPoints(p.Points), percent(p.DonePoints, p.Points))
+	}
+	return s
+}
+
+func percent(part, total float64) int {
+	if total == 0 {
+		return 0
+	}
+	return int(part / total * 100)
+}
+
+func formatPoints(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+func init() {
+	rootCmd.AddCommand(epicCmd)
+	epicCmd.AddCommand(epicListCmd)
+	epicCmd.AddCommand(epicViewCmd)
+	epicCmd.AddCommand(epicAddCmd)
+	epicCmd.AddCommand(epicRemoveCmd)
+
+	epicCmd.PersistentFlags().StringP("project", "p", "", "Project key (default from config)")
+
+	epicListCmd.Flags().Bool("all", false, "Include done epics")
+	epicListCmd.Flags().Int("limit", cmdutil.DefaultLimit, "Maximum number of epics")
+	epicListCmd.Flags().Bool("json", false, "Output as JSON")
+
+	epicViewCmd.Flags().Int("limit", 200, "Maximum number of child issues")
+	epicViewCmd.Flags().Bool("json", false, "Output as JSON")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestComputeEpicProgress(t *testing.T) {
+	var issues []api.Issue
+	data := `[
+		{"key": "P-1", "fields": {"status": {"name": "Done", "statusCategory": {"key": "done"}}, "customfield_10103": 5}},
+		{"key": "P-2", "fields": {"status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}}, "customfield_10103": 3}},
+		{"key": "P-3", "fields": {"status": {"name": "Closed", "statusCategory": {"key": "done"}}, "customfield_10103": null}},
+		{"key": "P-4", "fields": {"status": {"name": "To Do", "statusCategory": {"key": "new"}}, "customfield_10103": 0.5}}
+	]`
+	if err := json.Unmarshal([]byte(data), &issues); err != nil {
+		t.Fatalf("decoding issues: %v", err)
+	}
+
+	got := computeEpicProgress(issues, "customfield_10103")
+	want := epicProgress{Issues: 4, DoneIssues: 2, Points: 8.5, DonePoints: 5, Estimated: true}
+	if got != want {
+		t.Errorf("progress = %+v, want %+v", got, want)
+	}
+	if s := got.String(); s != "2/4 issues done (50%), 5/8.5 story points (58%)" {
+		t.Errorf("String() = %q", s)
+	}
+
+	unestimated := computeEpicProgress(issues, "")
+	if s := unestimated.String(); s != "2/4 issues done (50%)" {
+		t.Errorf("String() without points field = %q", s)
+	}
+}
+
+func TestEpicChildrenJQL(t *testing.T) {
+	agile := agileFieldIDs{EpicLink: "customfield_10501"}
+
+	tests := []struct {
+		installation api.InstallationType
+		agile        agileFieldIDs
+		want         string
+		wantErr      bool
+	}{
+		{api.InstallationTypeCloud, agileFieldIDs{}, "parent = 'P-100'", false},
+		{api.InstallationTypeServer, agile, `"Epic Link" = 'P-100'`, false},
+		{api.InstallationTypeServer, agileFieldIDs{}, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := epicChildrenJQL(tt.installation, tt.agile, "P-100")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.installation, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: jql = %q, want %q", tt.installation, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// issueListArgs allows a search text, optionally preceded by an @query.
+func issueListArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 2 && !strings.HasPrefix(args[0], "@") {
+		return fmt.Errorf("accepts at most 1 arg(s) unless the first is an @query, received 2")
+	}
+	return cobra.MaximumNArgs(2)(cmd, args)
+}
+
+// namedQueries reads jira.queries from config. Viper lowercases keys, so
+// lookups are case-insensitive.
+func namedQueries() map[string]string {
+	return viper.GetStringMapString("jira.queries")
+}
+
+// resolveNamedQuery looks up jira.queries.<name> and fills its
+// placeholders from name=value vars, with {project} defaulting to project.
+func resolveNamedQuery(name string, vars []string, project string) (string, error) {
+	queries := namedQueries()
+	query, ok := queries[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(queries))
+		for n := range queries {
+			names = append(names, "@"+n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return "", fmt.Errorf("unknown query @%s: no jira.queries defined in config", name)
+		}
+		return "", fmt.Errorf("unknown query @%s (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	values := map[string]string{}
+	if project != "" {
+		values["project"] = project
+	}
+	for _, v := range vars {
+		key, val, ok := strings.Cut(v, "=")
+		if !ok {
+			return "", fmt.Errorf("invalid --var %q, expected name=value", v)
+		}
+		values[key] = val
+	}
+
+	expanded, err := expandQueryParams(query, values)
+	if err != nil {
+		return "", fmt.Errorf("query @%s: %w", name, err)
+	}
+	return expanded, nil
+}
+
+var queryParamPattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_-]*)(?::([^}]*))?\}`)
+
+// expandQueryParams replaces {name} and {name:default} placeholders.
+// Values are JQL-escaped for use inside single quotes, which is where
+// placeholders for user input belong: assignee = '{user}'.
+func expandQueryParams(query string, values map[string]string) (string, error) {
+	var missing []string
+	expanded := queryParamPattern.ReplaceAllStringFunc(query, func(m string) string {
+		sub := queryParamPattern.FindStringSubmatch(m)
+		name, def := sub[1], sub[2]
+		if val, ok := values[name]; ok {
+			return escapeJQL(val)
+		}
+		if strings.Contains(m, ":") {
+			return escapeJQL(def)
+		}
+		missing = append(missing, name)
+		return m
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing value for %s (use --var name=value)", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+var orderByPattern = regexp.MustCompile(`(?i)\s*\bORDER\s+BY\b\s*`)
+
+// splitOrderBy separates a JQL query's conditions from its ORDER BY clause.
+func splitOrderBy(jql string) (string, string) {
+	loc := orderByPattern.FindStringIndex(jql)
+	if loc == nil {
+		return strings.TrimSpace(jql), ""
+	}
+	return strings.TrimSpace(jql[:loc[0]]), strings.TrimSpace(jql[loc[1]:])
+}
+
+var issueFilterCmd = &cobra.Command{
+	Use:   "filter",
+	Short: "List and run saved JIRA filters",
+	Long: `List and run the saved filters shared in the JIRA UI.
+
+Filters are referenced by ID or by name; names are looked up among your
+favourite filters (and, on Cloud, all filters visible to you).`,
+}
+
+var filterListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List favourite filters and configured named queries",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		filters, err := client.GetFavouriteFilters(ctx)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				"filters": filters,
+				"queries": namedQueries(),
+			})
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if len(filters) == 0 {
+			fmt.Fprintln(w, "No favourite filters")
+		} else {
+			fmt.Fprintln(w, "ID\tNAME\tOWNER\tJQL")
+			for _, f := range filters {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.ID, f.Name, f.Owner.DisplayName, cmdutil.Truncate(f.JQL, cmdutil.TitleTruncateLong))
+			}
+		}
+
+		if queries := namedQueries(); len(queries) > 0 {
+			names := make([]string, 0, len(queries))
+			for n := range queries {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "QUERY\tJQL")
+			for _, n := range names {
+				fmt.Fprintf(w, "@%s\t%s\n", n, queries[n])
+			}
+		}
+		return w.Flush()
+	},
+}
+
+var filterRunCmd = &cobra.Command{
+	Use:   "run [id|name]",
+	Short: "Run a saved filter",
+	Example: `  atl issue filter run 12345
+  atl issue filter run "Team backlog" --limit 50
+  atl issue filter run "Team backlog" -q "assignee = currentUser()"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		filter, err := findFilter(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+
+		jql := filter.JQL
+		if extra, _ := cmd.Flags().GetString("jql"); extra != "" {
+			conditions, order := splitOrderBy(filter.JQL)
+			jql = fmt.Sprintf("(%s) AND (%s)", conditions, extra)
+			if conditions == "" {
+				jql = extra
+			}
+			if order != "" {
+				jql += " ORDER BY " + order
+			}
+		}
+
+		if showJQL, _ := cmd.Flags().GetBool("show-jql"); showJQL {
+			fmt.Fprintf(os.Stderr, "Filter %s (%s): %s\n", filter.ID, filter.Name, jql)
+		}
+		return searchAndPrintIssues(cmd, jql)
+	},
+}
+
+// findFilter resolves a filter ID or name.
+func findFilter(ctx context.Context, client *api.JiraClient, ref string) (*api.Filter, error) {
+	if isNumeric(ref) {
+		return client.GetFilter(ctx, ref)
+	}
+
+	filters, err := client.GetFavouriteFilters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := matchFilter(filters, ref)
+	if err == nil || client.InstallationType != api.InstallationTypeCloud {
+		return filter, err
+	}
+
+	var ambiguous *ambiguousFilterError
+	if errors.As(err, &ambiguous) {
+		return nil, err
+	}
+	found, searchErr := client.SearchFilters(ctx, ref)
+	if searchErr != nil {
+		return nil, err
+	}
+	return matchFilter(found, ref)
+}
+
+type ambiguousFilterError struct {
+	Name    string
+	Matches []api.Filter
+}
+
+func (e *ambiguousFilterError) Error() string {
+	names := make([]string, len(e.Matches))
+	for i, f := range e.Matches {
+		names[i] = fmt.Sprintf("%q (%s)", f.Name, f.ID)
+	}
+	return fmt.Sprintf("filter %q is ambiguous: %s", e.Name, strings.Join(names, ", "))
+}
+
+// matchFilter picks the filter named name: an exact (case-insensitive)
+// match, else the only filter whose name contains it.
+func matchFilter(filters []api.Filter, name string) (*api.Filter, error) {
+	var partial []api.Filter
+	for i, f := range filters {
+		if strings.EqualFold(f.Name, name) {
+			return &filters[i], nil
+		}
+		if strings.Contains(strings.ToLower(f.Name), strings.ToLower(name)) {
+			partial = append(partial, f)
+		}
+	}
+
+	switch len(partial) {
+	case 0:
+		return nil, fmt.Errorf("no saved filter named %q (use its ID, or star it in JIRA)", name)
+	case 1:
+		return &partial[0], nil
+	}
+	return nil, &ambiguousFilterError{Name: name, Matches: partial}
+}
+
+func init() {
+	issueCmd.AddCommand(issueFilterCmd)
+	issueFilterCmd.AddCommand(filterListCmd)
+	issueFilterCmd.AddCommand(filterRunCmd)
+
+	filterListCmd.Flags().Bool("json", false, "Output as JSON")
+
+	filterRunCmd.Flags().StringP("jql", "q", "", "Additional JQL conditions")
+	filterRunCmd.Flags().Int("limit", cmdutil.DefaultLimit, "Maximum number of results")
+	filterRunCmd.Flags().Bool("show-jql", false, "Print the filter's JQL to stderr")
+}
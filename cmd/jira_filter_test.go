@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/spf13/viper"
+)
+
+func TestExpandQueryParams(t *testing.T) {
+	tests := []struct {
+		query   string
+		values  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{"assignee = currentUser()", nil, "assignee = currentUser()", false},
+		{"project = '{project}' AND team = '{team}'", map[string]string{"project": "P", "team": "O'Neil"}, "project = 'P' AND team = 'O''Neil'", false},
+		{"created >= {since:-14d}", nil, "created >= -14d", false},
+		{"created >= {since:-14d}", map[string]string{"since": "-7d"}, "created >= -7d", false},
+		{"team = '{team}' AND sprint = {sprint}", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := expandQueryParams(tt.query, tt.values)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("expandQueryParams(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("expandQueryParams(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestResolveNamedQuery(t *testing.T) {
+	viper.Set("jira.queries", map[string]interface{}{
+		"mine": "assignee = currentUser() AND resolution = EMPTY ORDER BY priority DESC",
+		"team": "project = '{project}' AND labels = '{label}'",
+	})
+	defer viper.Set("jira.queries", nil)
+
+	got, err := resolveNamedQuery("Mine", nil, "")
+	if err != nil || got != "assignee = currentUser() AND resolution = EMPTY ORDER BY priority DESC" {
+		t.Errorf("@mine = %q, %v", got, err)
+	}
+
+	got, err = resolveNamedQuery("team", []string{"label=backend"}, "PROJ")
+	if err != nil || got != "project = 'PROJ' AND labels = 'backend'" {
+		t.Errorf("@team = %q, %v", got, err)
+	}
+
+	if _, err := resolveNamedQuery("team", nil, "PROJ"); err == nil {
+		t.Error("expected error for missing {label}")
+	}
+	if _, err := resolveNamedQuery("nope", nil, ""); err == nil {
+		t.Error("expected error for unknown query")
+	}
+	if _, err := resolveNamedQuery("team", []string{"label"}, "PROJ"); err == nil {
+		t.Error("expected error for malformed --var")
+	}
+}
+
+func TestSplitOrderBy(t *testing.T) {
+	tests := []struct {
+		jql, conditions, order string
+	}{
+		{"assignee = currentUser()", "assignee = currentUser()", ""},
+		{"assignee = currentUser() order by priority DESC", "assignee = currentUser()", "priority DESC"},
+		{"ORDER BY created", "", "created"},
+	}
+
+	for _, tt := range tests {
+		conditions, order := splitOrderBy(tt.jql)
+		if conditions != tt.conditions || order != tt.order {
+			t.Errorf("splitOrderBy(%q) = %q, %q; want %q, %q", tt.jql, conditions, order, tt.conditions, tt.order)
+		}
+	}
+}
+
+func TestMatchFilter(t *testing.T) {
+	filters := []api.Filter{
+		{ID: "1", Name: "Team backlog"},
+		{ID: "2", Name: "Team bugs"},
+		{ID: "3", Name: "Release blockers"},
+	}
+
+	if f, err := matchFilter(filters, "team BUGS"); err != nil || f.ID != "2" {
+		t.Errorf("exact match = %v, %v", f, err)
+	}
+	if f, err := matchFilter(filters, "blockers"); err != nil || f.ID != "3" {
+		t.Errorf("partial match = %v, %v", f, err)
+	}
+
+	var ambiguous *ambiguousFilterError
+	if _, err := matchFilter(filters, "team"); !errors.As(err, &ambiguous) || len(ambiguous.Matches) != 2 {
+		t.Errorf("ambiguous match error = %v", err)
+	}
+	if _, err := matchFilter(filters, "nothing"); err == nil {
+		t.Error("expected error for no match")
+	}
+}
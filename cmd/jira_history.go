@@ -0,0 +1,461 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var issueHistoryCmd = &cobra.Command{
+	Use:   "history [issue-key]",
+	Short: "Show the change history of an issue",
+	Long: `Show every field change on an issue, oldest first, followed by how
+long the issue spent in each status.
+
+Time in status counts from creation; the current status runs until now.`,
+	Example: `  atl issue history MYPROJ-123
+  atl issue history MYPROJ-123 --field status --field assignee
+  atl issue history MYPROJ-123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueHistory,
+}
+
+func runIssueHistory(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	fields, _ := cmd.Flags().GetStringSlice("field")
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	issue, err := client.GetIssueWithChangelog(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	histories := filterHistories(sortedHistories(issue), fields)
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(histories)
+	}
+
+	fmt.Printf("%s %s\n", bold(issue.Key), issue.Fields.Summary)
+	fmt.Printf("%s  created\n", dim(formatJiraTime(issue.Fields.Created)))
+	for _, h := range histories {
+		fmt.Printf("%s  %s\n", dim(formatJiraTime(h.Created)), valueOr(h.Author.DisplayName, h.Author.Name))
+		for _, item := range h.Items {
+			fmt.Printf("  %s: %s → %s\n", item.Field, valueOr(item.FromString, "(none)"), valueOr(item.ToString, "(none)"))
+		}
+	}
+
+	periods := statusPeriods(issue, time.Now())
+	if len(periods) == 0 {
+		return nil
+	}
+	printSection("Time in status")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	current := periods[len(periods)-1].Status
+	for _, s := range timeInStatus(periods) {
+		note := ""
+		if s.Status == current {
+			note = dim("(current)")
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", s.Status, formatElapsed(s.Duration), note)
+	}
+	return w.Flush()
+}
+
+var issueCycleTimeCmd = &cobra.Command{
+	Use:   "cycle-time",
+	Short: "Lead and cycle time across issues",
+	Long: `Measure lead and cycle time for the issues a JQL query returns.
+
+Lead time runs from creation to resolution. Cycle time runs from the
+first move into an In Progress-category status (or --start-status) to
+resolution. Resolution is the resolution date, or the last move into a
+Done-category status for workflows that do not set one. Unfinished
+issues have no lead or cycle time and are left out of the summary.
+
+--json prints one flat object per issue with times in hours, ready for
+jq -r '.[] | [.key, .lead_time_hours] | @csv' or a spreadsheet.`,
+	Example: `  atl issue cycle-time -q "project = MYPROJ AND resolved >= -14d"
+  atl issue cycle-time -q "sprint = 42" --start-status "In Review"
+  atl issue cycle-time -q "fixVersion = 1.4" --json`,
+	Args: cobra.NoArgs,
+	RunE: runIssueCycleTime,
+}
+
+func runIssueCycleTime(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	jql, _ := cmd.Flags().GetString("jql")
+	limit, _ := cmd.Flags().GetInt("limit")
+	startStatus, _ := cmd.Flags().GetString("start-status")
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	statuses, err := client.GetStatuses(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching statuses: %w", err)
+	}
+	categories := statusCategories(statuses)
+
+	issues, err := client.SearchIssuesWithChangelog(ctx, jql, limit)
+	if err != nil {
+		return err
+	}
+
+	cycles := make([]issueCycle, len(issues))
+	for i := range issues {
+		cycles[i] = measureCycle(&issues[i], categories, startStatus)
+	}
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		rows := make([]cycleRow, len(cycles))
+		for i, c := range cycles {
+			rows[i] = c.row()
+		}
+		return json.NewEncoder(os.Stdout).Encode(rows)
+	}
+
+	if len(cycles) == 0 {
+		fmt.Println("No issues found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tTYPE\tCREATED\tSTARTED\tDONE\tLEAD\tCYCLE")
+	var leads, cycleTimes []time.Duration
+	for _, c := range cycles {
+		lead, cycle := "-", "-"
+		if d, ok := c.lead(); ok {
+			lead = formatElapsed(d)
+			leads = append(leads, d)
+		}
+		if d, ok := c.cycle(); ok {
+			cycle = formatElapsed(d)
+			cycleTimes = append(cycleTimes, d)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			c.Key, c.Type, formatDay(c.Created), formatDay(c.Started), formatDay(c.Done), lead, cycle)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tISSUES\tAVERAGE\tMEDIAN\t85TH PCT")
+	for _, s := range []struct {
+		label string
+		times []time.Duration
+	}{{"Lead time", leads}, {"Cycle time", cycleTimes}} {
+		if len(s.times) == 0 {
+			fmt.Fprintf(w, "%s\t0\t-\t-\t-\n", s.label)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", s.label, len(s.times),
+			formatElapsed(meanDuration(s.times)), formatElapsed(percentile(s.times, 50)), formatElapsed(percentile(s.times, 85)))
+	}
+	return w.Flush()
+}
+
+// statusPeriod is one stretch an issue spent in a status.
+type statusPeriod struct {
+	Status     string
+	Start, End time.Time
+}
+
+type statusDuration struct {
+	Status   string
+	Duration time.Duration
+}
+
+// sortedHistories returns the changelog oldest first; Cloud embeds it
+// newest first.
+func sortedHistories(issue *api.Issue) []api.ChangeHistory {
+	if issue.Changelog == nil {
+		return nil
+	}
+	histories := append([]api.ChangeHistory(nil), issue.Changelog.Histories...)
+	sort.SliceStable(histories, func(i, j int) bool {
+		return parseJiraTime(histories[i].Created).Before(parseJiraTime(histories[j].Created))
+	})
+	return histories
+}
+
+// filterHistories keeps the changes to the given fields; none keeps all.
+func filterHistories(histories []api.ChangeHistory, fields []string) []api.ChangeHistory {
+	if len(fields) == 0 {
+		return histories
+	}
+	var kept []api.ChangeHistory
+	for _, h := range histories {
+		var items []api.ChangelogItem
+		for _, item := range h.Items {
+			for _, f := range fields {
+				if strings.EqualFold(item.Field, f) {
+					items = append(items, item)
+					break
+				}
+			}
+		}
+		if len(items) > 0 {
+			h.Items = items
+			kept = append(kept, h)
+		}
+	}
+	return kept
+}
+
+// statusChanges returns the status items of the changelog, oldest first,
+// with the time each happened.
+func statusChanges(issue *api.Issue) ([]api.ChangelogItem, []time.Time) {
+	var items []api.ChangelogItem
+	var times []time.Time
+	for _, h := range sortedHistories(issue) {
+		for _, item := range h.Items {
+			if item.Field == "status" {
+				items = append(items, item)
+				times = append(times, parseJiraTime(h.Created))
+			}
+		}
+	}
+	return items, times
+}
+
+// statusPeriods splits an issue's life into the statuses it went through.
+// The first status is where the first transition came from, or the
+// current status if it never moved.
+func statusPeriods(issue *api.Issue, now time.Time) []statusPeriod {
+	created := parseJiraTime(issue.Fields.Created)
+	if created.IsZero() {
+		return nil
+	}
+
+	items, times := statusChanges(issue)
+	status := issue.Fields.Status.Name
+	if len(items) > 0 {
+		status = items[0].FromString
+	}
+
+	var periods []statusPeriod
+	start := created
+	for i, item := range items {
+		periods = append(periods, statusPeriod{Status: status, Start: start, End: times[i]})
+		status, start = item.ToString, times[i]
+	}
+	return append(periods, statusPeriod{Status: status, Start: start, End: now})
+}
+
+// timeInStatus totals the periods per status, in the order each status
+// was first reached.
+func timeInStatus(periods []statusPeriod) []statusDuration {
+	var totals []statusDuration
+	index := map[string]int{}
+	for _, p := range periods {
+		i, ok := index[p.Status]
+		if !ok {
+			i = len(totals)
+			index[p.Status] = i
+			totals = append(totals, statusDuration{Status: p.Status})
+		}
+		totals[i].Duration += p.End.Sub(p.Start)
+	}
+	return totals
+}
+
+// issueCycle holds the milestones lead and cycle time are measured
+// between. Zero times were never reached.
+type issueCycle struct {
+	Key, Type, Summary     string
+	Created, Started, Done time.Time
+}
+
+func (c issueCycle) lead() (time.Duration, bool) {
+	if c.Created.IsZero() || c.Done.IsZero() {
+		return 0, false
+	}
+	return c.Done.Sub(c.Created), true
+}
+
+func (c issueCycle) cycle() (time.Duration, bool) {
+	if c.Started.IsZero() || c.Done.IsZero() {
+		return 0, false
+	}
+	return c.Done.Sub(c.Started), true
+}
+
+// cycleRow is the --json shape: flat, with the same keys on every row.
+type cycleRow struct {
+	Key            string   `json:"key"`
+	Type           string   `json:"type"`
+	Summary        string   `json:"summary"`
+	Created        string   `json:"created"`
+	Started        string   `json:"started"`
+	Resolved       string   `json:"resolved"`
+	LeadTimeHours  *float64 `json:"lead_time_hours"`
+	CycleTimeHours *float64 `json:"cycle_time_hours"`
+}
+
+func (c issueCycle) row() cycleRow {
+	row := cycleRow{
+		Key:      c.Key,
+		Type:     c.Type,
+		Summary:  c.Summary,
+		Created:  formatRFC3339(c.Created),
+		Started:  formatRFC3339(c.Started),
+		Resolved: formatRFC3339(c.Done),
+	}
+	if d, ok := c.lead(); ok {
+		h := hours(d)
+		row.LeadTimeHours = &h
+	}
+	if d, ok := c.cycle(); ok {
+		h := hours(d)
+		row.CycleTimeHours = &h
+	}
+	return row
+}
+
+// statusCategories maps status IDs to their category keys; changelog
+// items name statuses by ID in From and To.
+func statusCategories(statuses []api.Status) map[string]string {
+	categories := make(map[string]string, len(statuses))
+	for _, s := range statuses {
+		if s.StatusCategory != nil {
+			categories[s.ID] = s.StatusCategory.Key
+		}
+	}
+	return categories
+}
+
+// measureCycle finds when an issue started and finished. Work starts at
+// the first move into startStatus, or into any In Progress-category
+// status when startStatus is empty. An unresolved issue that is not in a
+// Done-category status has no finish, even if it was once done.
+func measureCycle(issue *api.Issue, categories map[string]string, startStatus string) issueCycle {
+	c := issueCycle{
+		Key:     issue.Key,
+		Type:    issue.Fields.IssueType.Name,
+		Summary: issue.Fields.Summary,
+		Created: parseJiraTime(issue.Fields.Created),
+		Done:    parseJiraTime(issue.Fields.Resolved),
+	}
+
+	items, times := statusChanges(issue)
+	var lastDone time.Time
+	for i, item := range items {
+		started := categories[item.To] == "indeterminate"
+		if startStatus != "" {
+			started = strings.EqualFold(item.ToString, startStatus)
+		}
+		if started && c.Started.IsZero() {
+			c.Started = times[i]
+		}
+		if categories[item.To] == "done" {
+			lastDone = times[i]
+		}
+	}
+
+	currentCategory := ""
+	if issue.Fields.Status.StatusCategory != nil {
+		currentCategory = issue.Fields.Status.StatusCategory.Key
+	}
+	if c.Done.IsZero() && currentCategory == "done" {
+		c.Done = lastDone
+	}
+	// Moving straight from To Do to Done skips the work-in-progress step;
+	// count that as no cycle time rather than a negative one.
+	if !c.Done.IsZero() && c.Started.After(c.Done) {
+		c.Started = time.Time{}
+	}
+	return c
+}
+
+func parseJiraTime(s string) time.Time {
+	t, _ := time.Parse(api.JiraTimeLayout, s)
+	return t
+}
+
+func formatDay(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(dateLayout)
+}
+
+func formatRFC3339(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
+
+// formatElapsed shows a duration in its two largest units: "3d 4h",
+// "5h 10m", "12m".
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+	days := int(d / (24 * time.Hour))
+	h := int(d % (24 * time.Hour) / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	switch {
+	case days > 0 && h > 0:
+		return fmt.Sprintf("%dd %dh", days, h)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case h > 0 && m > 0:
+		return fmt.Sprintf("%dh %dm", h, m)
+	case h > 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dm", m)
+	}
+}
+
+func meanDuration(ds []time.Duration) time.Duration {
+	var total time.Duration
+	for _, d := range ds {
+		total += d
+	}
+	return total / time.Duration(len(ds))
+}
+
+// percentile uses the nearest-rank method, so the result is always one of
+// the measured durations.
+func percentile(ds []time.Duration, p int) time.Duration {
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func init() {
+	issueCmd.AddCommand(issueHistoryCmd)
+	issueCmd.AddCommand(issueCycleTimeCmd)
+
+	issueHistoryCmd.Flags().StringSlice("field", nil, "Only show changes to these fields")
+	issueHistoryCmd.Flags().Bool("json", false, "Output the changelog as JSON")
+
+	issueCycleTimeCmd.Flags().StringP("jql", "q", "", "JQL selecting the issues to measure")
+	issueCycleTimeCmd.Flags().Int("limit", 100, "Maximum number of issues")
+	issueCycleTimeCmd.Flags().String("start-status", "", "Status that starts the cycle (default: first In Progress-category status)")
+	issueCycleTimeCmd.Flags().Bool("json", false, "Output one flat row per issue as JSON")
+	_ = issueCycleTimeCmd.MarkFlagRequired("jql")
+}
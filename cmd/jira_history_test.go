@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func historyIssue(status, category, resolved string, changes ...[3]string) *api.Issue {
+	issue := &api.Issue{
+		Key: "MYPROJ-1",
+		Fields: api.IssueFields{
+			Created:  "2024-05-01T09:00:00.000+0000",
+			Resolved: resolved,
+			Status:   api.Status{Name: status, StatusCategory: &api.StatusCategory{Key: category}},
+		},
+		Changelog: &api.Changelog{},
+	}
+	// Each change is {created, from, to}; status IDs are the names.
+	for i := len(changes) - 1; i >= 0; i-- { // newest first, as Cloud sends
+		c := changes[i]
+		issue.Changelog.Histories = append(issue.Changelog.Histories, api.ChangeHistory{
+			Created: c[0],
+			Items: []api.ChangelogItem{
+				{Field: "assignee", ToString: "Jane"},
+				{Field: "status", From: c[1], FromString: c[1], To: c[2], ToString: c[2]},
+			},
+		})
+	}
+	return issue
+}
+
+var historyCategories = map[string]string{
+	"Open": "new", "In Progress": "indeterminate", "In Review": "indeterminate", "Done": "done",
+}
+
+func TestTimeInStatus(t *testing.T) {
+	issue := historyIssue("In Progress", "indeterminate", "",
+		[3]string{"2024-05-02T09:00:00.000+0000", "Open", "In Progress"},
+		[3]string{"2024-05-02T13:00:00.000+0000", "In Progress", "In Review"},
+		[3]string{"2024-05-03T09:00:00.000+0000", "In Review", "In Progress"},
+	)
+	now := time.Date(2024, 5, 6, 13, 0, 0, 0, time.UTC)
+
+	got := timeInStatus(statusPeriods(issue, now))
+	want := []statusDuration{
+		{"Open", 24 * time.Hour},
+		{"In Progress", 4*time.Hour + 76*time.Hour},
+		{"In Review", 20 * time.Hour},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("timeInStatus = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("timeInStatus[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStatusPeriodsWithoutTransitions(t *testing.T) {
+	issue := historyIssue("Open", "new", "")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	got := statusPeriods(issue, now)
+	if len(got) != 1 || got[0].Status != "Open" || got[0].End.Sub(got[0].Start) != 3*time.Hour {
+		t.Errorf("statusPeriods = %+v", got)
+	}
+}
+
+func TestMeasureCycle(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2024, 5, d, h, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name        string
+		issue       *api.Issue
+		startStatus string
+		lead, cycle time.Duration // 0: not measured
+	}{
+		{
+			name: "resolved",
+			issue: historyIssue("Done", "done", "2024-05-04T09:00:00.000+0000",
+				[3]string{"2024-05-02T09:00:00.000+0000", "Open", "In Progress"},
+				[3]string{"2024-05-03T09:00:00.000+0000", "In Progress", "In Review"},
+				[3]string{"2024-05-04T09:00:00.000+0000", "In Review", "Done"},
+			),
+			lead:  day(4, 9).Sub(day(1, 9)),
+			cycle: day(4, 9).Sub(day(2, 9)),
+		},
+		{
+			name: "custom start status",
+			issue: historyIssue("Done", "done", "2024-05-04T09:00:00.000+0000",
+				[3]string{"2024-05-02T09:00:00.000+0000", "Open", "In Progress"},
+				[3]string{"2024-05-03T09:00:00.000+0000", "In Progress", "In Review"},
+				[3]string{"2024-05-04T09:00:00.000+0000", "In Review", "Done"},
+			),
+			startStatus: "in review",
+			lead:        day(4, 9).Sub(day(1, 9)),
+			cycle:       24 * time.Hour,
+		},
+		{
+			name: "done without resolution date",
+			issue: historyIssue("Done", "done", "",
+				[3]string{"2024-05-02T09:00:00.000+0000", "Open", "In Progress"},
+				[3]string{"2024-05-03T09:00:00.000+0000", "In Progress", "Done"},
+			),
+			lead:  48 * time.Hour,
+			cycle: 24 * time.Hour,
+		},
+		{
+			name: "reopened",
+			issue: historyIssue("In Progress", "indeterminate", "",
+				[3]string{"2024-05-02T09:00:00.000+0000", "Open", "Done"},
+				[3]string{"2024-05-03T09:00:00.000+0000", "Done", "In Progress"},
+			),
+		},
+		{
+			name: "straight to done",
+			issue: historyIssue("Done", "done", "2024-05-02T09:00:00.000+0000",
+				[3]string{"2024-05-02T09:00:00.000+0000", "Open", "Done"},
+			),
+			lead: 24 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := measureCycle(tt.issue, historyCategories, tt.startStatus)
+			if lead, _ := c.lead(); lead != tt.lead {
+				t.Errorf("lead = %v, want %v", lead, tt.lead)
+			}
+			if cycle, _ := c.cycle(); cycle != tt.cycle {
+				t.Errorf("cycle = %v, want %v", cycle, tt.cycle)
+			}
+		})
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "<1m"},
+		{12 * time.Minute, "12m"},
+		{5*time.Hour + 10*time.Minute, "5h 10m"},
+		{2 * time.Hour, "2h"},
+		{76*time.Hour + 30*time.Minute, "3d 4h"},
+		{48 * time.Hour, "2d"},
+	}
+
+	for _, tt := range tests {
+		if got := formatElapsed(tt.d); got != tt.want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	ds := []time.Duration{5, 1, 4, 2, 3}
+	if got := percentile(ds, 50); got != 3 {
+		t.Errorf("median = %v, want 3", got)
+	}
+	if got := percentile(ds, 85); got != 5 {
+		t.Errorf("p85 = %v, want 5", got)
+	}
+	if got := meanDuration(ds); got != 3 {
+		t.Errorf("mean = %v, want 3", got)
+	}
+}
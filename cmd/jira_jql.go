@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var relativeDatePattern = regexp.MustCompile(`^-?(\d+)([wdhm])$`)
+
+// relativeDatePeriods maps period keywords to the JQL functions bounding
+// them; an empty end means "until now".
+var relativeDatePeriods = map[string][2]string{
+	"today":      {"startOfDay()", ""},
+	"yesterday":  {"startOfDay(-1)", "startOfDay()"},
+	"week":       {"startOfWeek()", ""},
+	"last-week":  {"startOfWeek(-1)", "startOfWeek()"},
+	"month":      {"startOfMonth()", ""},
+	"last-month": {"startOfMonth(-1)", "startOfMonth()"},
+	"year":       {"startOfYear()", ""},
+}
+
+// formatDateCondition turns a --created/--updated/--resolved value into
+// JQL. Accepted values are relative durations (-7d, 2w, 4h), period
+// keywords (today, yesterday, week, last-week, month, last-month, year),
+// dates (2024-05-01) and inclusive ranges (2024-05-01..2024-05-31, with
+// either end open). A ~ prefix negates the condition; JQL's NOT skips
+// issues where the field is empty (unresolved ones for resolved), so
+// those are added back.
+func formatDateCondition(field, val string) (string, error) {
+	negate := strings.HasPrefix(val, "~")
+	val = strings.TrimPrefix(val, "~")
+
+	cond, err := dateRangeCondition(field, val)
+	if err != nil {
+		return "", err
+	}
+	if negate {
+		return fmt.Sprintf("(NOT (%s) OR %s IS EMPTY)", cond, field), nil
+	}
+	return cond, nil
+}
+
+func dateRangeCondition(field, val string) (string, error) {
+	if m := relativeDatePattern.FindStringSubmatch(val); m != nil {
+		return fmt.Sprintf("%s >= -%s%s", field, m[1], m[2]), nil
+	}
+	if period, ok := relativeDatePeriods[strings.ToLower(val)]; ok {
+		return boundedCondition(field, period[0], period[1]), nil
+	}
+
+	from, to, isRange := strings.Cut(val, "..")
+	if !isRange {
+		to = from
+	}
+	var start, end string
+	if from != "" {
+		d, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return "", invalidDateError(field, val)
+		}
+		start = fmt.Sprintf("'%s'", d.Format(dateLayout))
+	}
+	if to != "" {
+		d, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return "", invalidDateError(field, val)
+		}
+		// Dates compare as midnight, so an inclusive end is the next day.
+		end = fmt.Sprintf("'%s'", d.AddDate(0, 0, 1).Format(dateLayout))
+	}
+	if start == "" && end == "" {
+		return "", invalidDateError(field, val)
+	}
+	return boundedCondition(field, start, end), nil
+}
+
+func boundedCondition(field, start, end string) string {
+	var parts []string
+	if start != "" {
+		parts = append(parts, fmt.Sprintf("%s >= %s", field, start))
+	}
+	if end != "" {
+		parts = append(parts, fmt.Sprintf("%s < %s", field, end))
+	}
+	return strings.Join(parts, " AND ")
+}
+
+func invalidDateError(field, val string) error {
+	return fmt.Errorf("invalid --%s %q: use -7d, 2w, today, yesterday, week, last-week, month, last-month, year, YYYY-MM-DD or FROM..TO", field, val)
+}
+
+// formatSprintCondition turns a --sprint value into JQL: current (the
+// active sprint), open (active or future), future, closed, none/x, a
+// sprint ID or a sprint name. A ~ prefix negates the condition; JQL's
+// negated sprint operators skip issues without a sprint, so those are
+// added back.
+func formatSprintCondition(val string) string {
+	negate := strings.HasPrefix(val, "~")
+	val = strings.TrimPrefix(val, "~")
+
+	switch strings.ToLower(val) {
+	case "none", "x":
+		if negate {
+			return "sprint IS NOT EMPTY"
+		}
+		return "sprint IS EMPTY"
+	case "current", "active":
+		if negate {
+			return "(sprint not in openSprints() OR sprint in futureSprints() OR sprint IS EMPTY)"
+		}
+		return "sprint in openSprints() AND sprint not in futureSprints()"
+	}
+
+	in, eq := "in", "="
+	if negate {
+		in, eq = "not in", "!="
+	}
+	var cond string
+	switch strings.ToLower(val) {
+	case "open":
+		cond = fmt.Sprintf("sprint %s openSprints()", in)
+	case "future":
+		cond = fmt.Sprintf("sprint %s futureSprints()", in)
+	case "closed":
+		cond = fmt.Sprintf("sprint %s closedSprints()", in)
+	default:
+		if isNumeric(val) {
+			cond = fmt.Sprintf("sprint %s %s", eq, val)
+		} else {
+			cond = fmt.Sprintf("sprint %s '%s'", eq, escapeJQL(val))
+		}
+	}
+	if negate {
+		return fmt.Sprintf("(%s OR sprint IS EMPTY)", cond)
+	}
+	return cond
+}
+
+// formatTextCondition searches summary and description; ~ negates.
+func formatTextCondition(val string) string {
+	negate := strings.HasPrefix(val, "~")
+	val = escapeJQL(strings.TrimPrefix(val, "~"))
+
+	cond := fmt.Sprintf("(summary ~ '%s' OR description ~ '%s')", val, val)
+	if negate {
+		return "NOT " + cond
+	}
+	return cond
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestFormatDateCondition(t *testing.T) {
+	tests := []struct {
+		field, val string
+		want       string
+		wantErr    bool
+	}{
+		{"created", "-7d", "created >= -7d", false},
+		{"created", "2w", "created >= -2w", false},
+		{"updated", "week", "updated >= startOfWeek()", false},
+		{"updated", "yesterday", "updated >= startOfDay(-1) AND updated < startOfDay()", false},
+		{"resolved", "2024-05-01", "resolved >= '2024-05-01' AND resolved < '2024-05-02'", false},
+		{"resolved", "2024-05-01..2024-05-31", "resolved >= '2024-05-01' AND resolved < '2024-06-01'", false},
+		{"created", "..2024-05-31", "created < '2024-06-01'", false},
+		{"created", "2024-05-01..", "created >= '2024-05-01'", false},
+		{"created", "~-30d", "(NOT (created >= -30d) OR created IS EMPTY)", false},
+		{"resolved", "~week", "(NOT (resolved >= startOfWeek()) OR resolved IS EMPTY)", false},
+		{"created", "last tuesday", "", true},
+		{"created", "..", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := formatDateCondition(tt.field, tt.val)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("formatDateCondition(%q, %q) error = %v, wantErr %v", tt.field, tt.val, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("formatDateCondition(%q, %q) = %q, want %q", tt.field, tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSprintCondition(t *testing.T) {
+	tests := []struct {
+		val, want string
+	}{
+		{"current", "sprint in openSprints() AND sprint not in futureSprints()"},
+		{"open", "sprint in openSprints()"},
+		{"~open", "(sprint not in openSprints() OR sprint IS EMPTY)"},
+		{"~current", "(sprint not in openSprints() OR sprint in futureSprints() OR sprint IS EMPTY)"},
+		{"none", "sprint IS EMPTY"},
+		{"~x", "sprint IS NOT EMPTY"},
+		{"1946", "sprint = 1946"},
+		{"Team's Sprint 4", "sprint = 'Team''s Sprint 4'"},
+		{"~Sprint 4", "(sprint != 'Sprint 4' OR sprint IS EMPTY)"},
+		{"~1946", "(sprint != 1946 OR sprint IS EMPTY)"},
+	}
+
+	for _, tt := range tests {
+		if got := formatSprintCondition(tt.val); got != tt.want {
+			t.Errorf("formatSprintCondition(%q) = %q, want %q", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTextCondition(t *testing.T) {
+	if got, want := formatTextCondition("timeout"), "(summary ~ 'timeout' OR description ~ 'timeout')"; got != want {
+		t.Errorf("formatTextCondition = %q, want %q", got, want)
+	}
+	if got, want := formatTextCondition("~flaky"), "NOT (summary ~ 'flaky' OR description ~ 'flaky')"; got != want {
+		t.Errorf("negated formatTextCondition = %q, want %q", got, want)
+	}
+}
+
+func TestAssigneeConditionServer(t *testing.T) {
+	client := api.NewJiraClient("https://jira.example.com", "tester", "token")
+	client.InstallationType = api.InstallationTypeServer
+
+	tests := map[string]string{
+		"jdoe":   "assignee = 'jdoe'",
+		"~jdoe":  "assignee != 'jdoe'",
+		"o'neil": "assignee = 'o''neil'",
+	}
+	for val, want := range tests {
+		got, err := assigneeCondition(context.Background(), client, val)
+		if err != nil || got != want {
+			t.Errorf("assigneeCondition(%q) = %q, %v; want %q", val, got, err, want)
+		}
+	}
+}
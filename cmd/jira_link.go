@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// linkGraphFields is what a graph walk needs from each issue.
+var linkGraphFields = []string{"summary", "status", "issuetype", "issuelinks"}
+
+var issueLinkCmd = &cobra.Command{
+	Use:   "link [issue-key] [relation] [issue-key]",
+	Short: "Link two JIRA issues",
+	Long: `Create a link between two issues.
+
+The relation is matched (case-insensitive) against the link type name
+and its outward and inward descriptions, so the command reads the way
+the link shows up in JIRA:
+  atl issue link MYPROJ-1 blocks MYPROJ-2          # MYPROJ-1 blocks MYPROJ-2
+  atl issue link MYPROJ-2 "is blocked by" MYPROJ-1 # same link
+
+Run without arguments to list the link types of this server.`,
+	Example: `  atl issue link MYPROJ-1 blocks MYPROJ-2
+  atl issue link MYPROJ-3 duplicates MYPROJ-1 -m "same stack trace"
+  atl issue link`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 && len(args) != 3 {
+			return fmt.Errorf("expected ISSUE RELATION ISSUE, or no arguments to list link types")
+		}
+		return nil
+	},
+	RunE: runIssueLink,
+}
+
+func runIssueLink(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	types, err := client.GetIssueLinkTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching link types: %w", err)
+	}
+
+	if len(args) == 0 {
+		for _, t := range types {
+			fmt.Printf("  %-20s %s / %s\n", t.Name, t.Outward, t.Inward)
+		}
+		return nil
+	}
+
+	linkType, reversed, err := matchLinkType(types, args[1])
+	if err != nil {
+		return err
+	}
+
+	from, to := args[0], args[2]
+	if reversed {
+		from, to = to, from
+	}
+
+	comment, _ := cmd.Flags().GetString("comment")
+	if err := client.LinkIssues(ctx, linkType.Name, from, to, comment); err != nil {
+		return err
+	}
+
+	fmt.Printf("Linked: %s %s %s\n", from, linkType.Outward, to)
+	return nil
+}
+
+// matchLinkType resolves a relation to a link type. reversed is true when the
+// relation is the inward description ("is blocked by"), i.e. the issues must
+// be swapped to express it as an outward link.
+func matchLinkType(types []api.LinkType, relation string) (api.LinkType, bool, error) {
+	for _, t := range types {
+		if strings.EqualFold(t.Name, relation) || strings.EqualFold(t.Outward, relation) {
+			return t, false, nil
+		}
+		if strings.EqualFold(t.Inward, relation) {
+			return t, true, nil
+		}
+	}
+
+	available := make([]string, len(types))
+	for i, t := range types {
+		available[i] = fmt.Sprintf("%q / %q", t.Outward, t.Inward)
+	}
+	return api.LinkType{}, false, fmt.Errorf("link type %q not found, available: %s", relation, strings.Join(available, ", "))
+}
+
+var issueUnlinkCmd = &cobra.Command{
+	Use:   "unlink [issue-key] [issue-key]",
+	Short: "Remove the link between two JIRA issues",
+	Long: `Remove a link between two issues.
+
+If the issues are linked more than once, narrow it down with --type
+(link type name or either description).`,
+	Example: `  atl issue unlink MYPROJ-1 MYPROJ-2
+  atl issue unlink MYPROJ-1 MYPROJ-2 --type blocks`,
+	Args: cobra.ExactArgs(2),
+	RunE: runIssueUnlink,
+}
+
+func runIssueUnlink(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	issue, err := client.GetIssueFields(ctx, args[0], "issuelinks")
+	if err != nil {
+		return err
+	}
+
+	typeFilter, _ := cmd.Flags().GetString("type")
+	link, err := findIssueLink(issue.Fields.IssueLinks, args[1], typeFilter)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	if err := client.DeleteIssueLink(ctx, link.ID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Unlinked %s and %s (%s)\n", args[0], args[1], link.Type.Name)
+	return nil
+}
+
+// findIssueLink picks the link to otherKey, requiring typeFilter when the
+// issues are linked in more than one way.
+func findIssueLink(links []api.IssueLink, otherKey, typeFilter string) (*api.IssueLink, error) {
+	var matches []*api.IssueLink
+	for i := range links {
+		l := &links[i]
+		if !strings.EqualFold(linkedIssueKey(l), otherKey) {
+			continue
+		}
+		if typeFilter != "" && !linkTypeMatches(l.Type, typeFilter) {
+			continue
+		}
+		matches = append(matches, l)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no link to %s found", otherKey)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, l := range matches {
+			names[i] = l.Type.Name
+		}
+		return nil, fmt.Errorf("linked to %s %d times (%s), use --type", otherKey, len(matches), strings.Join(names, ", "))
+	}
+}
+
+func linkedIssueKey(l *api.IssueLink) string {
+	if l.OutwardIssue != nil {
+		return l.OutwardIssue.Key
+	}
+	if l.InwardIssue != nil {
+		return l.InwardIssue.Key
+	}
+	return ""
+}
+
+func linkTypeMatches(t api.LinkType, filter string) bool {
+	return strings.EqualFold(t.Name, filter) || strings.EqualFold(t.Outward, filter) || strings.EqualFold(t.Inward, filter)
+}
+
+var issueLinksCmd = &cobra.Command{
+	Use:   "links [issue-key]",
+	Short: "Show the link graph around a JIRA issue",
+	Long: `Walk the links of an issue and print them as a tree or Graphviz DOT.
+
+--depth controls how many hops are followed (1 = direct links only).
+--type restricts the walk to one link type, which is how to follow a
+chain of blockers across epics:
+  atl issue links MYPROJ-100 --type blocks --depth 5
+
+Pipe --format dot into Graphviz for a picture:
+  atl issue links MYPROJ-100 --depth 3 --format dot | dot -Tsvg > deps.svg`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueLinks,
+}
+
+func runIssueLinks(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	depth, _ := cmd.Flags().GetInt("depth")
+	typeFilter, _ := cmd.Flags().GetString("type")
+	format, _ := cmd.Flags().GetString("format")
+	if format != "tree" && format != "dot" {
+		return fmt.Errorf("invalid --format %q: use tree or dot", format)
+	}
+	if depth < 1 {
+		return fmt.Errorf("--depth must be at least 1")
+	}
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	fetch := func(ctx context.Context, key string) (*api.Issue, error) {
+		return client.GetIssueFields(ctx, key, linkGraphFields...)
+	}
+
+	graph, err := walkIssueLinks(ctx, fetch, args[0], depth, typeFilter)
+	if err != nil {
+		return err
+	}
+
+	if format == "dot" {
+		graph.writeDOT(os.Stdout)
+		return nil
+	}
+	graph.writeTree(os.Stdout, depth)
+	return nil
+}
+
+// linkEdge is one link, always stored in the outward direction:
+// From <Outward> To, equivalently To <Inward> From.
+type linkEdge struct {
+	ID      string
+	From    string
+	To      string
+	Outward string
+	Inward  string
+}
+
+type linkGraph struct {
+	Root  string
+	Nodes map[string]*api.Issue
+	Edges []linkEdge
+}
+
+// walkIssueLinks follows links breadth-first from root up to depth hops.
+// Issues beyond the last hop are known only from the link payload, which
+// carries their summary and status but not their own links.
+func walkIssueLinks(ctx context.Context, fetch func(context.Context, string) (*api.Issue, error), root string, depth int, typeFilter string) (*linkGraph, error) {
+	g := &linkGraph{Root: root, Nodes: map[string]*api.Issue{}}
+	seenEdges := map[string]bool{}
+	expanded := map[string]bool{}
+
+	frontier := []string{root}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		var next []string
+		for _, key := range frontier {
+			if expanded[key] {
+				continue
+			}
+			expanded[key] = true
+
+			issue, err := fetch(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("fetching %s: %w", key, err)
+			}
+			g.Nodes[issue.Key] = issue
+			if level == 0 {
+				g.Root = issue.Key // canonical casing
+			}
+
+			for _, l := range issue.Fields.IssueLinks {
+				if typeFilter != "" && !linkTypeMatches(l.Type, typeFilter) {
+					continue
+				}
+
+				var other *api.Issue
+				edge := linkEdge{ID: l.ID, Outward: l.Type.Outward, Inward: l.Type.Inward}
+				if l.OutwardIssue != nil {
+					other = l.OutwardIssue
+					edge.From, edge.To = issue.Key, other.Key
+				} else if l.InwardIssue != nil {
+					other = l.InwardIssue
+					edge.From, edge.To = other.Key, issue.Key
+				} else {
+					continue
+				}
+
+				if _, ok := g.Nodes[other.Key]; !ok {
+					g.Nodes[other.Key] = other
+				}
+				if !seenEdges[l.ID] {
+					seenEdges[l.ID] = true
+					g.Edges = append(g.Edges, edge)
+				}
+				if !expanded[other.Key] {
+					next = append(next, other.Key)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return g, nil
+}
+
+type linkNeighbor struct {
+	LinkID   string
+	Relation string
+	Key      string
+}
+
+// neighbors lists the links of key as they read from key's side.
+func (g *linkGraph) neighbors(key string) []linkNeighbor {
+	var out []linkNeighbor
+	for _, e := range g.Edges {
+		switch key {
+		case e.From:
+			out = append(out, linkNeighbor{LinkID: e.ID, Relation: e.Outward, Key: e.To})
+		case e.To:
+			out = append(out, linkNeighbor{LinkID: e.ID, Relation: e.Inward, Key: e.From})
+		}
+	}
+	return out
+}
+
+func (g *linkGraph) describe(key string) string {
+	issue, ok := g.Nodes[key]
+	if !ok {
+		return key
+	}
+	return fmt.Sprintf("%s [%s] %s", key, issue.Fields.Status.Name, cmdutil.Truncate(issue.Fields.Summary, cmdutil.TitleTruncateNormal))
+}
+
+func (g *linkGraph) writeTree(w io.Writer, depth int) {
+	fmt.Fprintln(w, g.describe(g.Root))
+	visited := map[string]bool{g.Root: true}
+	g.writeSubtree(w, g.Root, "", "", depth, visited)
+}
+
+// writeSubtree prints the links of key, skipping the link it was reached
+// through so every edge reads once, from the parent's side.
+func (g *linkGraph) writeSubtree(w io.Writer, key, via, prefix string, depth int, visited map[string]bool) {
+	if depth == 0 {
+		return
+	}
+	var links []linkNeighbor
+	for _, n := range g.neighbors(key) {
+		if n.LinkID != via {
+			links = append(links, n)
+		}
+	}
+	for i, n := range links {
+		branch, childPrefix := "├── ", "│   "
+		if i == len(links)-1 {
+			branch, childPrefix = "└── ", "    "
+		}
+
+		if visited[n.Key] {
+			fmt.Fprintf(w, "%s%s%s %s (see above)\n", prefix, branch, n.Relation, n.Key)
+			continue
+		}
+		fmt.Fprintf(w, "%s%s%s %s\n", prefix, branch, n.Relation, g.describe(n.Key))
+
+		visited[n.Key] = true
+		g.writeSubtree(w, n.Key, n.LinkID, prefix+childPrefix, depth-1, visited)
+	}
+}
+
+func (g *linkGraph) writeDOT(w io.Writer) {
+	fmt.Fprintln(w, "digraph links {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box];")
+
+	keys := []string{g.Root}
+	for _, e := range g.Edges {
+		keys = append(keys, e.From, e.To)
+	}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		label := key
+		if issue, ok := g.Nodes[key]; ok {
+			label = fmt.Sprintf("%s\\n%s\\n[%s]", key, cmdutil.Truncate(issue.Fields.Summary, cmdutil.TitleTruncateShort), issue.Fields.Status.Name)
+		}
+		attrs := fmt.Sprintf("label=%s", dotQuote(label))
+		if key == g.Root {
+			attrs += ", style=bold"
+		}
+		fmt.Fprintf(w, "  %s [%s];\n", dotQuote(key), attrs)
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Outward))
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// dotQuote quotes a DOT identifier; label newlines are already escaped.
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func init() {
+	issueCmd.AddCommand(issueLinkCmd)
+	issueCmd.AddCommand(issueUnlinkCmd)
+	issueCmd.AddCommand(issueLinksCmd)
+
+	issueLinkCmd.Flags().StringP("comment", "m", "", "Comment to add with the link")
+
+	issueUnlinkCmd.Flags().String("type", "", "Link type to remove when the issues are linked more than once")
+
+	issueLinksCmd.Flags().Int("depth", 1, "Number of link hops to follow")
+	issueLinksCmd.Flags().String("type", "", "Only follow links of this type (name or description)")
+	issueLinksCmd.Flags().String("format", "tree", "Output format: tree or dot")
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+var sampleLinkTypes = []api.LinkType{
+	{ID: "10000", Name: "Blocks", Outward: "blocks", Inward: "is blocked by"},
+	{ID: "10001", Name: "Duplicate", Outward: "duplicates", Inward: "is duplicated by"},
+}
+
+func TestMatchLinkType(t *testing.T) {
+	cases := []struct {
+		relation     string
+		wantName     string
+		wantReversed bool
+	}{
+		{"blocks", "Blocks", false},
+		{"Blocks", "Blocks", false},
+		{"is blocked by", "Blocks", true},
+		{"IS DUPLICATED BY", "Duplicate", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.relation, func(t *testing.T) {
+			lt, reversed, err := matchLinkType(sampleLinkTypes, tc.relation)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lt.Name != tc.wantName || reversed != tc.wantReversed {
+				t.Errorf("got %s reversed=%v, want %s reversed=%v", lt.Name, reversed, tc.wantName, tc.wantReversed)
+			}
+		})
+	}
+
+	if _, _, err := matchLinkType(sampleLinkTypes, "relates"); err == nil || !strings.Contains(err.Error(), `"blocks"`) {
+		t.Errorf("error = %v, want not-found listing available types", err)
+	}
+}
+
+func issueWithLinks(key, status string, links ...api.IssueLink) *api.Issue {
+	issue := &api.Issue{Key: key}
+	issue.Fields.Summary = "summary of " + key
+	issue.Fields.Status.Name = status
+	issue.Fields.IssueLinks = links
+	return issue
+}
+
+func outward(id string, lt api.LinkType, key string) api.IssueLink {
+	return api.IssueLink{ID: id, Type: lt, OutwardIssue: issueWithLinks(key, "Open")}
+}
+
+func inward(id string, lt api.LinkType, key string) api.IssueLink {
+	return api.IssueLink{ID: id, Type: lt, InwardIssue: issueWithLinks(key, "Open")}
+}
+
+// A blocks B, B blocks C, D duplicates A.
+func sampleLinkFetcher() func(context.Context, string) (*api.Issue, error) {
+	blocks, dup := sampleLinkTypes[0], sampleLinkTypes[1]
+	issues := map[string]*api.Issue{
+		"P-1": issueWithLinks("P-1", "In Progress", outward("1", blocks, "P-2"), inward("3", dup, "P-4")),
+		"P-2": issueWithLinks("P-2", "Open", inward("1", blocks, "P-1"), outward("2", blocks, "P-3")),
+		"P-3": issueWithLinks("P-3", "Open", inward("2", blocks, "P-2")),
+		"P-4": issueWithLinks("P-4", "Closed", outward("3", dup, "P-1")),
+	}
+	return func(_ context.Context, key string) (*api.Issue, error) {
+		issue, ok := issues[key]
+		if !ok {
+			return nil, fmt.Errorf("no issue %s", key)
+		}
+		return issue, nil
+	}
+}
+
+func TestFindIssueLink(t *testing.T) {
+	blocks, dup := sampleLinkTypes[0], sampleLinkTypes[1]
+	links := []api.IssueLink{outward("1", blocks, "P-2"), inward("2", dup, "P-2"), outward("3", blocks, "P-3")}
+
+	if l, err := findIssueLink(links, "P-3", ""); err != nil || l.ID != "3" {
+		t.Errorf("P-3: got %v, %v", l, err)
+	}
+	if _, err := findIssueLink(links, "P-2", ""); err == nil || !strings.Contains(err.Error(), "use --type") {
+		t.Errorf("ambiguous: error = %v", err)
+	}
+	if l, err := findIssueLink(links, "P-2", "is duplicated by"); err != nil || l.ID != "2" {
+		t.Errorf("P-2 duplicate: got %v, %v", l, err)
+	}
+	if _, err := findIssueLink(links, "P-9", ""); err == nil {
+		t.Error("expected not-found error")
+	}
+}
+
+func TestWalkIssueLinks(t *testing.T) {
+	g, err := walkIssueLinks(context.Background(), sampleLinkFetcher(), "P-1", 3, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(g.Edges) != 3 {
+		t.Fatalf("edges = %+v, want 3 deduplicated links", g.Edges)
+	}
+	for _, e := range g.Edges {
+		if e.ID == "3" && (e.From != "P-4" || e.To != "P-1") {
+			t.Errorf("duplicate link stored as %s -> %s, want P-4 -> P-1", e.From, e.To)
+		}
+	}
+
+	var tree strings.Builder
+	g.writeTree(&tree, 3)
+	want := `P-1 [In Progress] summary of P-1
+├── blocks P-2 [Open] summary of P-2
+│   └── blocks P-3 [Open] summary of P-3
+└── is duplicated by P-4 [Closed] summary of P-4
+`
+	if tree.String() != want {
+		t.Errorf("tree =\n%s\nwant\n%s", tree.String(), want)
+	}
+}
+
+func TestWalkIssueLinksDepthAndType(t *testing.T) {
+	g, err := walkIssueLinks(context.Background(), sampleLinkFetcher(), "P-1", 1, "blocks")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(g.Edges) != 1 || g.Edges[0].To != "P-2" {
+		t.Errorf("edges = %+v, want only P-1 blocks P-2", g.Edges)
+	}
+
+	var dot strings.Builder
+	g.writeDOT(&dot)
+	for _, want := range []string{`"P-1" [label="P-1\nsummary of P-1\n[In Progress]", style=bold];`, `"P-1" -> "P-2" [label="blocks"];`} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("dot output missing %q:\n%s", want, dot.String())
+		}
+	}
+}
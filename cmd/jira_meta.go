@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+// Fields that are always part of the payload and whose allowed values are
+// objects (projects, issue types) rather than names users type.
+var structuralFields = map[string]bool{
+	"project":   true,
+	"issuetype": true,
+}
+
+// normalizeScreenFields rewrites display-name keys to field IDs and coerces
+// plain string values into the JSON shape the field schema expects, so
+// `--field "Root Cause=config"` works on the create and edit screens the
+// same way it does on transitions.
+func normalizeScreenFields(screen map[string]api.FieldMeta, screenName string, fields map[string]interface{}) error {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		id, meta, err := resolveScreenField(screen, screenName, key)
+		if err != nil {
+			return err
+		}
+		val := fields[key]
+		if id != key {
+			delete(fields, key)
+		}
+		if s, ok := val.(string); ok && meta != nil {
+			fields[id] = coerceTransitionValue(*meta, s)
+		} else {
+			fields[id] = val
+		}
+	}
+	return nil
+}
+
+// missingRequiredFields returns the IDs of required fields that have no
+// server-side default and are not in the payload, ordered by display name.
+func missingRequiredFields(screen map[string]api.FieldMeta, fields map[string]interface{}) []string {
+	var missing []string
+	for id, meta := range screen {
+		if !meta.Required || meta.HasDefaultValue || structuralFields[id] {
+			continue
+		}
+		if _, ok := fields[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return screen[missing[i]].Name < screen[missing[j]].Name
+	})
+	return missing
+}
+
+// validateAllowedValues checks every value sent for an enumerated field
+// against the screen's allowed values before the server sees it.
+func validateAllowedValues(screen map[string]api.FieldMeta, fields map[string]interface{}) error {
+	ids := make([]string, 0, len(fields))
+	for id := range fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		meta, ok := screen[id]
+		if !ok || len(meta.AllowedValues) == 0 || structuralFields[id] {
+			continue
+		}
+		for _, name := range valueNames(fields[id]) {
+			if !isAllowedValue(meta.AllowedValues, name) {
+				return fmt.Errorf("invalid value %q for %s (%s), allowed: %s",
+					name, meta.Name, id, formatAllowedValues(meta.AllowedValues))
+			}
+		}
+	}
+	return nil
+}
+
+// valueNames extracts the user-facing identifiers from a field value:
+// {"name": x}, {"value": x}, {"id": x}, plain strings, and lists of those.
+func valueNames(val interface{}) []string {
+	switch v := val.(type) {
+	case string:
+		return []string{v}
+	case map[string]string:
+		for _, k := range []string{"name", "value", "id", "key"} {
+			if s, ok := v[k]; ok {
+				return []string{s}
+			}
+		}
+	case map[string]interface{}:
+		for _, k := range []string{"name", "value", "id", "key"} {
+			if s, ok := v[k].(string); ok {
+				return []string{s}
+			}
+		}
+	case []string:
+		return v
+	case []map[string]string:
+		var names []string
+		for _, item := range v {
+			names = append(names, valueNames(item)...)
+		}
+		return names
+	case []interface{}:
+		var names []string
+		for _, item := range v {
+			names = append(names, valueNames(item)...)
+		}
+		return names
+	}
+	return nil
+}
+
+func isAllowedValue(allowed []api.AllowedValue, name string) bool {
+	for _, a := range allowed {
+		if a.ID == name || strings.EqualFold(a.Name, name) || strings.EqualFold(a.Value, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// promptForFields asks for each missing required field on the terminal.
+// Enumerated fields accept either a listed number or the value itself.
+func promptForFields(screen map[string]api.FieldMeta, missing []string, fields map[string]interface{}) error {
+	fmt.Fprintln(os.Stderr, "Missing required fields:")
+	for _, id := range missing {
+		meta := screen[id]
+
+		if len(meta.AllowedValues) > 0 {
+			fmt.Fprintf(os.Stderr, "%s (%s):\n", meta.Name, id)
+			for i, v := range meta.AllowedValues {
+				fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, v.Display())
+			}
+		}
+
+		input, err := promptLine(fmt.Sprintf("%s: ", meta.Name))
+		if err != nil {
+			return err
+		}
+		if input == "" {
+			return fmt.Errorf("%s (%s) is required", meta.Name, id)
+		}
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(meta.AllowedValues) {
+			input = meta.AllowedValues[n-1].Display()
+		}
+
+		fields[id] = coerceTransitionValue(meta, input)
+	}
+	return nil
+}
+
+// requiredFieldsError names the missing fields and their allowed values,
+// mirroring what printRequiredFieldsHint shows for transitions.
+func requiredFieldsError(screen map[string]api.FieldMeta, missing []string) error {
+	lines := make([]string, len(missing))
+	for i, id := range missing {
+		meta := screen[id]
+		lines[i] = fmt.Sprintf("  %s (%s)", meta.Name, id)
+		if vals := formatAllowedValues(meta.AllowedValues); vals != "" {
+			lines[i] += ": " + vals
+		}
+	}
+	return fmt.Errorf("missing required fields:\n%s\nset them with --field 'Name=value'", strings.Join(lines, "\n"))
+}
+
+// printScreenFields lists a screen's fields, required ones first, with
+// their schema type and allowed values.
+func printScreenFields(screen map[string]api.FieldMeta) {
+	ids := make([]string, 0, len(screen))
+	for id := range screen {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := screen[ids[i]], screen[ids[j]]
+		if a.Required != b.Required {
+			return a.Required
+		}
+		return a.Name < b.Name
+	})
+
+	for _, id := range ids {
+		meta := screen[id]
+		marker := " "
+		if meta.Required && !meta.HasDefaultValue {
+			marker = "*"
+		}
+		schema := meta.Schema.Type
+		if meta.Schema.Items != "" {
+			schema += "<" + meta.Schema.Items + ">"
+		}
+		line := fmt.Sprintf("  %s %s (%s) [%s]", marker, meta.Name, id, schema)
+		if vals := formatAllowedValues(meta.AllowedValues); vals != "" {
+			line += ": " + vals
+		}
+		fmt.Println(line)
+	}
+}
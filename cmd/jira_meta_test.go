@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func sampleCreateScreen() map[string]api.FieldMeta {
+	return map[string]api.FieldMeta{
+		"project":   {Required: true, Name: "Project", Schema: api.TransitionSchema{Type: "project"}, AllowedValues: []api.AllowedValue{{ID: "10000", Name: "My Project"}}},
+		"issuetype": {Required: true, Name: "Issue Type", Schema: api.TransitionSchema{Type: "issuetype"}},
+		"summary":   {Required: true, Name: "Summary", Schema: api.TransitionSchema{Type: "string"}},
+		"reporter":  {Required: true, Name: "Reporter", Schema: api.TransitionSchema{Type: "user"}, HasDefaultValue: true},
+		"priority": {
+			Name: "Priority", Schema: api.TransitionSchema{Type: "priority"},
+			AllowedValues: []api.AllowedValue{{ID: "1", Name: "Critical"}, {ID: "3", Name: "Major"}},
+		},
+		"customfield_12600": {
+			Required: true, Name: "Root Cause", Schema: api.TransitionSchema{Type: "option"},
+			AllowedValues: []api.AllowedValue{{ID: "201", Value: "config"}, {ID: "202", Value: "code"}},
+		},
+		"customfield_12301": {
+			Name: "Test Level", Schema: api.TransitionSchema{Type: "array", Items: "option"},
+			AllowedValues: []api.AllowedValue{{Value: "Manual Test"}, {Value: "IT Auto"}},
+		},
+		"customfield_10103": {Name: "Story Points", Schema: api.TransitionSchema{Type: "number"}},
+	}
+}
+
+func TestNormalizeScreenFields(t *testing.T) {
+	fields := map[string]interface{}{
+		"project":           map[string]string{"key": "MYPROJ"},
+		"summary":           "s",
+		"Root Cause":        "config",
+		"test level":        "Manual Test,IT Auto",
+		"customfield_10103": "3",
+		"customfield_99999": "passthrough",
+	}
+	if err := normalizeScreenFields(sampleCreateScreen(), "create", fields); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"project":           map[string]string{"key": "MYPROJ"},
+		"summary":           "s",
+		"customfield_12600": map[string]string{"value": "config"},
+		"customfield_12301": []interface{}{
+			map[string]string{"value": "Manual Test"},
+			map[string]string{"value": "IT Auto"},
+		},
+		"customfield_10103": float64(3),
+		"customfield_99999": "passthrough",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %#v, want %#v", fields, want)
+	}
+}
+
+func TestNormalizeScreenFieldsUnknownName(t *testing.T) {
+	err := normalizeScreenFields(sampleCreateScreen(), "create", map[string]interface{}{"No Such Field": "x"})
+	if err == nil || !strings.Contains(err.Error(), "not on the create screen") {
+		t.Errorf("error = %v, want not-on-screen error", err)
+	}
+}
+
+func TestMissingRequiredFields(t *testing.T) {
+	fields := map[string]interface{}{
+		"project":   map[string]string{"key": "MYPROJ"},
+		"issuetype": map[string]string{"name": "Bug"},
+	}
+	got := missingRequiredFields(sampleCreateScreen(), fields)
+	// reporter has a default; project and issuetype are structural
+	want := []string{"customfield_12600", "summary"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("missing = %v, want %v", got, want)
+	}
+}
+
+func TestValidateAllowedValues(t *testing.T) {
+	screen := sampleCreateScreen()
+
+	ok := map[string]interface{}{
+		"project":           map[string]string{"key": "MYPROJ"},
+		"priority":          map[string]string{"name": "major"},
+		"customfield_12600": map[string]interface{}{"value": "config"},
+		"customfield_12301": []interface{}{map[string]string{"value": "IT Auto"}},
+	}
+	if err := validateAllowedValues(screen, ok); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	bad := map[string]interface{}{
+		"customfield_12301": []interface{}{map[string]string{"value": "Manual Test"}, map[string]string{"value": "Chaos"}},
+	}
+	err := validateAllowedValues(screen, bad)
+	if err == nil || !strings.Contains(err.Error(), `"Chaos"`) || !strings.Contains(err.Error(), "Manual Test, IT Auto") {
+		t.Errorf("error = %v, want invalid value with allowed list", err)
+	}
+}
+
+func TestRequiredFieldsError(t *testing.T) {
+	screen := sampleCreateScreen()
+	err := requiredFieldsError(screen, []string{"customfield_12600"})
+	if !strings.Contains(err.Error(), "Root Cause (customfield_12600): config, code") {
+		t.Errorf("error = %q, want field name and allowed values", err)
+	}
+}
+
+func TestBuildEditFields(t *testing.T) {
+	screen := sampleCreateScreen()
+	fields, err := buildEditFields(issueEditOptions{
+		Summary:   "new summary",
+		Priority:  "Critical",
+		RawFields: []string{"Root Cause=code", `customfield_12301=[{"value":"IT Auto"}]`},
+	}, screen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"summary":           "new summary",
+		"priority":          map[string]string{"name": "Critical"},
+		"customfield_12600": map[string]string{"value": "code"},
+		"customfield_12301": []interface{}{map[string]interface{}{"value": "IT Auto"}},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %#v, want %#v", fields, want)
+	}
+}
+
+func TestBuildEditFieldsErrors(t *testing.T) {
+	screen := sampleCreateScreen()
+	cases := []struct {
+		name    string
+		opts    issueEditOptions
+		wantErr string
+	}{
+		{"no changes", issueEditOptions{}, "no changes"},
+		{"not editable", issueEditOptions{Components: []string{"api"}}, "not editable"},
+		{"disallowed value", issueEditOptions{Priority: "Trivial"}, "invalid value"},
+		{"bad field", issueEditOptions{RawFields: []string{"noequals"}}, "expected key=value"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := buildEditFields(tc.opts, screen)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	// Without edit metadata everything passes through unchecked.
+	if _, err := buildEditFields(issueEditOptions{Components: []string{"api"}}, nil); err != nil {
+		t.Errorf("nil screen: unexpected error %v", err)
+	}
+}
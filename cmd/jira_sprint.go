@@ -0,0 +1,457 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var sprintCmd = &cobra.Command{
+	Use:   "sprint",
+	Short: "Manage JIRA agile sprints",
+	Long: `Commands for listing, viewing and filling sprints on JIRA agile boards.
+
+--board takes a board ID or name. Without it, jira.default_board from
+the config is used, else the project's only scrum board.`,
+}
+
+var sprintListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List sprints on a board",
+	Aliases: []string{"ls"},
+	Example: `  atl sprint list
+  atl sprint list --board "Platform Scrum" --state active
+  atl sprint list --state closed --limit 5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		board, err := boardFromFlags(ctx, cmd, client)
+		if err != nil {
+			return err
+		}
+
+		state, _ := cmd.Flags().GetString("state")
+		var states []string
+		if state != "" && state != "all" {
+			states = strings.Split(state, ",")
+		}
+
+		sprints, err := client.GetBoardSprints(ctx, board.ID, states...)
+		if err != nil {
+			return err
+		}
+
+		// Closed sprints accumulate forever; the most recent are the
+		// interesting ones, so --limit keeps the tail.
+		if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 && len(sprints) > limit {
+			sprints = sprints[len(sprints)-limit:]
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(sprints)
+		}
+
+		if len(sprints) == 0 {
+			fmt.Printf("No sprints found on board %s\n", boardLabel(board))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTATE\tNAME\tSTART\tEND")
+		for _, s := range sprints {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", s.ID, s.State, s.Name, sprintDate(s.StartDate), sprintDate(s.EndDate))
+		}
+		return w.Flush()
+	},
+}
+
+var sprintViewCmd = &cobra.Command{
+	Use:   "view [sprint]",
+	Short: "Show a sprint's issues grouped by status",
+	Long: `Show a sprint's issues grouped by status.
+
+The sprint is an ID or name; without one, the board's active sprint is
+shown.`,
+	Example: `  atl sprint view
+  atl sprint view 1946
+  atl sprint view "Sprint 42" --board "Platform Scrum"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		var sprint *api.Sprint
+		if len(args) == 1 {
+			sprint, err = sprintFromArg(ctx, cmd, client, args[0])
+		} else {
+			sprint, err = activeSprint(ctx, cmd, client)
+		}
+		if err != nil {
+			return err
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		issues, err := client.GetSprintIssues(ctx, sprint.ID, limit, "summary", "status", "issuetype", "assignee", "priority")
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"sprint": sprint, "issues": issues})
+		}
+
+		fmt.Printf("Sprint %d: %s [%s]\n", sprint.ID, sprint.Name, sprint.State)
+		if sprint.StartDate != "" || sprint.EndDate != "" {
+			fmt.Printf("Dates: %s to %s\n", sprintDate(sprint.StartDate), sprintDate(sprint.EndDate))
+		}
+		if sprint.Goal != "" {
+			fmt.Printf("Goal: %s\n", sprint.Goal)
+		}
+
+		if len(issues) == 0 {
+			fmt.Println("\nNo issues in this sprint")
+			return nil
+		}
+
+		done := 0
+		for _, group := range groupIssuesByStatus(issues) {
+			fmt.Printf("\n%s (%d)\n", group.Status, len(group.Issues))
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, issue := range group.Issues {
+				assignee := "Unassigned"
+				if issue.Fields.Assignee != nil {
+					assignee = issue.Fields.Assignee.DisplayName
+				}
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n",
+					issue.Key,
+					issue.Fields.IssueType.Name,
+					assignee,
+					cmdutil.Truncate(issue.Fields.Summary, cmdutil.TitleTruncateLong),
+				)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if group.Category == "done" {
+				done += len(group.Issues)
+			}
+		}
+
+		fmt.Printf("\n%d issues, %d done\n", len(issues), done)
+		if len(issues) == limit {
+			fmt.Fprintf(os.Stderr, "Warning: stopped at --limit %d issues; the sprint may have more\n", limit)
+		}
+		return nil
+	},
+}
+
+var sprintAddCmd = &cobra.Command{
+	Use:   "add [sprint] [issue-key...]",
+	Short: "Move issues into a sprint",
+	Long: `Move issues into a sprint, taking them out of any other open sprint.
+
+The sprint is an ID or name. Bare issue numbers are prefixed with the
+default project.`,
+	Example: `  atl sprint add 1946 MYPROJ-123 MYPROJ-124
+  atl sprint add "Sprint 42" 125 126`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		sprint, err := sprintFromArg(ctx, cmd, client, args[0])
+		if err != nil {
+			return err
+		}
+
+		keys := prefixIssueKeys(args[1:], projectFromFlags(cmd))
+
+		if err := client.MoveIssuesToSprint(ctx, sprint.ID, keys); err != nil {
+			return err
+		}
+
+		fmt.Printf("Moved %s to sprint %d (%s)\n", strings.Join(keys, ", "), sprint.ID, sprint.Name)
+		return nil
+	},
+}
+
+func projectFromFlags(cmd *cobra.Command) string {
+	project, _ := cmd.Flags().GetString("project")
+	if project == "" {
+		project = viper.GetString("jira.default_project")
+	}
+	return project
+}
+
+func boardFromFlags(ctx context.Context, cmd *cobra.Command, client *api.JiraClient) (api.Board, error) {
+	board, _ := cmd.Flags().GetString("board")
+	return resolveBoard(ctx, client, board, projectFromFlags(cmd))
+}
+
+// resolveBoard turns a board ID or name into a board. An empty value falls
+// back to jira.default_board, then to the project's only scrum board.
+func resolveBoard(ctx context.Context, client *api.JiraClient, value, project string) (api.Board, error) {
+	if value == "" {
+		value = viper.GetString("jira.default_board")
+	}
+	if id, err := strconv.Atoi(value); err == nil {
+		return api.Board{ID: id}, nil
+	}
+
+	boards, err := client.GetBoards(ctx, project, value)
+	if err != nil {
+		return api.Board{}, fmt.Errorf("listing boards: %w", err)
+	}
+	return chooseBoard(boards, value, project)
+}
+
+// chooseBoard picks the board named name, or with no name the only scrum
+// board among boards.
+func chooseBoard(boards []api.Board, name, project string) (api.Board, error) {
+	var candidates []api.Board
+	for _, b := range boards {
+		if name != "" && strings.EqualFold(b.Name, name) {
+			return b, nil
+		}
+		if name != "" || b.Type == "scrum" {
+			candidates = append(candidates, b)
+		}
+	}
+
+	switch {
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case len(candidates) == 0 && name != "":
+		return api.Board{}, fmt.Errorf("no board matching %q", name)
+	case len(candidates) == 0:
+		if project == "" {
+			return api.Board{}, fmt.Errorf("board required: use --board, --project or set jira.default_board in config")
+		}
+		return api.Board{}, fmt.Errorf("no scrum board found for project %s: use --board", project)
+	}
+
+	names := make([]string, len(candidates))
+	for i, b := range candidates {
+		names[i] = boardLabel(b)
+	}
+	return api.Board{}, fmt.Errorf("several boards match, use --board with one of: %s", strings.Join(names, ", "))
+}
+
+func boardLabel(b api.Board) string {
+	if b.Name == "" {
+		return strconv.Itoa(b.ID)
+	}
+	return fmt.Sprintf("%q (%d)", b.Name, b.ID)
+}
+
+// sprintFromArg resolves a sprint ID or name. Names are looked up among
+// the open (active and future) sprints of the selected board.
+func sprintFromArg(ctx context.Context, cmd *cobra.Command, client *api.JiraClient, value string) (*api.Sprint, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return client.GetSprint(ctx, id)
+	}
+
+	board, err := boardFromFlags(ctx, cmd, client)
+	if err != nil {
+		return nil, err
+	}
+	sprints, err := client.GetBoardSprints(ctx, board.ID, "active", "future")
+	if err != nil {
+		return nil, err
+	}
+	sprint, err := matchSprint(sprints, value)
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func activeSprint(ctx context.Context, cmd *cobra.Command, client *api.JiraClient) (*api.Sprint, error) {
+	board, err := boardFromFlags(ctx, cmd, client)
+	if err != nil {
+		return nil, err
+	}
+	sprints, err := client.GetBoardSprints(ctx, board.ID, "active")
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(sprints) {
+	case 0:
+		return nil, fmt.Errorf("no active sprint on board %s", boardLabel(board))
+	case 1:
+		return &sprints[0], nil
+	}
+	names := make([]string, len(sprints))
+	for i, s := range sprints {
+		names[i] = fmt.Sprintf("%d (%s)", s.ID, s.Name)
+	}
+	return nil, fmt.Errorf("board %s has several active sprints, pick one: %s", boardLabel(board), strings.Join(names, ", "))
+}
+
+// resolveSprintID accepts a numeric sprint ID as-is and otherwise looks the
+// name up among the open sprints of the given board, or of every scrum
+// board in the project.
+func resolveSprintID(ctx context.Context, client *api.JiraClient, value, board, project string) (int, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return id, nil
+	}
+
+	var boards []api.Board
+	if board != "" || viper.GetString("jira.default_board") != "" {
+		b, err := resolveBoard(ctx, client, board, project)
+		if err != nil {
+			return 0, err
+		}
+		boards = []api.Board{b}
+	} else {
+		all, err := client.GetBoards(ctx, project, "")
+		if err != nil {
+			return 0, fmt.Errorf("listing boards: %w", err)
+		}
+		for _, b := range all {
+			if b.Type == "scrum" {
+				boards = append(boards, b)
+			}
+		}
+	}
+
+	var sprints []api.Sprint
+	for _, b := range boards {
+		s, err := client.GetBoardSprints(ctx, b.ID, "active", "future")
+		if err != nil {
+			return 0, fmt.Errorf("listing sprints of board %s: %w", boardLabel(b), err)
+		}
+		sprints = append(sprints, s...)
+	}
+
+	sprint, err := matchSprint(sprints, value)
+	if err != nil {
+		return 0, err
+	}
+	return sprint.ID, nil
+}
+
+// matchSprint finds a sprint by name: an exact (case-insensitive) match
+// wins, otherwise the name must be a substring of exactly one sprint.
+// Sprints shared by several boards appear once per board and are deduped.
+func matchSprint(sprints []api.Sprint, name string) (api.Sprint, error) {
+	seen := map[int]bool{}
+	var partial []api.Sprint
+	for _, s := range sprints {
+		if seen[s.ID] {
+			continue
+		}
+		seen[s.ID] = true
+
+		if strings.EqualFold(s.Name, name) {
+			return s, nil
+		}
+		if strings.Contains(strings.ToLower(s.Name), strings.ToLower(name)) {
+			partial = append(partial, s)
+		}
+	}
+
+	switch len(partial) {
+	case 1:
+		return partial[0], nil
+	case 0:
+		return api.Sprint{}, fmt.Errorf("no open sprint named %q", name)
+	}
+	names := make([]string, len(partial))
+	for i, s := range partial {
+		names[i] = fmt.Sprintf("%q (%d)", s.Name, s.ID)
+	}
+	return api.Sprint{}, fmt.Errorf("sprint %q is ambiguous: %s", name, strings.Join(names, ", "))
+}
+
+type statusGroup struct {
+	Status   string
+	Category string
+	Issues   []api.Issue
+}
+
+// statusCategoryOrder puts board columns in workflow order.
+var statusCategoryOrder = map[string]int{"new": 0, "indeterminate": 1, "done": 2}
+
+// groupIssuesByStatus buckets issues by status, ordering the buckets To Do,
+// In Progress, Done (by status category) and then by status name.
+func groupIssuesByStatus(issues []api.Issue) []statusGroup {
+	index := map[string]int{}
+	var groups []statusGroup
+	for _, issue := range issues {
+		status := issue.Fields.Status
+		i, ok := index[status.Name]
+		if !ok {
+			category := ""
+			if status.StatusCategory != nil {
+				category = status.StatusCategory.Key
+			}
+			i = len(groups)
+			index[status.Name] = i
+			groups = append(groups, statusGroup{Status: status.Name, Category: category})
+		}
+		groups[i].Issues = append(groups[i].Issues, issue)
+	}
+
+	rank := func(category string) int {
+		if r, ok := statusCategoryOrder[category]; ok {
+			return r
+		}
+		return len(statusCategoryOrder)
+	}
+	sort.SliceStable(groups, func(a, b int) bool {
+		ra, rb := rank(groups[a].Category), rank(groups[b].Category)
+		if ra != rb {
+			return ra < rb
+		}
+		return groups[a].Status < groups[b].Status
+	})
+	return groups
+}
+
+// sprintDate trims an agile API timestamp to its date.
+func sprintDate(s string) string {
+	if len(s) >= len(dateLayout) {
+		return s[:len(dateLayout)]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(sprintCmd)
+	sprintCmd.AddCommand(sprintListCmd)
+	sprintCmd.AddCommand(sprintViewCmd)
+	sprintCmd.AddCommand(sprintAddCmd)
+
+	sprintCmd.PersistentFlags().StringP("board", "b", "", "Board ID or name (default: jira.default_board)")
+	sprintCmd.PersistentFlags().StringP("project", "p", "", "Project key used to find boards (default from config)")
+
+	sprintListCmd.Flags().String("state", "active,future", "Sprint states: active, future, closed, comma-separated, or all")
+	sprintListCmd.Flags().Int("limit", 0, "Show only the last N sprints")
+	sprintListCmd.Flags().Bool("json", false, "Output as JSON")
+
+	sprintViewCmd.Flags().Int("limit", 200, "Maximum number of issues")
+	sprintViewCmd.Flags().Bool("json", false, "Output as JSON")
+}
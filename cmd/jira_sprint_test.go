@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestMatchSprint(t *testing.T) {
+	sprints := []api.Sprint{
+		{ID: 1, Name: "Platform Sprint 41"},
+		{ID: 2, Name: "Platform Sprint 42"},
+		{ID: 3, Name: "Mobile Sprint 42"},
+		{ID: 2, Name: "Platform Sprint 42"}, // same sprint on a second board
+	}
+
+	tests := []struct {
+		name    string
+		want    int
+		wantErr bool
+	}{
+		{"platform sprint 42", 2, false},
+		{"Sprint 41", 1, false},
+		{"Mobile", 3, false},
+		{"Sprint 42", 0, true},
+		{"Sprint 43", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := matchSprint(sprints, tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("matchSprint(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got.ID != tt.want {
+			t.Errorf("matchSprint(%q) = %d, want %d", tt.name, got.ID, tt.want)
+		}
+	}
+}
+
+func TestChooseBoard(t *testing.T) {
+	boards := []api.Board{
+		{ID: 10, Name: "Platform Scrum", Type: "scrum"},
+		{ID: 11, Name: "Platform Kanban", Type: "kanban"},
+	}
+
+	tests := []struct {
+		desc    string
+		boards  []api.Board
+		name    string
+		want    int
+		wantErr bool
+	}{
+		{"only scrum board", boards, "", 10, false},
+		{"exact name", boards, "platform kanban", 11, false},
+		{"ambiguous name", boards, "Platform", 0, true},
+		{"no boards", nil, "", 0, true},
+		{"several scrum boards", append(boards, api.Board{ID: 12, Name: "Other", Type: "scrum"}), "", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := chooseBoard(tt.boards, tt.name, "MYPROJ")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.desc, err, tt.wantErr)
+			continue
+		}
+		if got.ID != tt.want {
+			t.Errorf("%s: board = %d, want %d", tt.desc, got.ID, tt.want)
+		}
+	}
+}
+
+func TestGroupIssuesByStatus(t *testing.T) {
+	issue := func(key, status, category string) api.Issue {
+		return api.Issue{Key: key, Fields: api.IssueFields{Status: api.Status{
+			Name:           status,
+			StatusCategory: &api.StatusCategory{Key: category},
+		}}}
+	}
+	issues := []api.Issue{
+		issue("P-1", "Done", "done"),
+		issue("P-2", "In Review", "indeterminate"),
+		issue("P-3", "To Do", "new"),
+		issue("P-4", "In Progress", "indeterminate"),
+		issue("P-5", "To Do", "new"),
+	}
+
+	var got []string
+	for _, g := range groupIssuesByStatus(issues) {
+		keys := ""
+		for _, i := range g.Issues {
+			keys += " " + i.Key
+		}
+		got = append(got, g.Status+":"+keys)
+	}
+
+	want := []string{"To Do: P-3 P-5", "In Progress: P-4", "In Review: P-2", "Done: P-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groups = %q, want %q", got, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -108,14 +109,8 @@ func runIssueTransition(cmd *cobra.Command, args []string) error {
 			opts.Defaults = transitionDefaults(issue.Fields.IssueType.Name, target.Name)
 		}
 	}
-	if ts, ok := popDefault(opts.Defaults, "Time Spent"); ok && update.TimeSpent == "" {
-		update.TimeSpent = ts
-	}
-	if est, ok := popDefault(opts.Defaults, "Original Estimate"); ok {
-		opts.Defaults["timetracking"] = est
-	}
 
-	fields, err := buildTransitionFields(opts, target)
+	fields, update, err := applyTransitionDefaults(opts, update, target)
 	if err != nil {
 		return err
 	}
@@ -163,6 +158,21 @@ func transitionDefaults(issueType, transitionName string) map[string]string {
 	return defaults
 }
 
+// applyTransitionDefaults lifts the defaults that are not screen fields
+// (Time Spent, Original Estimate) into their proper place and builds the
+// transition fields payload.
+func applyTransitionDefaults(opts transitionOptions, update api.TransitionUpdate, target *api.Transition) (map[string]interface{}, api.TransitionUpdate, error) {
+	if ts, ok := popDefault(opts.Defaults, "Time Spent"); ok && update.TimeSpent == "" {
+		update.TimeSpent = ts
+	}
+	if est, ok := popDefault(opts.Defaults, "Original Estimate"); ok {
+		opts.Defaults["timetracking"] = est
+	}
+
+	fields, err := buildTransitionFields(opts, target)
+	return fields, update, err
+}
+
 func popDefault(defaults map[string]string, name string) (string, bool) {
 	for k, v := range defaults {
 		if strings.EqualFold(k, name) {
@@ -313,15 +323,22 @@ func buildTransitionFields(opts transitionOptions, t *api.Transition) (map[strin
 // resolveTransitionField maps a --field key to a field ID using the
 // transition screen metadata. Display names match case-insensitively.
 func resolveTransitionField(t *api.Transition, key string) (string, *api.TransitionField, error) {
-	if t != nil {
-		if meta, ok := t.Fields[key]; ok {
-			return key, &meta, nil
-		}
-		for id, meta := range t.Fields {
-			if strings.EqualFold(meta.Name, key) {
-				m := meta
-				return id, &m, nil
-			}
+	if t == nil {
+		return resolveScreenField(nil, "transition", key)
+	}
+	return resolveScreenField(t.Fields, fmt.Sprintf("%q transition", t.Name), key)
+}
+
+// resolveScreenField maps a field key to a field ID using screen metadata
+// (transition, create or edit). Display names match case-insensitively.
+func resolveScreenField(screen map[string]api.FieldMeta, screenName, key string) (string, *api.FieldMeta, error) {
+	if meta, ok := screen[key]; ok {
+		return key, &meta, nil
+	}
+	for id, meta := range screen {
+		if strings.EqualFold(meta.Name, key) {
+			m := meta
+			return id, &m, nil
 		}
 	}
 
@@ -329,13 +346,12 @@ func resolveTransitionField(t *api.Transition, key string) (string, *api.Transit
 	// error because Jira would reject it with an opaque message anyway.
 	if strings.Contains(key, " ") {
 		var names []string
-		if t != nil {
-			for _, meta := range t.Fields {
-				names = append(names, meta.Name)
-			}
+		for _, meta := range screen {
+			names = append(names, meta.Name)
 		}
-		return "", nil, fmt.Errorf("field %q is not on the %q transition screen (available: %s)",
-			key, t.Name, strings.Join(names, ", "))
+		sort.Strings(names)
+		return "", nil, fmt.Errorf("field %q is not on the %s screen (available: %s)",
+			key, screenName, strings.Join(names, ", "))
 	}
 	return key, nil, nil
 }
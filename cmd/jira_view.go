@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/lroolle/atlas-cli/pkg/converter"
+	"github.com/spf13/cobra"
+)
+
+var issueViewCmd = &cobra.Command{
+	Use:   "view [issue-key]",
+	Short: "View a JIRA issue",
+	Long: `View a JIRA issue: its details, description, subtasks, links and the
+most recent comments.
+
+Descriptions and comments are converted from JIRA wiki markup to
+Markdown and styled on a terminal (set NO_COLOR to disable); --raw
+prints them untouched. --fields adds custom fields by name or ID.`,
+	Example: `  atl issue view MYPROJ-123
+  atl issue view MYPROJ-123 --comments 10
+  atl issue view MYPROJ-123 --fields "Story Points,Team Zone,customfield_10401"
+  atl issue view MYPROJ-123 --raw`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueView,
+}
+
+func runIssueView(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	issue, err := client.GetIssue(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(issue)
+	}
+
+	raw, _ := cmd.Flags().GetBool("raw")
+	render := func(wiki string) string {
+		if raw {
+			return wiki
+		}
+		return renderMarkdown(converter.WikiToMarkdown(wiki))
+	}
+
+	f := issue.Fields
+	fmt.Printf("%s %s\n", bold(issue.Key), bold(f.Summary))
+	fmt.Printf("%s · %s · %s\n", f.IssueType.Name, issueStatus(f.Status), valueOr(f.Priority.Name, "No priority"))
+	fmt.Println()
+
+	assignee := "Unassigned"
+	if f.Assignee != nil {
+		assignee = f.Assignee.DisplayName
+	}
+	printDetail("Assignee", assignee)
+	printDetail("Reporter", f.Reporter.DisplayName)
+	if f.Resolution != nil {
+		printDetail("Resolution", f.Resolution.Name)
+	}
+	printDetail("Created", formatJiraTime(f.Created))
+	printDetail("Updated", formatJiraTime(f.Updated))
+	if len(f.Labels) > 0 {
+		printDetail("Labels", strings.Join(f.Labels, ", "))
+	}
+	if len(f.Components) > 0 {
+		names := make([]string, len(f.Components))
+		for i, c := range f.Components {
+			names[i] = c.Name
+		}
+		printDetail("Components", strings.Join(names, ", "))
+	}
+	if len(f.FixVersions) > 0 {
+		names := make([]string, len(f.FixVersions))
+		for i, v := range f.FixVersions {
+			names[i] = v.Name
+		}
+		printDetail("Fix versions", strings.Join(names, ", "))
+	}
+	if f.Parent != nil {
+		printDetail("Parent", fmt.Sprintf("%s %s", f.Parent.Key, f.Parent.Fields.Summary))
+	}
+
+	if names, _ := cmd.Flags().GetStringSlice("fields"); len(names) > 0 {
+		if err := printCustomFields(ctx, client, issue, names); err != nil {
+			return err
+		}
+	}
+
+	if strings.TrimSpace(f.Description) != "" {
+		printSection("Description")
+		fmt.Println(render(f.Description))
+	}
+
+	if len(f.Subtasks) > 0 {
+		printSection(fmt.Sprintf("Subtasks (%d)", len(f.Subtasks)))
+		for _, st := range f.Subtasks {
+			fmt.Printf("  %s [%s] %s\n", st.Key, issueStatus(st.Fields.Status), st.Fields.Summary)
+		}
+	}
+
+	if len(f.IssueLinks) > 0 {
+		printSection("Links")
+		for _, link := range f.IssueLinks {
+			if link.OutwardIssue != nil {
+				fmt.Printf("  %s %s [%s] %s\n", dim(link.Type.Outward), link.OutwardIssue.Key, issueStatus(link.OutwardIssue.Fields.Status), link.OutwardIssue.Fields.Summary)
+			}
+			if link.InwardIssue != nil {
+				fmt.Printf("  %s %s [%s] %s\n", dim(link.Type.Inward), link.InwardIssue.Key, issueStatus(link.InwardIssue.Fields.Status), link.InwardIssue.Fields.Summary)
+			}
+		}
+	}
+
+	if n, _ := cmd.Flags().GetInt("comments"); n > 0 {
+		comments, err := client.GetComments(ctx, issue.Key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cannot read comments: %v\n", err)
+		} else if len(comments) > 0 {
+			shown := comments
+			if len(shown) > n {
+				shown = shown[len(shown)-n:]
+			}
+			printSection(fmt.Sprintf("Comments (%d of %d)", len(shown), len(comments)))
+			for _, c := range shown {
+				fmt.Printf("%s %s\n", bold(c.Author.DisplayName), dim(formatJiraTime(c.Created)))
+				if client.IsCloud() && !raw {
+					// Cloud comments arrive as ADF, already converted to Markdown.
+					fmt.Println(renderMarkdown(c.Body))
+				} else {
+					fmt.Println(render(c.Body))
+				}
+				fmt.Println()
+			}
+		}
+	}
+
+	fmt.Printf("\n%s\n", dim(client.BaseURL+"/browse/"+issue.Key))
+	return nil
+}
+
+func printDetail(label, value string) {
+	fmt.Printf("%s %s\n", dim(fmt.Sprintf("%-13s", label+":")), value)
+}
+
+func printSection(title string) {
+	fmt.Printf("\n%s\n", bold(title))
+}
+
+func issueStatus(s api.Status) string {
+	category := ""
+	if s.StatusCategory != nil {
+		category = s.StatusCategory.Key
+	}
+	return statusColor(s.Name, category)
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// formatJiraTime shows a JIRA timestamp in local time, or as-is if it
+// does not parse.
+func formatJiraTime(s string) string {
+	t, err := time.Parse(api.JiraTimeLayout, s)
+	if err != nil {
+		return s
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// printCustomFields prints the requested fields, given by display name or
+// ID, in the order asked.
+func printCustomFields(ctx context.Context, client *api.JiraClient, issue *api.Issue, names []string) error {
+	jiraFields, err := client.GetFields(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving --fields: %w", err)
+	}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		id, label := name, name
+		for _, jf := range jiraFields {
+			if jf.ID == name || strings.EqualFold(jf.Name, name) {
+				id, label = jf.ID, jf.Name
+				break
+			}
+		}
+
+		value := "-"
+		if raw, ok := issue.Fields.Custom[id]; ok {
+			value = formatCustomValue(raw)
+		}
+		printDetail(label, value)
+	}
+	return nil
+}
+
+// Server returns sprints as serialized Java objects:
+// com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=1,name=Sprint 4,...]
+var legacySprintName = regexp.MustCompile(`\bname=([^,\]]*)`)
+
+// formatCustomValue renders a custom field's JSON value for humans: option
+// and user objects by their label, cascading selects as "parent / child",
+// arrays comma-separated.
+func formatCustomValue(raw json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+	return formatCustomAny(v)
+}
+
+func formatCustomAny(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if m := legacySprintName.FindStringSubmatch(val); m != nil && strings.Contains(val, "Sprint@") {
+			return m[1]
+		}
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case []interface{}:
+		parts := make([]string, len(val))
+		for i, item := range val {
+			parts[i] = formatCustomAny(item)
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		label := ""
+		for _, key := range []string{"value", "displayName", "name", "key"} {
+			if s, ok := val[key].(string); ok && s != "" {
+				label = s
+				break
+			}
+		}
+		if child, ok := val["child"]; ok {
+			return label + " / " + formatCustomAny(child)
+		}
+		if label != "" {
+			return label
+		}
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func init() {
+	f := issueViewCmd.Flags()
+	f.Int("comments", 3, "Number of most recent comments to show (0 to hide)")
+	f.StringSlice("fields", nil, "Extra fields to show, by name or ID (comma-separated)")
+	f.Bool("raw", false, "Print description and comments without converting wiki markup")
+	f.Bool("json", false, "Output the issue as JSON")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFormatCustomValue(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"string", `"Platform"`, "Platform"},
+		{"number", `5`, "5"},
+		{"fraction", `0.5`, "0.5"},
+		{"bool", `true`, "true"},
+		{"option", `{"self": "x", "value": "High", "id": "10001"}`, "High"},
+		{"user", `{"name": "jdoe", "displayName": "Jane Doe"}`, "Jane Doe"},
+		{"cascading", `{"value": "EMEA", "child": {"value": "Berlin"}}`, "EMEA / Berlin"},
+		{"multi select", `[{"value": "A"}, {"value": "B"}]`, "A, B"},
+		{"labels", `["x", "y"]`, "x, y"},
+		{
+			"server sprint",
+			`["com.atlassian.greenhopper.service.sprint.Sprint@1f2e[id=12,rapidViewId=3,state=ACTIVE,name=Sprint 42,startDate=2024-01-01]"]`,
+			"Sprint 42",
+		},
+		{"unknown object", `{"id": 3}`, `{"id":3}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatCustomValue(json.RawMessage(tt.raw)); got != tt.want {
+				t.Errorf("formatCustomValue(%s) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatJiraTime(t *testing.T) {
+	if got := formatJiraTime("not a time"); got != "not a time" {
+		t.Errorf("unparseable time = %q, want it unchanged", got)
+	}
+	if got := formatJiraTime("2024-03-05T10:20:30.000+0000"); len(got) != len("2006-01-02 15:04") {
+		t.Errorf("formatJiraTime = %q, want a local date and time", got)
+	}
+}
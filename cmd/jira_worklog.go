@@ -0,0 +1,455 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+const dateLayout = "2006-01-02"
+
+var issueWorklogCmd = &cobra.Command{
+	Use:     "worklog",
+	Short:   "Log and manage work on JIRA issues",
+	Aliases: []string{"wl"},
+}
+
+var worklogAddCmd = &cobra.Command{
+	Use:   "add [issue-key] [time-spent]",
+	Short: "Log work on an issue",
+	Long: `Log work on an issue.
+
+Time spent uses JIRA duration syntax (30m, 2h, 1d 4h). --started takes
+a date (2024-05-06), a date and time (2024-05-06 09:30) or RFC 3339;
+it defaults to now.`,
+	Example: `  atl issue worklog add MYPROJ-123 2h
+  atl issue worklog add MYPROJ-123 "1h 30m" --started "2024-05-06 09:30" -m "pairing on the parser"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		req, err := worklogRequestFromFlags(cmd, args[1])
+		if err != nil {
+			return err
+		}
+		if req.Started == "" {
+			req.Started = time.Now().Format(api.JiraTimeLayout)
+		}
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		worklog, err := client.AddWorklog(ctx, args[0], req)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Logged %s on %s (worklog %s)\n", worklog.TimeSpent, args[0], worklog.ID)
+		return nil
+	},
+}
+
+var worklogListCmd = &cobra.Command{
+	Use:     "list [issue-key]",
+	Short:   "List work logged on an issue",
+	Aliases: []string{"ls"},
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		worklogs, err := client.GetWorklogs(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(worklogs)
+		}
+
+		if len(worklogs) == 0 {
+			fmt.Printf("No work logged on %s\n", args[0])
+			return nil
+		}
+
+		total := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTARTED\tSPENT\tAUTHOR\tCOMMENT")
+		for _, wl := range worklogs {
+			total += wl.TimeSpentSeconds
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				wl.ID,
+				wl.StartedTime().Local().Format("2006-01-02 15:04"),
+				wl.TimeSpent,
+				wl.Author.DisplayName,
+				cmdutil.Truncate(firstLine(wl.Comment), cmdutil.TitleTruncateShort),
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		fmt.Printf("\nTotal: %s\n", formatWorkSeconds(total))
+		return nil
+	},
+}
+
+var worklogEditCmd = &cobra.Command{
+	Use:   "edit [issue-key] [worklog-id] [time-spent]",
+	Short: "Change a worklog's time, start or comment",
+	Example: `  atl issue worklog edit MYPROJ-123 10452 3h
+  atl issue worklog edit MYPROJ-123 10452 -m "corrected description"`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		var timeSpent string
+		if len(args) > 2 {
+			timeSpent = args[2]
+		}
+		req, err := worklogRequestFromFlags(cmd, timeSpent)
+		if err != nil {
+			return err
+		}
+		if req == (api.WorklogRequest{}) {
+			return fmt.Errorf("no changes specified")
+		}
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		// JIRA replaces timeSpent on every update, so keep the current
+		// value when only the comment or start changes.
+		if req.TimeSpent == "" {
+			worklogs, err := client.GetWorklogs(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			for _, wl := range worklogs {
+				if wl.ID == args[1] {
+					req.TimeSpent = wl.TimeSpent
+				}
+			}
+			if req.TimeSpent == "" {
+				return fmt.Errorf("worklog %s not found on %s", args[1], args[0])
+			}
+		}
+
+		worklog, err := client.UpdateWorklog(ctx, args[0], args[1], req)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Updated worklog %s on %s (%s)\n", worklog.ID, args[0], worklog.TimeSpent)
+		return nil
+	},
+}
+
+var worklogDeleteCmd = &cobra.Command{
+	Use:     "delete [issue-key] [worklog-id]",
+	Short:   "Delete a worklog",
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		if err := client.DeleteWorklog(ctx, args[0], args[1]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted worklog %s from %s\n", args[1], args[0])
+		return nil
+	},
+}
+
+func worklogRequestFromFlags(cmd *cobra.Command, timeSpent string) (api.WorklogRequest, error) {
+	req := api.WorklogRequest{TimeSpent: strings.TrimSpace(timeSpent)}
+	req.Comment, _ = cmd.Flags().GetString("comment")
+
+	if started, _ := cmd.Flags().GetString("started"); started != "" {
+		t, err := parseStarted(started, time.Local)
+		if err != nil {
+			return req, err
+		}
+		req.Started = t.Format(api.JiraTimeLayout)
+	}
+	return req, nil
+}
+
+// parseStarted accepts a date, a date with a time, or RFC 3339. Dates
+// without a time start at 09:00 so the worklog lands on that day in any
+// reasonable timezone.
+func parseStarted(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation(dateLayout, s, loc); err == nil {
+		return t.Add(9 * time.Hour), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --started %q: use YYYY-MM-DD, 'YYYY-MM-DD HH:MM' or RFC 3339", s)
+}
+
+// formatWorkSeconds renders a duration in hours and minutes; days are
+// avoided because the length of a working day is a server setting.
+func formatWorkSeconds(seconds int) string {
+	h, m := seconds/3600, (seconds%3600)/60
+	switch {
+	case h > 0 && m > 0:
+		return fmt.Sprintf("%dh %dm", h, m)
+	case h > 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dm", m)
+	}
+}
+
+var issueTimesheetCmd = &cobra.Command{
+	Use:   "timesheet",
+	Short: "Summarize logged work per day and per issue",
+	Long: `Summarize the work a user logged in a date range.
+
+Issues are found with JQL (worklogDate within the range, worklogAuthor =
+the user, plus --jql), then every worklog of theirs in the range is
+totalled per day and per issue. The range defaults to the current week
+(Monday to today); --user defaults to you.`,
+	Example: `  atl issue timesheet
+  atl issue timesheet --from 2024-05-06 --to 2024-05-10
+  atl issue timesheet --user alice --from 2024-05-01 --to 2024-05-31 --json
+  atl issue timesheet -p MYPROJ --jql "labels = contractor"`,
+	Args: cobra.NoArgs,
+	RunE: runIssueTimesheet,
+}
+
+type timesheetEntry struct {
+	Key     string      `json:"key"`
+	Summary string      `json:"summary"`
+	Worklog api.Worklog `json:"worklog"`
+}
+
+type timesheetRow struct {
+	Key     string `json:"key,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Seconds int    `json:"seconds"`
+}
+
+type timesheet struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	User    string         `json:"user"`
+	ByDay   []timesheetRow `json:"by_day"`
+	ByIssue []timesheetRow `json:"by_issue"`
+	Total   int            `json:"total_seconds"`
+}
+
+func runIssueTimesheet(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	from, to, err := timesheetRange(cmd, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	userArg, _ := cmd.Flags().GetString("user")
+	if userArg == "" {
+		userArg = "me"
+	}
+	author, err := resolveJiraUser(ctx, client, userArg)
+	if err != nil {
+		return fmt.Errorf("resolving --user: %w", err)
+	}
+	authorID := jiraUserID(client, *author)
+	if authorID == "" {
+		return fmt.Errorf("could not identify JIRA user %q", userArg)
+	}
+	user := valueOr(author.DisplayName, authorID)
+
+	authorJQL := "worklogAuthor = currentUser()"
+	if userArg != "me" {
+		authorJQL = fmt.Sprintf("worklogAuthor = '%s'", escapeJQL(authorID))
+	}
+
+	conditions := []string{
+		fmt.Sprintf("worklogDate >= '%s'", from.Format(dateLayout)),
+		fmt.Sprintf("worklogDate <= '%s'", to.Format(dateLayout)),
+		authorJQL,
+	}
+	if project := projectFromFlags(cmd); project != "" {
+		conditions = append(conditions, fmt.Sprintf("project = '%s'", escapeJQL(project)))
+	}
+	if extra, _ := cmd.Flags().GetString("jql"); extra != "" {
+		conditions = append(conditions, "("+extra+")")
+	}
+	jql := strings.Join(conditions, " AND ") + " ORDER BY key ASC"
+
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	issues, err := client.SearchIssues(ctx, jql, limit)
+	if err != nil {
+		return err
+	}
+
+	var entries []timesheetEntry
+	for _, issue := range issues {
+		worklogs, err := client.GetWorklogs(ctx, issue.Key)
+		if err != nil {
+			return fmt.Errorf("fetching worklogs for %s: %w", issue.Key, err)
+		}
+		for _, wl := range worklogs {
+			if worklogByAuthor(client, wl, authorID) {
+				entries = append(entries, timesheetEntry{Key: issue.Key, Summary: issue.Fields.Summary, Worklog: wl})
+			}
+		}
+	}
+
+	sheet := buildTimesheet(entries, from, to, time.Local)
+	sheet.User = user
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(sheet)
+	}
+
+	fmt.Printf("Timesheet for %s, %s to %s\n\n", user, sheet.From, sheet.To)
+	if sheet.Total == 0 {
+		fmt.Println("No work logged")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tDAY\tLOGGED")
+	for _, row := range sheet.ByDay {
+		day, _ := time.Parse(dateLayout, row.Date)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", row.Date, day.Format("Mon"), formatWorkSeconds(row.Seconds))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "KEY\tSUMMARY\tLOGGED")
+	for _, row := range sheet.ByIssue {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", row.Key, cmdutil.Truncate(row.Summary, cmdutil.TitleTruncateNormal), formatWorkSeconds(row.Seconds))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nTotal: %s\n", formatWorkSeconds(sheet.Total))
+	return nil
+}
+
+// timesheetRange reads --from/--to, defaulting to Monday of the current
+// week through today. The returned to is the start of the last day.
+func timesheetRange(cmd *cobra.Command, now time.Time) (time.Time, time.Time, error) {
+	fromStr, _ := cmd.Flags().GetString("from")
+	toStr, _ := cmd.Flags().GetString("to")
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	from := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	to := today
+
+	var err error
+	if fromStr != "" {
+		if from, err = time.ParseInLocation(dateLayout, fromStr, time.Local); err != nil {
+			return from, to, fmt.Errorf("invalid --from %q: use YYYY-MM-DD", fromStr)
+		}
+	}
+	if toStr != "" {
+		if to, err = time.ParseInLocation(dateLayout, toStr, time.Local); err != nil {
+			return from, to, fmt.Errorf("invalid --to %q: use YYYY-MM-DD", toStr)
+		}
+	}
+	if to.Before(from) {
+		return from, to, fmt.Errorf("--to %s is before --from %s", to.Format(dateLayout), from.Format(dateLayout))
+	}
+	return from, to, nil
+}
+
+// worklogByAuthor reports whether wl was logged by the user with id, an
+// account ID on Cloud or a username on Server (see jiraUserID).
+func worklogByAuthor(client *api.JiraClient, wl api.Worklog, id string) bool {
+	return id != "" && strings.EqualFold(jiraUserID(client, wl.Author), id)
+}
+
+// buildTimesheet totals the worklogs started within [from, to] (whole days,
+// in loc) per day and per issue.
+func buildTimesheet(entries []timesheetEntry, from, to time.Time, loc *time.Location) timesheet {
+	sheet := timesheet{From: from.Format(dateLayout), To: to.Format(dateLayout)}
+	end := to.AddDate(0, 0, 1)
+
+	byDay := map[string]int{}
+	byIssue := map[string]*timesheetRow{}
+	var issueOrder []string
+
+	for _, e := range entries {
+		started := e.Worklog.StartedTime().In(loc)
+		if started.Before(from) || !started.Before(end) {
+			continue
+		}
+
+		secs := e.Worklog.TimeSpentSeconds
+		byDay[started.Format(dateLayout)] += secs
+		row, ok := byIssue[e.Key]
+		if !ok {
+			row = &timesheetRow{Key: e.Key, Summary: e.Summary}
+			byIssue[e.Key] = row
+			issueOrder = append(issueOrder, e.Key)
+		}
+		row.Seconds += secs
+		sheet.Total += secs
+	}
+
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		if secs, ok := byDay[date]; ok {
+			sheet.ByDay = append(sheet.ByDay, timesheetRow{Date: date, Seconds: secs})
+		}
+	}
+	sort.Strings(issueOrder)
+	for _, key := range issueOrder {
+		sheet.ByIssue = append(sheet.ByIssue, *byIssue[key])
+	}
+
+	return sheet
+}
+
+func init() {
+	issueCmd.AddCommand(issueWorklogCmd)
+	issueCmd.AddCommand(issueTimesheetCmd)
+	issueWorklogCmd.AddCommand(worklogAddCmd)
+	issueWorklogCmd.AddCommand(worklogListCmd)
+	issueWorklogCmd.AddCommand(worklogEditCmd)
+	issueWorklogCmd.AddCommand(worklogDeleteCmd)
+
+	for _, c := range []*cobra.Command{worklogAddCmd, worklogEditCmd} {
+		c.Flags().StringP("comment", "m", "", "Worklog comment")
+		c.Flags().String("started", "", "When the work started (YYYY-MM-DD, 'YYYY-MM-DD HH:MM' or RFC 3339)")
+	}
+	worklogListCmd.Flags().Bool("json", false, "Output as JSON")
+
+	f := issueTimesheetCmd.Flags()
+	f.String("from", "", "First day, YYYY-MM-DD (default: Monday of this week)")
+	f.String("to", "", "Last day, YYYY-MM-DD (default: today)")
+	f.StringP("user", "u", "", "Whose work to report (default: you)")
+	f.StringP("project", "p", "", "Limit to a project (default: jira.default_project)")
+	f.StringP("jql", "q", "", "Additional JQL conditions")
+	f.Int("limit", 200, "Maximum number of issues to scan")
+	f.Bool("json", false, "Output as JSON")
+}
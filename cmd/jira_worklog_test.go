@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestParseStarted(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2024-05-06", time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC), false},
+		{"2024-05-06 14:30", time.Date(2024, 5, 6, 14, 30, 0, 0, time.UTC), false},
+		{"2024-05-06T14:30", time.Date(2024, 5, 6, 14, 30, 0, 0, time.UTC), false},
+		{"2024-05-06T14:30:00Z", time.Date(2024, 5, 6, 14, 30, 0, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseStarted(tt.in, time.UTC)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseStarted(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseStarted(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatWorkSeconds(t *testing.T) {
+	tests := []struct {
+		in   int
+		want string
+	}{
+		{0, "0m"},
+		{1800, "30m"},
+		{7200, "2h"},
+		{27000, "7h 30m"},
+		{90000, "25h"},
+	}
+
+	for _, tt := range tests {
+		if got := formatWorkSeconds(tt.in); got != tt.want {
+			t.Errorf("formatWorkSeconds(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuildTimesheet(t *testing.T) {
+	wl := func(started string, secs int) api.Worklog {
+		return api.Worklog{Started: started, TimeSpentSeconds: secs}
+	}
+	entries := []timesheetEntry{
+		{Key: "P-2", Summary: "second", Worklog: wl("2024-05-07T10:00:00.000+0000", 3600)},
+		{Key: "P-1", Summary: "first", Worklog: wl("2024-05-06T09:00:00.000+0000", 7200)},
+		{Key: "P-1", Summary: "first", Worklog: wl("2024-05-07T15:00:00.000+0000", 1800)},
+		// Outside the range on both ends.
+		{Key: "P-1", Summary: "first", Worklog: wl("2024-05-05T23:59:00.000+0000", 600)},
+		{Key: "P-3", Summary: "third", Worklog: wl("2024-05-09T00:00:00.000+0000", 600)},
+	}
+
+	from := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)
+	got := buildTimesheet(entries, from, to, time.UTC)
+
+	want := timesheet{
+		From: "2024-05-06",
+		To:   "2024-05-08",
+		ByDay: []timesheetRow{
+			{Date: "2024-05-06", Seconds: 7200},
+			{Date: "2024-05-07", Seconds: 5400},
+		},
+		ByIssue: []timesheetRow{
+			{Key: "P-1", Summary: "first", Seconds: 9000},
+			{Key: "P-2", Summary: "second", Seconds: 3600},
+		},
+		Total: 12600,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildTimesheet() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestWorklogByAuthor(t *testing.T) {
+	server := api.NewJiraClient("https://jira.example.com", "tester", "token")
+	server.InstallationType = api.InstallationTypeServer
+	cloud := api.NewJiraClient("https://example.atlassian.net", "tester", "token")
+	cloud.InstallationType = api.InstallationTypeCloud
+
+	jdoe := api.Worklog{Author: api.JiraUser{Name: "jdoe", DisplayName: "Jane Doe"}}
+	cloudLog := api.Worklog{Author: api.JiraUser{AccountID: "5b10a2844c20165700ede21f", DisplayName: "Jane Doe"}}
+
+	if !worklogByAuthor(server, jdoe, "JDoe") {
+		t.Error("server: username should match")
+	}
+	if worklogByAuthor(server, jdoe, "Jane Doe") {
+		t.Error("server: display name should not match")
+	}
+	if !worklogByAuthor(cloud, cloudLog, "5b10a2844c20165700ede21f") {
+		t.Error("cloud: account ID should match")
+	}
+	if worklogByAuthor(cloud, cloudLog, "") {
+		t.Error("an empty ID should match no one")
+	}
+}
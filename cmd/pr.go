@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -115,20 +114,23 @@ var prListCmd = &cobra.Command{
 			return json.NewEncoder(os.Stdout).Encode(prs)
 		}
 
+		builds := prBuildStats(ctx, client, prs)
+
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "#\tSTATUS\tTITLE\tBRANCH\tAUTHOR")
+		fmt.Fprintln(w, "#\tSTATUS\tTITLE\tBRANCH\tAUTHOR\tBUILD")
 
 		for _, pr := range prs {
 			status := pr.State
 			if pr.State == "OPEN" {
 				status = "OPEN  "
 			}
-			fmt.Fprintf(w, "#%d\t%s\t%s\t%s\t%s\n",
+			fmt.Fprintf(w, "#%d\t%s\t%s\t%s\t%s\t%s\n",
 				pr.ID,
 				status,
 				cmdutil.Truncate(pr.Title, cmdutil.TitleTruncateNormal),
 				cmdutil.Truncate(pr.FromRef.DisplayID, 20),
 				pr.Author.User.Name,
+				buildSummary(builds[pr.FromRef.LatestCommit]),
 			)
 		}
 
@@ -139,31 +141,12 @@ var prListCmd = &cobra.Command{
 var prViewCmd = &cobra.Command{
 	Use:   "view [project/repo] [pr-id]",
 	Short: "View a pull request",
-	Args:  cobra.RangeArgs(1, 2),
+	Args:  cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID or PROJECT/REPO PR-ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -208,6 +191,11 @@ var prViewCmd = &cobra.Command{
 			fmt.Printf("\nDescription:\n%s\n", pr.Description)
 		}
 
+		if builds, err := client.GetCommitBuildStatuses(ctx, pr.FromRef.LatestCommit); err == nil && len(builds) > 0 {
+			fmt.Println("\nBuilds:")
+			printBuilds(builds)
+		}
+
 		if len(pr.Reviewers) > 0 {
 			fmt.Println("\nReviewers:")
 			for _, reviewer := range pr.Reviewers {
@@ -223,55 +211,10 @@ var prViewCmd = &cobra.Command{
 	},
 }
 
-var prDiffCmd = &cobra.Command{
-	Use:   "diff [project/repo] [pr-id]",
-	Short: "View pull request diff",
-	Args:  cobra.RangeArgs(1, 2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID or PROJECT/REPO PR-ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
-		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
-		}
-
-		client, err := getClient()
-		if err != nil {
-			return err
-		}
-		diff, err := client.GetPullRequestDiff(ctx, project, repo, prID)
-		if err != nil {
-			return err
-		}
-
-		fmt.Println(diff)
-		return nil
-	},
-}
-
 func init() {
 	rootCmd.AddCommand(prCmd)
 	prCmd.AddCommand(prListCmd)
 	prCmd.AddCommand(prViewCmd)
-	prCmd.AddCommand(prDiffCmd)
 
 	prListCmd.Flags().String("state", "OPEN", "Filter by state (OPEN, MERGED, DECLINED, ALL)")
 	prListCmd.Flags().Int("limit", cmdutil.DefaultLimit, "Maximum number of results")
@@ -5,7 +5,9 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 
+	"github.com/lroolle/atlas-cli/api"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +16,10 @@ var prCheckoutCmd = &cobra.Command{
 	Short: "Check out a pull request branch locally",
 	Long: `Check out the source branch of a pull request locally.
 
-This fetches the PR's source branch and creates a local branch named pr-<id>.`,
+This fetches the PR's source branch and creates a local branch named pr-<id>.
+Pull requests from forks are fetched through the target repository's
+refs/pull-requests/<id>/from ref, or from the fork's clone URL, when no
+local remote points at the fork.`,
 	Aliases: []string{"co"},
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -41,7 +46,6 @@ This fetches the PR's source branch and creates a local branch named pr-<id>.`,
 			return fmt.Errorf("fetching PR: %w", err)
 		}
 
-		sourceBranch := pr.FromRef.DisplayID
 		localBranch := fmt.Sprintf("pr-%d", prID)
 
 		detach, _ := cmd.Flags().GetBool("detach")
@@ -54,11 +58,7 @@ This fetches the PR's source branch and creates a local branch named pr-<id>.`,
 
 		fmt.Printf("Fetching PR #%d: %s\n", prID, pr.Title)
 		fmt.Printf("  %s -> %s\n", pr.FromRef.DisplayID, pr.ToRef.DisplayID)
-
-		fetchCmd := exec.Command("git", "fetch", "origin", sourceBranch)
-		fetchCmd.Stdout = os.Stdout
-		fetchCmd.Stderr = os.Stderr
-		if err := fetchCmd.Run(); err != nil {
+		if err := fetchPRSource(pr); err != nil {
 			return fmt.Errorf("fetching branch: %w", err)
 		}
 
@@ -114,6 +114,89 @@ This fetches the PR's source branch and creates a local branch named pr-<id>.`,
 	},
 }
 
+// fetchPRSource fetches a pull request's source commit into FETCH_HEAD.
+// The source branch comes from a remote for the source repository when
+// there is one. A fork without a local remote is fetched through the
+// target repository's refs/pull-requests/<id>/from ref, or failing that
+// from the fork's clone URL directly.
+func fetchPRSource(pr *api.PullRequest) error {
+	src, dst := pr.FromRef.Repository, pr.ToRef.Repository
+	if remote, ok := remoteFor(src.Project.Key, src.Slug); ok {
+		fmt.Printf("From %s\n", remote)
+		return gitFetch(remote, pr.FromRef.DisplayID, true)
+	}
+
+	remote := checkoutRemote(dst.Project.Key, dst.Slug)
+	if isSameRepository(src, dst) {
+		fmt.Printf("From %s\n", remote)
+		return gitFetch(remote, pr.FromRef.DisplayID, true)
+	}
+
+	ref := fmt.Sprintf("refs/pull-requests/%d/from", pr.ID)
+	fmt.Printf("From %s (%s)\n", remote, ref)
+	if err := gitFetch(remote, ref, false); err == nil {
+		return nil
+	}
+
+	cloneURL := src.CloneURL(cloneProtocol(remoteURL(remote)))
+	if cloneURL == "" {
+		return fmt.Errorf("%s/%s has no local remote and no clone URL", src.Project.Key, src.Slug)
+	}
+	fmt.Printf("From %s\n", cloneURL)
+	return gitFetch(cloneURL, pr.FromRef.ID, true)
+}
+
+func isSameRepository(a, b api.Repository) bool {
+	return strings.EqualFold(a.Project.Key, b.Project.Key) && strings.EqualFold(a.Slug, b.Slug)
+}
+
+// gitFetch fetches ref from remote, a remote name or URL. Git's errors are
+// shown only when verbose.
+func gitFetch(remote, ref string, verbose bool) error {
+	fetchCmd := exec.Command("git", "fetch", remote, ref)
+	fetchCmd.Stdout = os.Stdout
+	if verbose {
+		fetchCmd.Stderr = os.Stderr
+	}
+	return fetchCmd.Run()
+}
+
+// remoteURL returns the fetch URL of the named remote, or "".
+func remoteURL(name string) string {
+	remotes, err := gitRemotes()
+	if err != nil {
+		return ""
+	}
+	for _, r := range remotes {
+		if r.Name == name {
+			return r.URL
+		}
+	}
+	return ""
+}
+
+// cloneProtocol is the Bitbucket clone link name matching a remote URL, so
+// a fork is fetched with the credentials the checkout already uses.
+func cloneProtocol(url string) string {
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return "http"
+	}
+	return "ssh"
+}
+
+// checkoutRemote picks the remote to fetch a pull request from: the one
+// pointing at project/repo, else the preferred Bitbucket remote, else
+// origin.
+func checkoutRemote(project, repo string) string {
+	if remote, ok := remoteFor(project, repo); ok {
+		return remote
+	}
+	if remotes := localBitbucketRemotes(); len(remotes) > 0 {
+		return remotes[0].Name
+	}
+	return "origin"
+}
+
 func branchExists(name string) bool {
 	err := exec.Command("git", "rev-parse", "--verify", name).Run()
 	return err == nil
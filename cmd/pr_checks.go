@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/spf13/cobra"
+)
+
+var prChecksCmd = &cobra.Command{
+	Use:   "checks [project/repo] [pr-id]",
+	Short: "Show CI builds for a pull request",
+	Long: `Show the builds CI servers reported for a pull request's commits,
+newest commit first.
+
+--watch refreshes the head commit's builds until none is in progress,
+following new pushes, and gives up after --timeout.
+The command exits non-zero when a head commit build failed.`,
+	Example: `  atl pr checks 142
+  atl pr checks 142 --all-commits
+  atl pr checks 142 --watch && atl pr merge 142`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: runPRChecks,
+}
+
+func runPRChecks(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	project, repo, prID, _, err := resolvePRArgs(ctx, args)
+	if err != nil {
+		return err
+	}
+	watch, _ := cmd.Flags().GetBool("watch")
+	allCommits, _ := cmd.Flags().GetBool("all-commits")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	if watch && jsonOutput {
+		return fmt.Errorf("--watch and --json are mutually exclusive")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if watch {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		if interval < minWatchInterval {
+			return fmt.Errorf("--interval must be at least %s", minWatchInterval)
+		}
+		return watchChecks(ctx, client, project, repo, prID, interval, timeout)
+	}
+
+	pr, err := client.GetPullRequest(ctx, project, repo, prID)
+	if err != nil {
+		return err
+	}
+	head := pr.FromRef.LatestCommit
+
+	commits := []api.Commit{{ID: head, DisplayID: shortSHA(head)}}
+	if allCommits {
+		limit, _ := cmd.Flags().GetInt("limit")
+		if commits, err = client.GetPullRequestCommits(ctx, project, repo, prID, limit); err != nil {
+			return err
+		}
+		if len(commits) == limit {
+			fmt.Fprintf(os.Stderr, "Warning: showing the newest %d commits; raise --limit for older ones\n", limit)
+		}
+	}
+
+	results := make([]commitBuilds, 0, len(commits))
+	for _, c := range commits {
+		builds, err := client.GetCommitBuildStatuses(ctx, c.ID)
+		if err != nil {
+			return fmt.Errorf("fetching builds for %s: %w", c.DisplayID, err)
+		}
+		results = append(results, commitBuilds{Commit: c.ID, Message: firstLine(c.Message), Builds: builds})
+	}
+
+	if jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(results)
+	}
+
+	for i, r := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s %s\n", bold(shortSHA(r.Commit)), r.Message)
+		printBuilds(r.Builds)
+	}
+
+	if len(results) == 0 {
+		return nil
+	}
+	if failed := countBuilds(results[0].Builds)[api.BuildFailed]; failed > 0 {
+		return fmt.Errorf("%d build(s) failed on %s", failed, shortSHA(head))
+	}
+	return nil
+}
+
+// commitBuilds is the --json shape of pr checks.
+type commitBuilds struct {
+	Commit  string            `json:"commit"`
+	Message string            `json:"message,omitempty"`
+	Builds  []api.BuildStatus `json:"builds"`
+}
+
+// minWatchInterval is the shortest refresh interval --watch accepts, to
+// keep it from hammering the server.
+const minWatchInterval = 5 * time.Second
+
+// watchChecks redraws the head commit's builds whenever they change and
+// returns once none is in progress. The head is re-read on every poll so a
+// new push is followed; after timeout (0: never) it gives up.
+func watchChecks(ctx context.Context, client *api.BitbucketClient, project, repo string, prID int, interval, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var builds []api.BuildStatus
+	commit, last, shown := "", "", false
+	// timedOut reports the deadline, which can expire mid-request too.
+	timedOut := func(err error) error {
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return err
+		}
+		if len(builds) == 0 {
+			return fmt.Errorf("timed out after %s: no builds reported on %s", timeout, shortSHA(commit))
+		}
+		return fmt.Errorf("timed out after %s: %d build(s) still running on %s", timeout, countBuilds(builds)[api.BuildInProgress], shortSHA(commit))
+	}
+
+	for {
+		pr, err := client.GetPullRequest(ctx, project, repo, prID)
+		if err != nil {
+			return timedOut(err)
+		}
+		if head := pr.FromRef.LatestCommit; head != commit {
+			commit, shown = head, false
+		}
+
+		if builds, err = client.GetCommitBuildStatuses(ctx, commit); err != nil {
+			return timedOut(err)
+		}
+
+		counts := countBuilds(builds)
+		if snapshot := buildsSnapshot(builds); !shown || snapshot != last {
+			last, shown = snapshot, true
+			fmt.Printf("%s PR #%d at %s\n", dim(time.Now().Format("15:04:05")), prID, shortSHA(commit))
+			printBuilds(builds)
+			fmt.Println()
+		}
+
+		if len(builds) > 0 && counts[api.BuildInProgress] == 0 {
+			if counts[api.BuildFailed] > 0 {
+				return fmt.Errorf("%d build(s) failed on %s", counts[api.BuildFailed], shortSHA(commit))
+			}
+			fmt.Printf("✓ All %d build(s) passed\n", len(builds))
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return timedOut(ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+func printBuilds(builds []api.BuildStatus) {
+	if len(builds) == 0 {
+		fmt.Println("  No builds reported")
+		return
+	}
+	sortBuilds(builds)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, b := range builds {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", buildStateLabel(b.State), valueOr(b.Name, b.Key), b.URL,
+			time.UnixMilli(b.DateAdded).Format("2006-01-02 15:04"))
+	}
+	w.Flush()
+}
+
+// sortBuilds puts failures first, then running builds, then passes.
+func sortBuilds(builds []api.BuildStatus) {
+	rank := map[string]int{api.BuildFailed: 0, api.BuildInProgress: 1, api.BuildSuccessful: 2}
+	sort.SliceStable(builds, func(i, j int) bool {
+		ri, rj := rank[builds[i].State], rank[builds[j].State]
+		if ri != rj {
+			return ri < rj
+		}
+		return valueOr(builds[i].Name, builds[i].Key) < valueOr(builds[j].Name, builds[j].Key)
+	})
+}
+
+func countBuilds(builds []api.BuildStatus) map[string]int {
+	counts := map[string]int{}
+	for _, b := range builds {
+		counts[b.State]++
+	}
+	return counts
+}
+
+func buildsSnapshot(builds []api.BuildStatus) string {
+	parts := make([]string, len(builds))
+	for i, b := range builds {
+		parts[i] = b.Key + "=" + b.State
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+func buildStateLabel(state string) string {
+	switch state {
+	case api.BuildSuccessful:
+		return green("✓ passed")
+	case api.BuildFailed:
+		return red("✗ failed")
+	case api.BuildInProgress:
+		return yellow("● running")
+	}
+	return strings.ToLower(state)
+}
+
+// buildSummary condenses build stats to one cell: the worst state and its
+// count.
+func buildSummary(s api.BuildStats) string {
+	switch {
+	case s.Total() == 0:
+		return "-"
+	case s.Failed > 0:
+		return red(fmt.Sprintf("✗ %d/%d failed", s.Failed, s.Total()))
+	case s.InProgress > 0:
+		return yellow(fmt.Sprintf("● %d/%d running", s.InProgress, s.Total()))
+	}
+	return green(fmt.Sprintf("✓ %d passed", s.Successful))
+}
+
+// prBuildStats fetches build stats for the head commits of prs. Builds are
+// extra information, so a failure (e.g. build status not available) only
+// yields no stats.
+func prBuildStats(ctx context.Context, client *api.BitbucketClient, prs []api.PullRequest) map[string]api.BuildStats {
+	commits := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		if pr.FromRef.LatestCommit != "" {
+			commits = append(commits, pr.FromRef.LatestCommit)
+		}
+	}
+	stats, err := client.GetBuildStats(ctx, commits)
+	if err != nil {
+		return nil
+	}
+	return stats
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 11 {
+		return sha[:11]
+	}
+	return sha
+}
+
+func init() {
+	prCmd.AddCommand(prChecksCmd)
+	prChecksCmd.Flags().Bool("watch", false, "Refresh until all builds finish")
+	prChecksCmd.Flags().Duration("interval", 10*time.Second, "Refresh interval for --watch (at least 5s)")
+	prChecksCmd.Flags().Duration("timeout", 30*time.Minute, "How long --watch waits (0: no limit)")
+	prChecksCmd.Flags().Bool("all-commits", false, "Show builds for every commit in the PR")
+	prChecksCmd.Flags().Int("limit", 50, "Maximum number of commits for --all-commits")
+	prChecksCmd.Flags().Bool("json", false, "Output as JSON")
+}
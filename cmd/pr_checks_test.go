@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestBuildSummary(t *testing.T) {
+	tests := []struct {
+		stats api.BuildStats
+		want  string
+	}{
+		{api.BuildStats{}, "-"},
+		{api.BuildStats{Successful: 3}, "✓ 3 passed"},
+		{api.BuildStats{Successful: 1, InProgress: 2}, "● 2/3 running"},
+		{api.BuildStats{Successful: 1, InProgress: 1, Failed: 1}, "✗ 1/3 failed"},
+	}
+	for _, tt := range tests {
+		if got := buildSummary(tt.stats); got != tt.want {
+			t.Errorf("buildSummary(%+v) = %q, want %q", tt.stats, got, tt.want)
+		}
+	}
+}
+
+func TestSortBuilds(t *testing.T) {
+	builds := []api.BuildStatus{
+		{Key: "lint", State: api.BuildSuccessful},
+		{Key: "unit", State: api.BuildInProgress},
+		{Key: "e2e", State: api.BuildFailed},
+		{Key: "build", Name: "Build", State: api.BuildSuccessful},
+	}
+	sortBuilds(builds)
+
+	var got []string
+	for _, b := range builds {
+		got = append(got, b.Key)
+	}
+	want := []string{"e2e", "unit", "build", "lint"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortBuilds order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBuildsSnapshot(t *testing.T) {
+	a := []api.BuildStatus{{Key: "b", State: api.BuildInProgress}, {Key: "a", State: api.BuildSuccessful}}
+	b := []api.BuildStatus{{Key: "a", State: api.BuildSuccessful}, {Key: "b", State: api.BuildInProgress}}
+	if buildsSnapshot(a) != buildsSnapshot(b) {
+		t.Errorf("snapshot depends on order: %q vs %q", buildsSnapshot(a), buildsSnapshot(b))
+	}
+	b[1].State = api.BuildFailed
+	if buildsSnapshot(a) == buildsSnapshot(b) {
+		t.Error("snapshot ignores state changes")
+	}
+}
+
+func TestWatchChecksFollowsNewPush(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PROJ/repos/app/pull-requests/7":
+			polls++
+			head := "aaaa"
+			if polls > 1 {
+				head = "bbbb"
+			}
+			fmt.Fprintf(w, `{"id":7,"fromRef":{"latestCommit":%q}}`, head)
+		case "/rest/build-status/1.0/commits/aaaa":
+			fmt.Fprint(w, `{"values":[],"isLastPage":true}`)
+		case "/rest/build-status/1.0/commits/bbbb":
+			fmt.Fprint(w, `{"values":[{"key":"ci","state":"SUCCESSFUL"}],"isLastPage":true}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	if err := watchChecks(context.Background(), client, "PROJ", "app", 7, time.Millisecond, time.Minute); err != nil {
+		t.Fatalf("watchChecks returned error: %v", err)
+	}
+	if polls != 2 {
+		t.Errorf("polls = %d, want 2", polls)
+	}
+}
+
+func TestWatchChecksTimesOutWithoutBuilds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/rest/build-status/") {
+			fmt.Fprint(w, `{"values":[],"isLastPage":true}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":7,"fromRef":{"latestCommit":"aaaa"}}`)
+	}))
+	defer server.Close()
+
+	client := api.NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	err := watchChecks(context.Background(), client, "PROJ", "app", 7, time.Millisecond, 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "no builds reported") {
+		t.Errorf("watchChecks error = %v, want a timeout without builds", err)
+	}
+}
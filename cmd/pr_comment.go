@@ -184,7 +184,7 @@ func resolveAnchor(diff *api.Diff, spec commentSpec, side api.DiffSide) (*api.Co
 }
 
 var prCommentCmd = &cobra.Command{
-	Use:   "comment [project/repo] <pr-id> [text]",
+	Use:   "comment [project/repo] [pr-id] [text]",
 	Short: "Comment on a pull request, optionally on a specific line",
 	Long: `Add a comment to a pull request.
 
@@ -214,19 +214,33 @@ their surrounding context) can be anchored.`,
 
   # Correct or remove an existing comment (yours; pending or published)
   atl pr comment MYPROJ/myrepo 140 --edit 331 -b "corrected text"
-  atl pr comment MYPROJ/myrepo 140 --delete 331`,
-	Args: cobra.RangeArgs(1, 3),
+  atl pr comment MYPROJ/myrepo 140 --delete 331
+
+  # Resolve or reopen a task or thread, react to a comment
+  atl pr comment MYPROJ/myrepo 140 --resolve 331
+  atl pr comment MYPROJ/myrepo 140 --reopen 331
+  atl pr comment MYPROJ/myrepo 140 --react 331 :thumbsup:`,
+	Args: cobra.RangeArgs(0, 3),
 	RunE: runPRComment,
 }
 
 func runPRComment(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	project, repo, prID, rest, err := parsePRArgs(args)
+	project, repo, prID, rest, err := resolvePRArgs(ctx, args)
 	if err != nil {
 		return err
 	}
 
+	if resolveID, _ := cmd.Flags().GetInt("resolve"); resolveID != 0 {
+		return runPRCommentResolve(cmd, project, repo, prID, resolveID, true, rest)
+	}
+	if reopenID, _ := cmd.Flags().GetInt("reopen"); reopenID != 0 {
+		return runPRCommentResolve(cmd, project, repo, prID, reopenID, false, rest)
+	}
+	if reactID, _ := cmd.Flags().GetInt("react"); reactID != 0 {
+		return runPRCommentReact(cmd, project, repo, prID, reactID, rest)
+	}
 	if editID, _ := cmd.Flags().GetInt("edit"); editID != 0 {
 		return runPRCommentEdit(cmd, project, repo, prID, editID, rest)
 	}
@@ -303,7 +317,7 @@ func lifecycleFlagConflict(cmd *cobra.Command, action string, extra ...string) e
 func runPRCommentEdit(cmd *cobra.Command, project, repo string, prID, commentID int, positionalText string) error {
 	ctx := cmd.Context()
 
-	if err := lifecycleFlagConflict(cmd, "edit", "delete"); err != nil {
+	if err := lifecycleFlagConflict(cmd, "edit", "delete", "resolve", "reopen", "react"); err != nil {
 		return err
 	}
 	body, err := resolveCommentBody(cmd, positionalText)
@@ -335,7 +349,7 @@ func runPRCommentEdit(cmd *cobra.Command, project, repo string, prID, commentID
 func runPRCommentDelete(cmd *cobra.Command, project, repo string, prID, commentID int, positionalText string) error {
 	ctx := cmd.Context()
 
-	if err := lifecycleFlagConflict(cmd, "delete", "body", "body-file", "edit"); err != nil {
+	if err := lifecycleFlagConflict(cmd, "delete", "body", "body-file", "edit", "resolve", "reopen", "react"); err != nil {
 		return err
 	}
 	if positionalText != "" {
@@ -359,6 +373,120 @@ func runPRCommentDelete(cmd *cobra.Command, project, repo string, prID, commentI
 	return nil
 }
 
+// runPRCommentResolve resolves or reopens a task, or the thread a regular
+// comment starts.
+func runPRCommentResolve(cmd *cobra.Command, project, repo string, prID, commentID int, resolve bool, positionalText string) error {
+	ctx := cmd.Context()
+
+	action, other := "resolve", "reopen"
+	if !resolve {
+		action, other = other, action
+	}
+	if err := lifecycleFlagConflict(cmd, action, "body", "body-file", "edit", "delete", other, "react"); err != nil {
+		return err
+	}
+	if positionalText != "" {
+		return fmt.Errorf("--%s takes no comment text", action)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	current, err := client.GetPullRequestComment(ctx, project, repo, prID, commentID)
+	if err != nil {
+		return fmt.Errorf("fetching comment %d: %w", commentID, err)
+	}
+
+	kind := "thread"
+	if current.Severity == api.SeverityBlocker {
+		kind = "task"
+	}
+	if isResolved(*current) == resolve {
+		if resolve {
+			fmt.Printf("%s %d is already resolved\n", kind, commentID)
+		} else {
+			fmt.Printf("%s %d is already open\n", kind, commentID)
+		}
+		return nil
+	}
+
+	var updated *api.Comment
+	if current.Severity == api.SeverityBlocker {
+		state := api.CommentStateOpen
+		if resolve {
+			state = api.CommentStateResolved
+		}
+		updated, err = client.SetPullRequestCommentState(ctx, project, repo, prID, commentID, current.Version, state)
+	} else {
+		updated, err = client.SetPullRequestThreadResolved(ctx, project, repo, prID, commentID, current.Version, resolve)
+	}
+	if err != nil {
+		return fmt.Errorf("updating comment %d: %w", commentID, err)
+	}
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(updated)
+	}
+	if resolve {
+		fmt.Printf("✓ Resolved %s %d\n", kind, commentID)
+	} else {
+		fmt.Printf("✓ Reopened %s %d\n", kind, commentID)
+	}
+	return nil
+}
+
+// isResolved reports whether a task or a thread's root comment is resolved.
+func isResolved(c api.Comment) bool {
+	return c.State == api.CommentStateResolved || c.ThreadResolved
+}
+
+func runPRCommentReact(cmd *cobra.Command, project, repo string, prID, commentID int, emoji string) error {
+	ctx := cmd.Context()
+
+	if err := lifecycleFlagConflict(cmd, "react", "body", "body-file", "edit", "delete", "resolve", "reopen"); err != nil {
+		return err
+	}
+	name, err := reactionName(emoji)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	if err := client.AddCommentReaction(ctx, project, repo, prID, commentID, name); err != nil {
+		return fmt.Errorf("reacting to comment %d: %w", commentID, err)
+	}
+
+	fmt.Printf("✓ Reacted :%s: to comment %d\n", name, commentID)
+	return nil
+}
+
+// reactionAliases maps emoji and common shorthands to Bitbucket's emoticon
+// names.
+var reactionAliases = map[string]string{
+	"+1": "thumbsup", "👍": "thumbsup",
+	"-1": "thumbsdown", "👎": "thumbsdown",
+	"❤️": "heart", "❤": "heart",
+	"😄": "smile", "🎉": "tada", "👀": "eyes", "🚀": "rocket",
+}
+
+// reactionName turns ":thumbsup:", "thumbsup", "+1" or "👍" into the
+// emoticon name the reactions endpoint takes.
+func reactionName(emoji string) (string, error) {
+	name := strings.ToLower(strings.Trim(strings.TrimSpace(emoji), ":"))
+	if alias, ok := reactionAliases[name]; ok {
+		return alias, nil
+	}
+	if name == "" || strings.ContainsAny(name, " /") {
+		return "", fmt.Errorf("--react needs an emoji, e.g. :thumbsup: (got %q)", emoji)
+	}
+	return name, nil
+}
+
 // collectSpecs builds the comment list from either --batch or the flags and
 // positional text.
 func collectSpecs(cmd *cobra.Command, positionalText, batchFile string) ([]commentSpec, error) {
@@ -510,7 +638,7 @@ func describeTarget(spec commentSpec, anchor *api.CommentAnchor) string {
 }
 
 var prCommentsCmd = &cobra.Command{
-	Use:     "comments [project/repo] <pr-id>",
+	Use:     "comments [project/repo] [pr-id]",
 	Short:   "List comments on a pull request, grouped by file and line",
 	Aliases: []string{"reviews"},
 	Long: `List the comments on a pull request.
@@ -518,7 +646,7 @@ var prCommentsCmd = &cobra.Command{
 Comments are grouped by what they are anchored to: general pull request
 comments first, then each file and line. Comment IDs shown here are what
 'atl pr comment --reply' takes.`,
-	Args: cobra.RangeArgs(1, 2),
+	Args: cobra.RangeArgs(0, 2),
 	RunE: runPRComments,
 }
 
@@ -532,7 +660,7 @@ type commentEntry struct {
 func runPRComments(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	project, repo, prID, _, err := parsePRArgs(args)
+	project, repo, prID, _, err := resolvePRArgs(ctx, args)
 	if err != nil {
 		return err
 	}
@@ -546,6 +674,19 @@ func runPRComments(cmd *cobra.Command, args []string) error {
 	fileFilter, _ := cmd.Flags().GetString("file")
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	pending, _ := cmd.Flags().GetBool("pending")
+	state, _ := cmd.Flags().GetString("state")
+	unresolvedOnly, _ := cmd.Flags().GetBool("unresolved-only")
+
+	state = strings.ToLower(state)
+	if err := validateEnum("state", state, "open", "resolved"); err != nil {
+		return err
+	}
+	if unresolvedOnly {
+		if state == "resolved" {
+			return errors.New("--unresolved-only cannot be combined with --state resolved")
+		}
+		state = "open"
+	}
 
 	var entries []commentEntry
 	if pending {
@@ -568,6 +709,9 @@ func runPRComments(cmd *cobra.Command, args []string) error {
 	if fileFilter != "" {
 		entries = filterEntriesByFile(entries, fileFilter)
 	}
+	if state != "" {
+		entries = filterEntriesByState(entries, state == "resolved")
+	}
 
 	if jsonOutput {
 		return json.NewEncoder(os.Stdout).Encode(entries)
@@ -691,6 +835,22 @@ func filterEntriesByFile(entries []commentEntry, file string) []commentEntry {
 	return filtered
 }
 
+// filterEntriesByState keeps the threads whose root comment is resolved, or
+// unresolved.
+func filterEntriesByState(entries []commentEntry, resolved bool) []commentEntry {
+	var filtered []commentEntry
+	keep := false
+	for _, e := range entries {
+		if e.Depth == 0 {
+			keep = isResolved(e.Comment) == resolved
+		}
+		if keep {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
 func printCommentEntries(entries []commentEntry) {
 	lastGroup := "\x00"
 
@@ -733,6 +893,8 @@ func commentMarkers(e commentEntry) string {
 	}
 	if state := e.Comment.State; state != "" && state != api.CommentStateOpen {
 		markers = append(markers, state)
+	} else if e.Comment.ThreadResolved {
+		markers = append(markers, api.CommentStateResolved)
 	}
 	if e.Anchor != nil && e.Anchor.Orphaned {
 		markers = append(markers, "ORPHANED")
@@ -743,36 +905,121 @@ func commentMarkers(e commentEntry) string {
 	return "  [" + strings.Join(markers, "] [") + "]"
 }
 
-// parsePRArgs handles the shared [project/repo] <pr-id> [text] argument shape.
+// parsePRArgs handles the shared [project/repo] [pr-id] [text] argument
+// shape. A missing PR ID comes back as 0; resolvePRArgs infers it. A single
+// argument that is neither a number nor PROJECT/REPO is the text.
 func parsePRArgs(args []string) (project, repo string, prID int, rest string, err error) {
-	var prIDStr string
+	var repoArg, prIDStr string
 
-	if len(args) > 1 && !isNumeric(args[0]) {
-		project, repo, err = parseRepoArg(args[0])
-		if err != nil {
-			return "", "", 0, "", err
+	switch {
+	case len(args) == 0:
+	case isNumeric(args[0]):
+		prIDStr = args[0]
+		if len(args) > 1 {
+			rest = args[1]
 		}
-		prIDStr = args[1]
+	case len(args) > 1:
+		repoArg, prIDStr = args[0], args[1]
 		if len(args) > 2 {
 			rest = args[2]
 		}
-	} else {
-		project, repo, err = parseRepoArg("")
+	case looksLikeRepoArg(args[0]):
+		repoArg = args[0]
+	default:
+		rest = args[0]
+	}
+
+	project, repo, err = parseRepoArg(repoArg)
+	if err != nil {
+		return "", "", 0, "", err
+	}
+
+	if prIDStr != "" {
+		prID, err = strconv.Atoi(prIDStr)
 		if err != nil {
-			return "", "", 0, "", fmt.Errorf("PR ID required: %w", err)
-		}
-		prIDStr = args[0]
-		if len(args) > 1 {
-			rest = args[1]
+			return "", "", 0, "", fmt.Errorf("invalid PR ID: %q", prIDStr)
 		}
 	}
 
-	prID, err = strconv.Atoi(prIDStr)
+	return project, repo, prID, rest, nil
+}
+
+// looksLikeRepoArg reports whether s is a PROJECT/REPO argument rather than
+// comment text.
+func looksLikeRepoArg(s string) bool {
+	project, repo, found := strings.Cut(s, "/")
+	return found && project != "" && repo != "" &&
+		!strings.ContainsAny(s, " \t\n") && !strings.Contains(repo, "/")
+}
+
+// resolvePRArgs is parsePRArgs for commands that act on one pull request:
+// without a PR ID it uses the open pull request of the current branch, in
+// the repository that pull request targets.
+func resolvePRArgs(ctx context.Context, args []string) (project, repo string, prID int, rest string, err error) {
+	project, repo, prID, rest, err = parsePRArgs(args)
+	if err != nil || prID != 0 {
+		return project, repo, prID, rest, err
+	}
+
+	branch, err := getCurrentGitBranch()
+	if err != nil || branch == "HEAD" {
+		return "", "", 0, "", errors.New("PR ID required: not on a git branch to infer it from")
+	}
+
+	// A pull request from a fork is an outgoing pull request of the fork,
+	// not of the repository it targets, so look in the repository the branch
+	// is pushed to.
+	fromProject, fromRepo, fromBranch := project, repo, branch
+	if remote, tracked, ok := branchUpstream(branch); ok {
+		fromProject, fromRepo, fromBranch = remote.Project, remote.Repo, tracked
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return "", "", 0, "", err
+	}
+	prs, err := client.ListPullRequestsFromBranch(ctx, fromProject, fromRepo, fromBranch)
 	if err != nil {
-		return "", "", 0, "", fmt.Errorf("invalid PR ID: %q", prIDStr)
+		return "", "", 0, "", fmt.Errorf("looking up the pull request for %s: %w", branch, err)
 	}
 
-	return project, repo, prID, rest, nil
+	pr, err := pickBranchPR(prs, fromProject, fromRepo, fromBranch)
+	if err != nil {
+		return "", "", 0, "", fmt.Errorf("%w in %s/%s; pass the PR ID", err, fromProject, fromRepo)
+	}
+	if target := pr.ToRef.Repository; target.Slug != "" {
+		project, repo = target.Project.Key, target.Slug
+	}
+	fmt.Fprintf(os.Stderr, "Using PR #%d for branch %s\n", pr.ID, branch)
+	return project, repo, pr.ID, rest, nil
+}
+
+// pickBranchPR returns the one pull request whose source is branch of
+// project/repo.
+func pickBranchPR(prs []api.PullRequest, project, repo, branch string) (api.PullRequest, error) {
+	var ids []string
+	var match api.PullRequest
+	for _, pr := range prs {
+		if !branchMatches(pr.FromRef, branch) || !refInRepo(pr.FromRef, project, repo) {
+			continue
+		}
+		ids = append(ids, fmt.Sprintf("#%d", pr.ID))
+		match = pr
+	}
+	switch len(ids) {
+	case 0:
+		return api.PullRequest{}, fmt.Errorf("no open pull request from branch %s", branch)
+	case 1:
+		return match, nil
+	}
+	return api.PullRequest{}, fmt.Errorf("several open pull requests from branch %s (%s)", branch, strings.Join(ids, ", "))
+}
+
+// refInRepo reports whether ref belongs to project/repo; refs without
+// repository details match any.
+func refInRepo(ref api.Ref, project, repo string) bool {
+	r := ref.Repository
+	return r.Slug == "" || (strings.EqualFold(r.Project.Key, project) && strings.EqualFold(r.Slug, repo))
 }
 
 func isNumeric(s string) bool {
@@ -799,9 +1046,14 @@ func init() {
 	prCommentCmd.Flags().String("file-type", "", "Override the resolved file side (TO, FROM)")
 	prCommentCmd.Flags().Int("edit", 0, "Replace the text of an existing comment ID")
 	prCommentCmd.Flags().Int("delete", 0, "Delete an existing comment ID")
+	prCommentCmd.Flags().Int("resolve", 0, "Resolve a task or comment thread by ID")
+	prCommentCmd.Flags().Int("reopen", 0, "Reopen a resolved task or comment thread by ID")
+	prCommentCmd.Flags().Int("react", 0, "React to a comment ID with the emoji given as text")
 
 	prCommentsCmd.Flags().StringP("file", "f", "", "Only show comments anchored to paths containing this string")
 	prCommentsCmd.Flags().Int("limit", cmdutil.DefaultActivityLimit, "Maximum number of activities to scan")
 	prCommentsCmd.Flags().Bool("pending", false, "Show your unpublished review comments instead")
+	prCommentsCmd.Flags().String("state", "", "Only show threads that are open or resolved")
+	prCommentsCmd.Flags().Bool("unresolved-only", false, "Only show unresolved threads (same as --state open)")
 	prCommentsCmd.Flags().Bool("json", false, "Output as JSON")
 }
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/lroolle/atlas-cli/api"
+	"github.com/spf13/viper"
 )
 
 func TestCommentSpecValidate(t *testing.T) {
@@ -205,6 +211,56 @@ func TestParsePRArgs(t *testing.T) {
 	if _, _, _, _, err := parsePRArgs([]string{"MYPROJ/myrepo", "nine"}); err == nil {
 		t.Error("expected an error for a non-numeric PR ID")
 	}
+
+	// Without a PR ID the ID is left to be inferred from the branch.
+	project, repo, prID, rest, err = parsePRArgs([]string{"MYPROJ/myrepo"})
+	if err != nil || project != "MYPROJ" || repo != "myrepo" || prID != 0 || rest != "" {
+		t.Errorf("repo only: got %s/%s #%d %q, %v", project, repo, prID, rest, err)
+	}
+
+	// No local remote matches this server, so the config defaults apply.
+	viper.Set("bitbucket.server", "https://git.invalid")
+	viper.Set("bitbucket.default_project", "DEF")
+	viper.Set("bitbucket.default_repo", "app")
+	defer func() {
+		viper.Set("bitbucket.server", "")
+		viper.Set("bitbucket.default_project", "")
+		viper.Set("bitbucket.default_repo", "")
+	}()
+
+	project, repo, prID, rest, err = parsePRArgs([]string{"looks good to me"})
+	if err != nil || project != "DEF" || repo != "app" || prID != 0 || rest != "looks good to me" {
+		t.Errorf("text only: got %s/%s #%d %q, %v", project, repo, prID, rest, err)
+	}
+	project, repo, prID, _, err = parsePRArgs(nil)
+	if err != nil || project != "DEF" || repo != "app" || prID != 0 {
+		t.Errorf("no args: got %s/%s #%d, %v", project, repo, prID, err)
+	}
+}
+
+func TestPickBranchPR(t *testing.T) {
+	prs := []api.PullRequest{
+		{ID: 7, FromRef: api.Ref{ID: "refs/heads/feature/csv", DisplayID: "feature/csv"}},
+		{ID: 9, FromRef: api.Ref{ID: "refs/heads/feature/csv-v2", DisplayID: "feature/csv-v2"}},
+	}
+
+	if pr, err := pickBranchPR(prs, "PROJ", "app", "feature/csv"); err != nil || pr.ID != 7 {
+		t.Errorf("pickBranchPR = %d, %v; want 7", pr.ID, err)
+	}
+	if _, err := pickBranchPR(prs, "PROJ", "app", "main"); err == nil {
+		t.Error("expected an error for a branch without pull request")
+	}
+	if _, err := pickBranchPR(append(prs, api.PullRequest{ID: 11, FromRef: prs[0].FromRef}), "PROJ", "app", "feature/csv"); err == nil || !strings.Contains(err.Error(), "#7, #11") {
+		t.Errorf("ambiguous branch error = %v", err)
+	}
+
+	fork := api.Ref{ID: "refs/heads/feature/csv", DisplayID: "feature/csv", Repository: api.Repository{Slug: "app", Project: api.Project{Key: "~JDOE"}}}
+	if _, err := pickBranchPR([]api.PullRequest{{ID: 12, FromRef: fork}}, "PROJ", "app", "feature/csv"); err == nil {
+		t.Error("a pull request from another repository's branch should not match")
+	}
+	if pr, err := pickBranchPR([]api.PullRequest{{ID: 12, FromRef: fork}}, "~jdoe", "app", "feature/csv"); err != nil || pr.ID != 12 {
+		t.Errorf("fork pickBranchPR = %d, %v; want 12", pr.ID, err)
+	}
 }
 
 func TestCommentsFromActivities(t *testing.T) {
@@ -276,6 +332,53 @@ func TestFilterEntriesByFileKeepsReplies(t *testing.T) {
 	}
 }
 
+func TestFilterEntriesByState(t *testing.T) {
+	entries := []commentEntry{
+		{Comment: api.Comment{ID: 1, State: api.CommentStateOpen}},
+		{Comment: api.Comment{ID: 2, ThreadResolved: true}},
+		{Comment: api.Comment{ID: 3}, Depth: 1},
+		{Comment: api.Comment{ID: 4, Severity: api.SeverityBlocker, State: api.CommentStateResolved}},
+		{Comment: api.Comment{ID: 5, Severity: api.SeverityBlocker, State: api.CommentStateOpen}},
+	}
+
+	ids := func(entries []commentEntry) []int {
+		var out []int
+		for _, e := range entries {
+			out = append(out, e.Comment.ID)
+		}
+		return out
+	}
+
+	if got := ids(filterEntriesByState(entries, true)); len(got) != 3 || got[0] != 2 || got[1] != 3 || got[2] != 4 {
+		t.Errorf("resolved = %v, want [2 3 4]", got)
+	}
+	if got := ids(filterEntriesByState(entries, false)); len(got) != 2 || got[0] != 1 || got[1] != 5 {
+		t.Errorf("open = %v, want [1 5]", got)
+	}
+}
+
+func TestReactionName(t *testing.T) {
+	tests := map[string]string{
+		":thumbsup:": "thumbsup",
+		"thumbsup":   "thumbsup",
+		"+1":         "thumbsup",
+		"👍":          "thumbsup",
+		":Heart:":    "heart",
+		":tada:":     "tada",
+	}
+	for input, want := range tests {
+		got, err := reactionName(input)
+		if err != nil || got != want {
+			t.Errorf("reactionName(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "::", "two words"} {
+		if _, err := reactionName(input); err == nil {
+			t.Errorf("reactionName(%q) should fail", input)
+		}
+	}
+}
+
 func TestDescribeTarget(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -335,3 +438,76 @@ func TestLifecycleFlagConflict(t *testing.T) {
 		t.Fatalf("expected --file/--edit conflict, got: %v", err)
 	}
 }
+
+func TestResolvePRArgs(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		if r.URL.Query().Get("at") != "refs/heads/feature/csv" || r.URL.Query().Get("direction") != "OUTGOING" {
+			t.Errorf("request = %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PROJ/repos/app/pull-requests":
+			fmt.Fprint(w, `{"values":[{"id":7,"fromRef":{"id":"refs/heads/feature/csv","displayId":"feature/csv"}}],"isLastPage":true}`)
+		case "/rest/api/1.0/projects/~jdoe/repos/app/pull-requests":
+			fmt.Fprint(w, `{"values":[{"id":12,
+				"fromRef":{"id":"refs/heads/feature/csv","displayId":"feature/csv","repository":{"slug":"app","project":{"key":"~JDOE"}}},
+				"toRef":{"id":"refs/heads/main","displayId":"main","repository":{"slug":"app","project":{"key":"PROJ"}}}}],"isLastPage":true}`)
+		default:
+			t.Errorf("request = %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+
+	viper.Set("bitbucket.server", server.URL)
+	viper.Set("bitbucket.username", "tester")
+	viper.Set("bitbucket.token", "token")
+	defer viper.Reset()
+
+	project, repo, prID, _, err := resolvePRArgs(context.Background(), []string{"PROJ/app", "42"})
+	if err != nil || project != "PROJ" || repo != "app" || prID != 42 {
+		t.Fatalf("explicit ID: resolvePRArgs = %q, %q, %d, %v", project, repo, prID, err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("explicit ID should not query the server: %v", requests)
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "feature/csv"},
+		{"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		git := exec.Command("git", args...)
+		git.Dir = dir
+		if out, err := git.CombinedOutput(); err != nil {
+			t.Skipf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	t.Chdir(dir)
+
+	project, repo, prID, _, err = resolvePRArgs(context.Background(), []string{"PROJ/app"})
+	if err != nil || project != "PROJ" || repo != "app" || prID != 7 {
+		t.Fatalf("inferred ID: resolvePRArgs = %q, %q, %d, %v", project, repo, prID, err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("requests = %v, want one branch lookup", requests)
+	}
+
+	// In a fork clone the branch is pushed to the fork, which is where the
+	// pull request is outgoing from.
+	host := strings.TrimPrefix(server.URL, "http://")
+	for _, args := range [][]string{
+		{"remote", "add", "upstream", "http://" + host + "/scm/proj/app.git"},
+		{"remote", "add", "origin", "http://" + host + "/scm/~jdoe/app.git"},
+		{"config", "branch.feature/csv.remote", "origin"},
+		{"config", "branch.feature/csv.merge", "refs/heads/feature/csv"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	project, repo, prID, _, err = resolvePRArgs(context.Background(), nil)
+	if err != nil || project != "PROJ" || repo != "app" || prID != 12 {
+		t.Fatalf("fork branch: resolvePRArgs = %q, %q, %d, %v", project, repo, prID, err)
+	}
+}
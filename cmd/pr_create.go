@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/lroolle/atlas-cli/api"
 	"github.com/spf13/cobra"
 )
 
@@ -15,7 +16,22 @@ var prCreateCmd = &cobra.Command{
 	Long: `Create a new pull request from the current branch.
 
 If no title is provided and --fill is used, the title will be derived from
-the first commit message on the branch.`,
+the first commit message on the branch.
+
+To open a pull request from a fork, name the source repository with
+--repo-from, or prefix --head with it: OWNER:BRANCH is the branch of
+OWNER's personal fork (~OWNER/REPO) of the target repository, and
+PROJECT/REPO:BRANCH a branch of any repository.
+
+The repository's default reviewers are added unless --no-default-reviewers
+is given. --reviewer @GROUP adds the members of a
+bitbucket.reviewer_groups group. --suggest-reviewers ranks the people who
+committed to and last changed the modified files (git log and blame
+against the base branch) and lets you pick from them.`,
+	Example: `  atl pr create --fill
+  atl pr create PROJ/repo --head jdoe:fix-login --title "Fix login"
+  atl pr create --repo-from ~jdoe/repo --head fix-login --fill
+  atl pr create --fill -r @backend --suggest-reviewers`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
@@ -30,11 +46,6 @@ the first commit message on the branch.`,
 			return err
 		}
 
-		client, err := getClient()
-		if err != nil {
-			return err
-		}
-
 		title, _ := cmd.Flags().GetString("title")
 		body, _ := cmd.Flags().GetString("body")
 		base, _ := cmd.Flags().GetString("base")
@@ -42,6 +53,35 @@ the first commit message on the branch.`,
 		reviewers, _ := cmd.Flags().GetStringSlice("reviewer")
 		fill, _ := cmd.Flags().GetBool("fill")
 		web, _ := cmd.Flags().GetBool("web")
+		noDefaultReviewers, _ := cmd.Flags().GetBool("no-default-reviewers")
+		suggest, _ := cmd.Flags().GetBool("suggest-reviewers")
+
+		if title == "" && !fill {
+			return fmt.Errorf("--title is required (or use --fill to derive from commits)")
+		}
+
+		client, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		repoFrom, _ := cmd.Flags().GetString("repo-from")
+
+		fromProject, fromRepo, head, err := parseHeadRef(head, repo)
+		if err != nil {
+			return err
+		}
+		if repoFrom != "" {
+			if fromProject != "" {
+				return fmt.Errorf("--repo-from and a repository in --head are mutually exclusive")
+			}
+			if fromProject, fromRepo, err = parseRepoArg(repoFrom); err != nil {
+				return fmt.Errorf("invalid --repo-from: %w", err)
+			}
+		}
+		if fromProject == "" {
+			fromProject, fromRepo = project, repo
+		}
 
 		if head == "" {
 			head, err = getCurrentGitBranch()
@@ -62,10 +102,57 @@ the first commit message on the branch.`,
 		}
 
 		if title == "" {
-			return fmt.Errorf("--title is required (or use --fill to derive from commits)")
+			return fmt.Errorf("--fill found no commits to derive a title from; use --title")
 		}
 
-		pr, err := client.CreatePullRequest(ctx, project, repo, title, body, head, base, reviewers)
+		// Bitbucket refuses the author as a reviewer; reviewers are
+		// usernames, which may differ from the configured login.
+		self, err := currentBitbucketUser(ctx, client)
+		if err != nil {
+			self = &api.User{Name: client.Username}
+		}
+
+		if len(reviewers) > 0 {
+			if reviewers, err = expandReviewerGroups(reviewers, reviewerGroups()); err != nil {
+				return err
+			}
+			reviewers, err = resolveBitbucketUsers(ctx, client, reviewers)
+			if err != nil {
+				return fmt.Errorf("resolving --reviewer: %w", err)
+			}
+		}
+		reviewers = mergeReviewers(nil, reviewers, *self)
+
+		if !noDefaultReviewers {
+			defaults, err := defaultReviewers(ctx, client, fromProject, fromRepo, project, repo, head, base)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not fetch default reviewers: %v\n", err)
+			} else if len(defaults) > 0 {
+				fmt.Fprintf(os.Stderr, "Adding default reviewers: %s\n", strings.Join(defaults, ", "))
+				reviewers = mergeReviewers(reviewers, defaults, *self)
+			}
+		}
+
+		if suggest {
+			candidates, err := suggestReviewers(localBaseRef(project, repo, base), head)
+			if err != nil {
+				return fmt.Errorf("suggesting reviewers: %w", err)
+			}
+			emails, err := pickSuggestedReviewers(candidates, 10)
+			if err != nil {
+				return err
+			}
+			for _, email := range emails {
+				names, err := resolveBitbucketUsers(ctx, client, []string{email})
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", email, err)
+					continue
+				}
+				reviewers = mergeReviewers(reviewers, names, *self)
+			}
+		}
+
+		pr, err := client.CreatePullRequestFrom(ctx, fromProject, fromRepo, project, repo, title, body, head, base, reviewers)
 		if err != nil {
 			return fmt.Errorf("creating pull request: %w", err)
 		}
@@ -94,6 +181,26 @@ func getCurrentGitBranch() (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// parseHeadRef splits --head into its source repository and branch:
+// BRANCH, OWNER:BRANCH (OWNER's personal fork of repo, "~" optional) or
+// PROJECT/REPO:BRANCH. The project is empty when head names no repository.
+func parseHeadRef(head, repo string) (fromProject, fromRepo, branch string, err error) {
+	owner, branch, found := strings.Cut(head, ":")
+	if !found {
+		return "", "", head, nil
+	}
+	if owner == "" || branch == "" {
+		return "", "", "", fmt.Errorf("invalid --head %q: use BRANCH, OWNER:BRANCH or PROJECT/REPO:BRANCH", head)
+	}
+	if p, r, ok := strings.Cut(owner, "/"); ok {
+		if p == "" || r == "" || strings.Contains(r, "/") {
+			return "", "", "", fmt.Errorf("invalid --head %q: use BRANCH, OWNER:BRANCH or PROJECT/REPO:BRANCH", head)
+		}
+		return p, r, branch, nil
+	}
+	return "~" + strings.TrimPrefix(owner, "~"), repo, branch, nil
+}
+
 func fillFromCommits(base, head string) (title, body string) {
 	out, err := exec.Command("git", "log", "--format=%s", "--reverse", base+".."+head).Output()
 	if err != nil {
@@ -113,6 +220,16 @@ func fillFromCommits(base, head string) (title, body string) {
 	return title, body
 }
 
+// localBaseRef is the local ref for the base branch: its remote-tracking
+// branch when there is one, since the local branch may be stale or missing.
+func localBaseRef(project, repo, base string) string {
+	tracking := checkoutRemote(project, repo) + "/" + base
+	if branchExists(tracking) {
+		return tracking
+	}
+	return base
+}
+
 func getPRURL(baseURL, project, repo string, prID int) string {
 	return fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests/%d", baseURL, project, repo, prID)
 }
@@ -123,8 +240,11 @@ func init() {
 	prCreateCmd.Flags().StringP("title", "t", "", "Title for the pull request")
 	prCreateCmd.Flags().StringP("body", "b", "", "Body/description for the pull request")
 	prCreateCmd.Flags().StringP("base", "B", "", "Base branch (default: repo default branch)")
-	prCreateCmd.Flags().StringP("head", "H", "", "Head branch (default: current branch)")
-	prCreateCmd.Flags().StringSliceP("reviewer", "r", nil, "Request review from these users")
+	prCreateCmd.Flags().StringP("head", "H", "", "Head branch, optionally OWNER: or PROJECT/REPO: for a fork (default: current branch)")
+	prCreateCmd.Flags().String("repo-from", "", "Repository the head branch is in, as PROJECT/REPO (default: the target repository)")
+	prCreateCmd.Flags().StringSliceP("reviewer", "r", nil, "Request review from these users (username, email, name or @group)")
+	prCreateCmd.Flags().Bool("no-default-reviewers", false, "Do not add the repository's default reviewers")
+	prCreateCmd.Flags().Bool("suggest-reviewers", false, "Suggest reviewers from the history of the changed files and pick from them")
 	prCreateCmd.Flags().Bool("fill", false, "Use commit messages to fill title and body")
 	prCreateCmd.Flags().BoolP("web", "w", false, "Open the PR in browser after creation")
 }
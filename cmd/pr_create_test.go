@@ -0,0 +1,48 @@
+package cmd
+
+import "testing"
+
+func TestParseHeadRef(t *testing.T) {
+	tests := []struct {
+		head                          string
+		wantProject, wantRepo, branch string
+	}{
+		{"feature/x", "", "", "feature/x"},
+		{"", "", "", ""},
+		{"jdoe:fix-login", "~jdoe", "app", "fix-login"},
+		{"~jdoe:fix-login", "~jdoe", "app", "fix-login"},
+		{"~jdoe/app-fork:fix", "~jdoe", "app-fork", "fix"},
+		{"OTHER/lib:release/1.0", "OTHER", "lib", "release/1.0"},
+	}
+	for _, tt := range tests {
+		project, repo, branch, err := parseHeadRef(tt.head, "app")
+		if err != nil {
+			t.Errorf("parseHeadRef(%q) returned error: %v", tt.head, err)
+			continue
+		}
+		if project != tt.wantProject || repo != tt.wantRepo || branch != tt.branch {
+			t.Errorf("parseHeadRef(%q) = %q, %q, %q; want %q, %q, %q",
+				tt.head, project, repo, branch, tt.wantProject, tt.wantRepo, tt.branch)
+		}
+	}
+
+	for _, head := range []string{":fix", "jdoe:", "/app:fix", "a/b/c:fix"} {
+		if _, _, _, err := parseHeadRef(head, "app"); err == nil {
+			t.Errorf("parseHeadRef(%q) should fail", head)
+		}
+	}
+}
+
+func TestCloneProtocol(t *testing.T) {
+	tests := map[string]string{
+		"https://git.example.com/scm/proj/app.git":    "http",
+		"ssh://git@git.example.com:7999/proj/app.git": "ssh",
+		"git@git.example.com:proj/app.git":            "ssh",
+		"":                                            "ssh",
+	}
+	for url, want := range tests {
+		if got := cloneProtocol(url); got != want {
+			t.Errorf("cloneProtocol(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
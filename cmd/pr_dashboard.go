@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var prDashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Show the open pull requests involving you across all repositories",
+	Long: `Show every open pull request you are involved in, across all projects
+and repositories, grouped by your role:
+
+  Waiting for your review   your inbox: you are a reviewer and have not
+                            approved yet
+  Created by you
+  Reviewing                 reviewed by you already
+  Participating             you commented or were mentioned
+
+Each pull request shows its review state, head commit builds, open task
+count and when it was last updated; pull requests untouched for
+--stale-days are marked stale.`,
+	Example: `  atl pr dashboard
+  atl pr dashboard --watch --interval 2m
+  atl pr dashboard --json | jq '.[] | select(.stale)'`,
+	Args: cobra.NoArgs,
+	RunE: runPRDashboard,
+}
+
+// dashboardRoles are the dashboard groups in display order. A pull request
+// is listed under the first group it belongs to.
+var dashboardRoles = []struct {
+	role  string
+	title string
+}{
+	{dashboardInbox, "Waiting for your review"},
+	{api.RoleAuthor, "Created by you"},
+	{api.RoleReviewer, "Reviewing"},
+	{api.RoleParticipant, "Participating"},
+}
+
+const dashboardInbox = "INBOX"
+
+// dashboardEntry is one pull request row, also the --json shape.
+type dashboardEntry struct {
+	Role       string          `json:"role"`
+	Repository string          `json:"repository"`
+	ID         int             `json:"id"`
+	Title      string          `json:"title"`
+	Author     string          `json:"author"`
+	Review     string          `json:"review"`
+	Build      *api.BuildStats `json:"build,omitempty"`
+	OpenTasks  int             `json:"openTasks"`
+	Updated    time.Time       `json:"updated"`
+	Stale      bool            `json:"stale"`
+	URL        string          `json:"url"`
+
+	pr api.PullRequest
+}
+
+func runPRDashboard(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	staleDays, _ := cmd.Flags().GetInt("stale-days")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if watch && jsonOutput {
+		return fmt.Errorf("--watch and --json are mutually exclusive")
+	}
+	if watch && interval < minWatchInterval {
+		return fmt.Errorf("--interval must be at least %s", minWatchInterval)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	staleAfter := time.Duration(staleDays) * 24 * time.Hour
+
+	if !watch {
+		entries, err := fetchDashboard(ctx, client, limit, staleAfter, time.Now())
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			if entries == nil {
+				entries = []dashboardEntry{}
+			}
+			return json.NewEncoder(os.Stdout).Encode(entries)
+		}
+		printDashboard(os.Stdout, entries, time.Now())
+		return nil
+	}
+
+	last := ""
+	for {
+		now := time.Now()
+		entries, err := fetchDashboard(ctx, client, limit, staleAfter, now)
+		if err != nil {
+			return err
+		}
+		if snapshot := dashboardSnapshot(entries); snapshot != last {
+			last = snapshot
+			var buf bytes.Buffer
+			printDashboard(&buf, entries, now)
+			fmt.Printf("%s\n%s\n", dim(now.Format("15:04:05")), buf.String())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fetchDashboard collects the inbox and the pull requests of every role,
+// each pull request once, with their builds.
+func fetchDashboard(ctx context.Context, client *api.BitbucketClient, limit int, staleAfter time.Duration, now time.Time) ([]dashboardEntry, error) {
+	var entries []dashboardEntry
+	seen := map[string]bool{}
+	for _, r := range dashboardRoles {
+		var prs []api.PullRequest
+		var err error
+		if r.role == dashboardInbox {
+			prs, err = client.GetInboxPullRequests(ctx, limit)
+		} else {
+			prs, err = client.GetDashboardPullRequests(ctx, r.role, "OPEN", limit)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", strings.ToLower(r.title), err)
+		}
+
+		for _, pr := range prs {
+			key := fmt.Sprintf("%s#%d", prRepository(pr), pr.ID)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			entries = append(entries, newDashboardEntry(client.Client.BaseURL, r.role, pr, staleAfter, now))
+		}
+	}
+
+	prs := make([]api.PullRequest, len(entries))
+	for i, e := range entries {
+		prs[i] = e.pr
+	}
+	if builds := prBuildStats(ctx, client, prs); builds != nil {
+		for i := range entries {
+			if stats, ok := builds[entries[i].pr.FromRef.LatestCommit]; ok {
+				entries[i].Build = &stats
+			}
+		}
+	}
+	return entries, nil
+}
+
+func newDashboardEntry(baseURL, role string, pr api.PullRequest, staleAfter time.Duration, now time.Time) dashboardEntry {
+	project, repo := pr.ToRef.Repository.Project.Key, pr.ToRef.Repository.Slug
+	updated := time.UnixMilli(pr.UpdatedDate)
+	return dashboardEntry{
+		Role:       role,
+		Repository: prRepository(pr),
+		ID:         pr.ID,
+		Title:      pr.Title,
+		Author:     pr.Author.User.Name,
+		Review:     reviewState(pr),
+		OpenTasks:  pr.Properties.OpenTaskCount,
+		Updated:    updated,
+		Stale:      staleAfter > 0 && now.Sub(updated) > staleAfter,
+		URL:        getPRURL(baseURL, project, repo, pr.ID),
+		pr:         pr,
+	}
+}
+
+// prRepository names the repository a pull request targets as PROJECT/repo.
+func prRepository(pr api.PullRequest) string {
+	return pr.ToRef.Repository.Project.Key + "/" + pr.ToRef.Repository.Slug
+}
+
+// reviewState condenses the reviewers' verdicts: NEEDS_WORK if anyone asked
+// for changes, APPROVED once everyone approved, else PENDING (or NONE
+// without reviewers).
+func reviewState(pr api.PullRequest) string {
+	if len(pr.Reviewers) == 0 {
+		return "NONE"
+	}
+	approved := 0
+	for _, r := range pr.Reviewers {
+		if r.Status == "NEEDS_WORK" {
+			return "NEEDS_WORK"
+		}
+		if r.Approved {
+			approved++
+		}
+	}
+	if approved == len(pr.Reviewers) {
+		return "APPROVED"
+	}
+	return "PENDING"
+}
+
+func printDashboard(w io.Writer, entries []dashboardEntry, now time.Time) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No open pull requests involve you")
+		return
+	}
+
+	first := true
+	for _, r := range dashboardRoles {
+		var group []dashboardEntry
+		for _, e := range entries {
+			if e.Role == r.role {
+				group = append(group, e)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(w)
+		}
+		first = false
+
+		fmt.Fprintln(w, bold(fmt.Sprintf("%s (%d)", r.title, len(group))))
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, e := range group {
+			build := "-"
+			if e.Build != nil {
+				build = buildSummary(*e.Build)
+			}
+			fmt.Fprintf(tw, "  %s#%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				e.Repository, e.ID,
+				cmdutil.Truncate(e.Title, cmdutil.TitleTruncateNormal),
+				e.Author,
+				getReviewStatus(e.pr),
+				build,
+				taskCount(e.OpenTasks),
+				staleness(e, now),
+			)
+		}
+		tw.Flush()
+	}
+}
+
+func taskCount(open int) string {
+	switch open {
+	case 0:
+		return "-"
+	case 1:
+		return yellow("1 task")
+	}
+	return yellow(fmt.Sprintf("%d tasks", open))
+}
+
+func staleness(e dashboardEntry, now time.Time) string {
+	age := formatElapsed(now.Sub(e.Updated)) + " ago"
+	if e.Stale {
+		return yellow(age + " (stale)")
+	}
+	return dim(age)
+}
+
+// dashboardSnapshot identifies what --watch redraws for: anything but the
+// passing of time.
+func dashboardSnapshot(entries []dashboardEntry) string {
+	parts := make([]string, len(entries))
+	for i, e := range entries {
+		build := ""
+		if e.Build != nil {
+			build = fmt.Sprintf("%d/%d/%d", e.Build.Successful, e.Build.InProgress, e.Build.Failed)
+		}
+		parts[i] = fmt.Sprintf("%s:%s#%d:%s:%s:%d:%d:%t", e.Role, e.Repository, e.ID, e.Review, build, e.OpenTasks, e.Updated.UnixMilli(), e.Stale)
+	}
+	return strings.Join(parts, ",")
+}
+
+func init() {
+	prCmd.AddCommand(prDashboardCmd)
+	f := prDashboardCmd.Flags()
+	f.Int("limit", 100, "Maximum pull requests per group")
+	f.Int("stale-days", 7, "Mark pull requests not updated for this many days as stale (0: never)")
+	f.Bool("json", false, "Output as JSON")
+	f.Bool("watch", false, "Refresh until interrupted, redrawing on changes")
+	f.Duration("interval", time.Minute, "Refresh interval for --watch (at least 5s)")
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func dashboardPR(t *testing.T, raw string) api.PullRequest {
+	t.Helper()
+	var pr api.PullRequest
+	if err := json.Unmarshal([]byte(raw), &pr); err != nil {
+		t.Fatal(err)
+	}
+	return pr
+}
+
+func TestReviewState(t *testing.T) {
+	tests := map[string]string{
+		`{}`: "NONE",
+		`{"reviewers":[{"approved":true},{"approved":true}]}`:           "APPROVED",
+		`{"reviewers":[{"approved":true},{"status":"UNAPPROVED"}]}`:     "PENDING",
+		`{"reviewers":[{"approved":true},{"status":"NEEDS_WORK"}]}`:     "NEEDS_WORK",
+		`{"reviewers":[{"status":"NEEDS_WORK"},{"status":"APPROVED"}]}`: "NEEDS_WORK",
+	}
+	labels := map[string]string{"NONE": "No reviewers", "APPROVED": "✓ Approved", "PENDING": "pending", "NEEDS_WORK": "Changes requested"}
+	for raw, want := range tests {
+		if got := reviewState(dashboardPR(t, raw)); got != want {
+			t.Errorf("reviewState(%s) = %s, want %s", raw, got, want)
+		}
+		// The table's label and the --json state must agree.
+		if label := getReviewStatus(dashboardPR(t, raw)); !strings.Contains(label, labels[want]) {
+			t.Errorf("getReviewStatus(%s) = %q, want it to say %q", raw, label, labels[want])
+		}
+	}
+}
+
+func TestNewDashboardEntry(t *testing.T) {
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	pr := dashboardPR(t, fmt.Sprintf(`{
+		"id": 142, "title": "Add CSV export", "updatedDate": %d,
+		"author": {"user": {"name": "alice"}},
+		"toRef": {"repository": {"slug": "app", "project": {"key": "TEAM"}}},
+		"properties": {"openTaskCount": 2}
+	}`, now.Add(-10*24*time.Hour).UnixMilli()))
+
+	e := newDashboardEntry("https://git.example.com", api.RoleAuthor, pr, 7*24*time.Hour, now)
+	if e.Repository != "TEAM/app" || e.OpenTasks != 2 || !e.Stale || e.Author != "alice" {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.URL != "https://git.example.com/projects/TEAM/repos/app/pull-requests/142" {
+		t.Errorf("URL = %s", e.URL)
+	}
+	if e := newDashboardEntry("", api.RoleAuthor, pr, 0, now); e.Stale {
+		t.Error("--stale-days 0 should never mark stale")
+	}
+}
+
+func TestPrintDashboardGroupsByRole(t *testing.T) {
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	entries := []dashboardEntry{
+		{Role: api.RoleParticipant, Repository: "OPS/infra", ID: 3, Title: "Bump TLS", Updated: now.Add(-time.Hour)},
+		{Role: dashboardInbox, Repository: "TEAM/app", ID: 142, Title: "Add CSV export", OpenTasks: 2, Updated: now.Add(-50 * time.Hour)},
+	}
+
+	var buf bytes.Buffer
+	printDashboard(&buf, entries, now)
+	out := buf.String()
+
+	inbox := strings.Index(out, "Waiting for your review (1)")
+	participating := strings.Index(out, "Participating (1)")
+	if inbox < 0 || participating < 0 || inbox > participating {
+		t.Fatalf("groups missing or out of order:\n%s", out)
+	}
+	for _, want := range []string{"TEAM/app#142", "2 tasks", "2d 2h ago", "OPS/infra#3", "1h ago"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Created by you") {
+		t.Errorf("empty groups should be omitted:\n%s", out)
+	}
+}
+
+func TestDashboardSnapshot(t *testing.T) {
+	entry := dashboardEntry{Role: api.RoleAuthor, Repository: "TEAM/app", ID: 1, Review: "PENDING"}
+	before := dashboardSnapshot([]dashboardEntry{entry})
+
+	entry.Review = "APPROVED"
+	if dashboardSnapshot([]dashboardEntry{entry}) == before {
+		t.Error("a review change should change the snapshot")
+	}
+}
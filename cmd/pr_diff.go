@@ -0,0 +1,451 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var prDiffCmd = &cobra.Command{
+	Use:   "diff [project/repo] [pr-id]",
+	Short: "View pull request diff",
+	Long: `View a pull request's diff with old and new line numbers, the numbers
+'atl pr comment --line' takes (--side old for the left column). Open
+inline comments are shown under their lines.
+
+When output is not a terminal the plain patch is printed, as with
+--patch, unless one of --name-only, --stat, --file or --side-by-side is
+given.`,
+	Example: `  atl pr diff 142
+  atl pr diff 142 --stat
+  atl pr diff 142 --file src/app.js --side-by-side
+  atl pr diff 142 --patch | git apply`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: runPRDiff,
+}
+
+func runPRDiff(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	project, repo, prID, _, err := resolvePRArgs(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	f := cmd.Flags()
+	patch, _ := f.GetBool("patch")
+	nameOnly, _ := f.GetBool("name-only")
+	stat, _ := f.GetBool("stat")
+	files, _ := f.GetStringSlice("file")
+	sideBySide, _ := f.GetBool("side-by-side")
+	showComments, _ := f.GetBool("comments")
+	contextLines, _ := f.GetInt("context")
+
+	rendering := nameOnly || stat || len(files) > 0 || sideBySide
+	if patch && rendering {
+		return fmt.Errorf("--patch cannot be combined with --name-only, --stat, --file or --side-by-side")
+	}
+	if nameOnly && stat {
+		return fmt.Errorf("--name-only and --stat are mutually exclusive")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if patch || (!rendering && !term.IsTerminal(int(os.Stdout.Fd()))) {
+		diff, err := client.GetPullRequestDiff(ctx, project, repo, prID)
+		if err != nil {
+			return err
+		}
+		fmt.Println(diff)
+		return nil
+	}
+
+	diff, err := client.GetPullRequestDiffJSON(ctx, project, repo, prID, contextLines)
+	if err != nil {
+		return err
+	}
+
+	fileDiffs := diff.Diffs
+	if len(files) > 0 {
+		fileDiffs = nil
+		for _, name := range files {
+			fd := diff.FindFile(name)
+			if fd == nil {
+				return &api.ErrPathNotInDiff{Path: name, Available: diff.Paths()}
+			}
+			fileDiffs = append(fileDiffs, *fd)
+		}
+	}
+
+	switch {
+	case nameOnly:
+		for i := range fileDiffs {
+			fmt.Println(fileDiffs[i].Path())
+		}
+		return nil
+	case stat:
+		printDiffStat(os.Stdout, fileDiffs)
+		return nil
+	}
+
+	r := diffRenderer{w: os.Stdout, sideBySide: sideBySide, comments: showComments, width: terminalWidth()}
+	for i := range fileDiffs {
+		if i > 0 {
+			fmt.Println()
+		}
+		r.file(&fileDiffs[i])
+	}
+	if diff.Truncated {
+		fmt.Println(yellow("\n(diff truncated by the server; use --patch for the full diff)"))
+	}
+	return nil
+}
+
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	return 160
+}
+
+// printDiffStat prints a git-style diffstat.
+func printDiffStat(w io.Writer, files []api.FileDiff) {
+	const barWidth = 40
+
+	nameWidth, maxChanges := 0, 0
+	for i := range files {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(diffFileLabel(&files[i])))
+		added, removed := files[i].Stat()
+		maxChanges = max(maxChanges, added+removed)
+	}
+
+	totalAdded, totalRemoved := 0, 0
+	for i := range files {
+		added, removed := files[i].Stat()
+		totalAdded += added
+		totalRemoved += removed
+
+		plus, minus := added, removed
+		if maxChanges > barWidth {
+			plus = (added*barWidth + maxChanges - 1) / maxChanges
+			minus = (removed*barWidth + maxChanges - 1) / maxChanges
+		}
+		label := diffFileLabel(&files[i])
+		padding := strings.Repeat(" ", nameWidth-utf8.RuneCountInString(label))
+		fmt.Fprintf(w, " %s%s | %d %s%s\n", label, padding, added+removed,
+			green(strings.Repeat("+", plus)), red(strings.Repeat("-", minus)))
+	}
+	fmt.Fprintf(w, " %d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)\n", len(files), totalAdded, totalRemoved)
+}
+
+// diffFileLabel names a file the way git does: "new", "old => new" for
+// moves and copies.
+func diffFileLabel(f *api.FileDiff) string {
+	if src := f.SrcPath(); src != "" {
+		return src + " => " + f.Path()
+	}
+	return f.Path()
+}
+
+// diffRenderer draws the structured diff: unified with an old/new line
+// number gutter, or side by side.
+type diffRenderer struct {
+	w          io.Writer
+	sideBySide bool
+	comments   bool
+	width      int
+}
+
+func (r diffRenderer) file(f *api.FileDiff) {
+	r.fileHeader(f)
+	if f.Binary {
+		return
+	}
+
+	lang := commentSyntax(f.Path())
+	comments := r.commentIndex(f)
+	for _, h := range f.Hunks {
+		r.hunk(h, lang, comments)
+	}
+	if f.Truncated {
+		fmt.Fprintln(r.w, yellow("  (file truncated)"))
+	}
+}
+
+// fileHeader prints the file's name and change counts.
+func (r diffRenderer) fileHeader(f *api.FileDiff) {
+	added, removed := f.Stat()
+	header := diffFileLabel(f)
+	switch {
+	case f.Source == nil:
+		header += " (new)"
+	case f.Destination == nil:
+		header += " (deleted)"
+	}
+	fmt.Fprintf(r.w, "%s  %s %s\n", bold(header), green(fmt.Sprintf("+%d", added)), red(fmt.Sprintf("-%d", removed)))
+
+	if f.Binary {
+		fmt.Fprintln(r.w, dim("  binary file"))
+	}
+}
+
+// commentIndex maps the file's line comments by ID, or is empty when
+// comments are hidden.
+func (r diffRenderer) commentIndex(f *api.FileDiff) map[int]api.Comment {
+	comments := map[int]api.Comment{}
+	if r.comments {
+		for _, c := range f.LineComments {
+			comments[c.ID] = c
+		}
+	}
+	return comments
+}
+
+func (r diffRenderer) hunk(h api.Hunk, lang string, comments map[int]api.Comment) {
+	hunkHeader := fmt.Sprintf("@@ -%d,%d +%d,%d @@ %s", h.SourceLine, h.SourceSpan, h.DestinationLine, h.DestinationSpan, h.Context)
+	fmt.Fprintln(r.w, cyan(strings.TrimRight(hunkHeader, " ")))
+	if r.sideBySide {
+		r.hunkSideBySide(h, lang, comments)
+	} else {
+		r.hunkUnified(h, lang, comments)
+	}
+}
+
+func (r diffRenderer) hunkUnified(h api.Hunk, lang string, comments map[int]api.Comment) {
+	for _, s := range h.Segments {
+		for _, l := range s.Lines {
+			oldNo, newNo := lineNumbers(s.Type, l)
+			fmt.Fprintf(r.w, "%s %s %s %s\n",
+				dim(oldNo), dim(newNo), diffSign(s.Type), highlightDiffLine(expandTabs(l.Line), s.Type, lang))
+			r.lineComments(l, comments, 13)
+		}
+	}
+}
+
+// hunkSideBySide pairs each run of removed lines with the added lines that
+// follow it, so a changed line sits next to its replacement.
+func (r diffRenderer) hunkSideBySide(h api.Hunk, lang string, comments map[int]api.Comment) {
+	// Each half is a 5-digit line number, the sign and the code; " │ "
+	// separates them.
+	column := (r.width - 19) / 2
+	if column < 20 {
+		column = 20
+	}
+
+	for i := 0; i < len(h.Segments); i++ {
+		s := h.Segments[i]
+		var left, right []api.DiffLine
+		switch s.Type {
+		case api.SegmentContext:
+			left, right = s.Lines, s.Lines
+		case api.SegmentRemoved:
+			left = s.Lines
+			if i+1 < len(h.Segments) && h.Segments[i+1].Type == api.SegmentAdded {
+				right = h.Segments[i+1].Lines
+				i++
+			}
+		case api.SegmentAdded:
+			right = s.Lines
+		}
+
+		leftType, rightType := api.SegmentRemoved, api.SegmentAdded
+		if s.Type == api.SegmentContext {
+			leftType, rightType = api.SegmentContext, api.SegmentContext
+		}
+
+		for j := 0; j < max(len(left), len(right)); j++ {
+			leftCell := sideCell(left, j, leftType, lang, column, true)
+			rightCell := sideCell(right, j, rightType, lang, column, false)
+			fmt.Fprintf(r.w, "%s %s %s\n", leftCell, dim("│"), rightCell)
+
+			if j < len(left) && leftType != api.SegmentContext {
+				r.lineComments(left[j], comments, 6)
+			}
+			if j < len(right) {
+				r.lineComments(right[j], comments, column+11)
+			}
+		}
+	}
+}
+
+// sideCell renders one half of a side-by-side row, padded to width when
+// more follows on the row.
+func sideCell(lines []api.DiffLine, i int, segmentType, lang string, width int, pad bool) string {
+	if i >= len(lines) {
+		if pad {
+			return strings.Repeat(" ", width+8)
+		}
+		return ""
+	}
+	l := lines[i]
+	n := l.Destination
+	if segmentType == api.SegmentRemoved {
+		n = l.Source
+	}
+	text := truncateRunes(expandTabs(l.Line), width)
+	cell := fmt.Sprintf("%s %s %s", dim(fmt.Sprintf("%5d", n)), diffSign(segmentType), highlightDiffLine(text, segmentType, lang))
+	if pad {
+		cell += strings.Repeat(" ", width-utf8.RuneCountInString(text))
+	}
+	return cell
+}
+
+// lineComments prints the open comments anchored to a line, one line each.
+func (r diffRenderer) lineComments(l api.DiffLine, comments map[int]api.Comment, indent int) {
+	for _, id := range l.CommentIDs {
+		c, ok := comments[id]
+		if !ok {
+			continue
+		}
+		text := fmt.Sprintf("💬 #%d %s: %s", c.ID, c.Author.Name, firstLine(c.Text))
+		if n := len(c.Comments); n > 0 {
+			text += fmt.Sprintf(" (+%d replies)", n)
+		}
+		fmt.Fprintf(r.w, "%s%s\n", strings.Repeat(" ", indent), yellow(truncateRunes(text, max(r.width-indent, 20))))
+	}
+}
+
+// lineNumbers returns the old and new gutter cells; a side the line does
+// not exist on is blank.
+func lineNumbers(segmentType string, l api.DiffLine) (oldNo, newNo string) {
+	oldNo, newNo = fmt.Sprintf("%5d", l.Source), fmt.Sprintf("%5d", l.Destination)
+	switch segmentType {
+	case api.SegmentAdded:
+		oldNo = "     "
+	case api.SegmentRemoved:
+		newNo = "     "
+	}
+	return oldNo, newNo
+}
+
+func diffSign(segmentType string) string {
+	switch segmentType {
+	case api.SegmentAdded:
+		return green("+")
+	case api.SegmentRemoved:
+		return red("-")
+	}
+	return " "
+}
+
+// highlightDiffLine colors a line green or red by change and, for
+// languages commentSyntax knows, picks out strings and comments on top.
+func highlightDiffLine(line, segmentType, lang string) string {
+	var base string
+	switch segmentType {
+	case api.SegmentAdded:
+		base = "32"
+	case api.SegmentRemoved:
+		base = "31"
+	}
+	switch {
+	case lang != "":
+		return highlightCode(line, lang, base)
+	case base != "":
+		return ansi(base, line)
+	}
+	return line
+}
+
+// commentSyntax returns the line comment marker of a file's language, or
+// "" when the language is unknown.
+func commentSyntax(file string) string {
+	base := path.Base(file)
+	switch base {
+	case "Makefile", "Dockerfile":
+		return "#"
+	case "Jenkinsfile":
+		return "//"
+	}
+	switch strings.ToLower(path.Ext(base)) {
+	case ".go", ".js", ".jsx", ".ts", ".tsx", ".java", ".kt", ".scala", ".groovy", ".gradle",
+		".c", ".h", ".cc", ".cpp", ".hpp", ".cs", ".rs", ".swift", ".php", ".dart", ".proto":
+		return "//"
+	case ".py", ".rb", ".sh", ".bash", ".zsh", ".yaml", ".yml", ".toml", ".pl", ".r", ".tf", ".properties", ".conf", ".cfg", ".ini":
+		return "#"
+	case ".sql", ".lua", ".hs":
+		return "--"
+	}
+	return ""
+}
+
+// highlightCode colors string literals and the trailing line comment. On a
+// changed line base is its ANSI color, which the whole line keeps: strings
+// are then bold and the comment dim in that color.
+func highlightCode(line, commentMarker, base string) string {
+	if !colorEnabled {
+		return line
+	}
+	str, comment := "36", "2"
+	if base != "" {
+		str, comment = "1;"+base, "2;"+base
+	}
+
+	var b strings.Builder
+	plain := 0 // start of the text not yet written
+	flush := func(to int) {
+		if base != "" {
+			b.WriteString(ansi(base, line[plain:to]))
+		} else {
+			b.WriteString(line[plain:to])
+		}
+	}
+	for i := 0; i < len(line); {
+		if strings.HasPrefix(line[i:], commentMarker) {
+			flush(i)
+			b.WriteString(ansi(comment, line[i:]))
+			return b.String()
+		}
+		quote := line[i]
+		if quote != '"' && quote != '\'' && quote != '`' {
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(line) && line[end] != quote {
+			if line[end] == '\\' && quote != '`' {
+				end++
+			}
+			end++
+		}
+		end = min(end+1, len(line))
+		flush(i)
+		b.WriteString(ansi(str, line[i:end]))
+		i, plain = end, end
+	}
+	flush(len(line))
+	return b.String()
+}
+
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
+
+func init() {
+	prCmd.AddCommand(prDiffCmd)
+	f := prDiffCmd.Flags()
+	f.Bool("patch", false, "Print the plain unified patch")
+	f.Bool("name-only", false, "Only list the changed files")
+	f.Bool("stat", false, "Show a diffstat")
+	f.StringSlice("file", nil, "Only show these files")
+	f.BoolP("side-by-side", "s", false, "Show old and new side by side")
+	f.Bool("comments", true, "Show inline comments under their lines")
+	f.Int("context", 0, "Lines of context around changes (default: server setting)")
+}
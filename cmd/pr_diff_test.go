@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func testFileDiff() *api.FileDiff {
+	return &api.FileDiff{
+		Source:      &api.Path{ToString: "src/app.go"},
+		Destination: &api.Path{ToString: "src/app.go"},
+		Hunks: []api.Hunk{{
+			SourceLine: 10, SourceSpan: 3, DestinationLine: 10, DestinationSpan: 3,
+			Segments: []api.Segment{
+				{Type: api.SegmentContext, Lines: []api.DiffLine{{Source: 10, Destination: 10, Line: "func main() {"}}},
+				{Type: api.SegmentRemoved, Lines: []api.DiffLine{{Source: 11, Destination: 11, Line: "\tfmt.Println(\"hi\")"}}},
+				{Type: api.SegmentAdded, Lines: []api.DiffLine{{Source: 12, Destination: 11, Line: "\tlog.Println(\"hi\")", CommentIDs: []int{7}}}},
+				{Type: api.SegmentContext, Lines: []api.DiffLine{{Source: 12, Destination: 12, Line: "}"}}},
+			},
+		}},
+		LineComments: []api.Comment{{ID: 7, Text: "use slog\nplease", Author: api.User{Name: "jdoe"}}},
+	}
+}
+
+func TestDiffRendererUnified(t *testing.T) {
+	var buf bytes.Buffer
+	r := diffRenderer{w: &buf, comments: true, width: 120}
+	r.file(testFileDiff())
+
+	want := `src/app.go  +1 -1
+@@ -10,3 +10,3 @@
+   10    10   func main() {
+   11       -     fmt.Println("hi")
+         11 +     log.Println("hi")
+             💬 #7 jdoe: use slog
+   12    12   }
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unified:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffRendererSideBySide(t *testing.T) {
+	var buf bytes.Buffer
+	r := diffRenderer{w: &buf, sideBySide: true, width: 61} // 21-column halves
+	r.file(testFileDiff())
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	want := []string{
+		"   10   func main() {         │    10   func main() {",
+		"   11 -     fmt.Println(\"hi\") │    11 +     log.Println(\"hi\")",
+		"   12   }                     │    12   }",
+	}
+	if len(lines) != 5 {
+		t.Fatalf("side by side:\n%s", buf.String())
+	}
+	for i, w := range want {
+		if lines[i+2] != w {
+			t.Errorf("row %d = %q, want %q", i, lines[i+2], w)
+		}
+	}
+}
+
+func TestPrintDiffStat(t *testing.T) {
+	moved := api.FileDiff{
+		Source:      &api.Path{ToString: "old.txt"},
+		Destination: &api.Path{ToString: "new.txt"},
+	}
+	var buf bytes.Buffer
+	printDiffStat(&buf, []api.FileDiff{*testFileDiff(), moved})
+
+	want := " src/app.go         | 2 +-\n" +
+		" old.txt => new.txt | 0 \n" +
+		" 2 file(s) changed, 1 insertion(s)(+), 1 deletion(s)(-)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("diffstat:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestHighlightCode(t *testing.T) {
+	defer func(enabled bool) { colorEnabled = enabled }(colorEnabled)
+	colorEnabled = true
+
+	got := highlightCode(`x := "a // b" // note`, "//", "")
+	want := "x := " + cyan(`"a // b"`) + " " + dim("// note")
+	if got != want {
+		t.Errorf("highlightCode = %q, want %q", got, want)
+	}
+
+	got = highlightCode(`s = 'it\'s'`, "#", "")
+	if want := "s = " + cyan(`'it\'s'`); got != want {
+		t.Errorf("highlightCode escaped = %q, want %q", got, want)
+	}
+
+	// Added and removed lines keep their color under the highlighting.
+	got = highlightDiffLine(`x := "a" // note`, api.SegmentAdded, "//")
+	want = green("x := ") + ansi("1;32", `"a"`) + green(" ") + ansi("2;32", "// note")
+	if got != want {
+		t.Errorf("highlightDiffLine added = %q, want %q", got, want)
+	}
+	if got, want := highlightDiffLine("return nil", api.SegmentRemoved, "//"), red("return nil"); got != want {
+		t.Errorf("highlightDiffLine removed = %q, want %q", got, want)
+	}
+}
+
+func TestCommentSyntax(t *testing.T) {
+	tests := map[string]string{
+		"main.go":         "//",
+		"deploy/app.yaml": "#",
+		"Makefile":        "#",
+		"db/001_init.sql": "--",
+		"README.md":       "",
+		"assets/logo.png": "",
+		"ci/Jenkinsfile":  "//",
+	}
+	for file, want := range tests {
+		if got := commentSyntax(file); got != want {
+			t.Errorf("commentSyntax(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
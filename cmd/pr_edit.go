@@ -2,14 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/lroolle/atlas-cli/api"
 	"github.com/spf13/cobra"
 )
 
 var prEditCmd = &cobra.Command{
-	Use:   "edit [project/repo] <pr-id>",
+	Use:   "edit [project/repo] [pr-id]",
 	Short: "Edit pull request properties",
 	Long: `Edit a pull request's title, description, base branch, or reviewers.
 
@@ -17,31 +16,12 @@ Examples:
   atl pr edit 123 --title "New title"
   atl pr edit 123 --add-reviewer alice --add-reviewer bob
   atl pr edit 123 --remove-reviewer charlie`,
-	Args: cobra.RangeArgs(1, 2),
+	Args: cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -62,6 +42,13 @@ Examples:
 
 		hasUpdates := title != "" || body != "" || base != ""
 
+		if addReviewers, err = resolveBitbucketUsers(ctx, client, addReviewers); err != nil {
+			return fmt.Errorf("resolving --add-reviewer: %w", err)
+		}
+		if removeReviewers, err = resolveBitbucketUsers(ctx, client, removeReviewers); err != nil {
+			return fmt.Errorf("resolving --remove-reviewer: %w", err)
+		}
+
 		for _, r := range removeReviewers {
 			if err := client.RemoveReviewer(ctx, project, repo, prID, r); err != nil {
 				fmt.Printf("Warning: could not remove reviewer %s: %v\n", r, err)
@@ -106,6 +93,6 @@ func init() {
 	prEditCmd.Flags().StringP("title", "t", "", "New title")
 	prEditCmd.Flags().StringP("body", "b", "", "New description")
 	prEditCmd.Flags().StringP("base", "B", "", "Change target branch")
-	prEditCmd.Flags().StringSlice("add-reviewer", nil, "Add reviewer (can be repeated)")
-	prEditCmd.Flags().StringSlice("remove-reviewer", nil, "Remove reviewer (can be repeated)")
+	prEditCmd.Flags().StringSlice("add-reviewer", nil, "Add reviewer by username, email or name (can be repeated)")
+	prEditCmd.Flags().StringSlice("remove-reviewer", nil, "Remove reviewer by username, email or name (can be repeated)")
 }
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strconv"
 	"text/tabwriter"
 	"time"
 
@@ -13,33 +12,14 @@ import (
 )
 
 var prCommitsCmd = &cobra.Command{
-	Use:   "commits [project/repo] <pr-id>",
+	Use:   "commits [project/repo] [pr-id]",
 	Short: "List commits in a pull request",
-	Args:  cobra.RangeArgs(1, 2),
+	Args:  cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -82,34 +62,15 @@ var prCommitsCmd = &cobra.Command{
 }
 
 var prFilesCmd = &cobra.Command{
-	Use:     "files [project/repo] <pr-id>",
+	Use:     "files [project/repo] [pr-id]",
 	Short:   "List files changed in a pull request",
 	Aliases: []string{"changes"},
-	Args:    cobra.RangeArgs(1, 2),
+	Args:    cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -148,33 +109,14 @@ var prFilesCmd = &cobra.Command{
 }
 
 var prActivityCmd = &cobra.Command{
-	Use:   "activity [project/repo] <pr-id>",
+	Use:   "activity [project/repo] [pr-id]",
 	Short: "Show activity on a pull request",
-	Args:  cobra.RangeArgs(1, 2),
+	Args:  cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -217,33 +159,14 @@ var prActivityCmd = &cobra.Command{
 }
 
 var prCanMergeCmd = &cobra.Command{
-	Use:   "can-merge [project/repo] <pr-id>",
+	Use:   "can-merge [project/repo] [pr-id]",
 	Short: "Check if a pull request can be merged",
-	Args:  cobra.RangeArgs(1, 2),
+	Args:  cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -2,40 +2,20 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/spf13/cobra"
 )
 
 var prDeclineCmd = &cobra.Command{
-	Use:     "decline [project/repo] <pr-id>",
+	Use:     "decline [project/repo] [pr-id]",
 	Short:   "Decline a pull request",
 	Aliases: []string{"close"},
-	Args:    cobra.RangeArgs(1, 2),
+	Args:    cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -69,33 +49,14 @@ var prDeclineCmd = &cobra.Command{
 }
 
 var prReopenCmd = &cobra.Command{
-	Use:   "reopen [project/repo] <pr-id>",
+	Use:   "reopen [project/repo] [pr-id]",
 	Short: "Reopen a declined pull request",
-	Args:  cobra.RangeArgs(1, 2),
+	Args:  cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -122,34 +83,15 @@ var prReopenCmd = &cobra.Command{
 }
 
 var prRebaseCmd = &cobra.Command{
-	Use:   "rebase [project/repo] <pr-id>",
+	Use:   "rebase [project/repo] [pr-id]",
 	Short: "Rebase a pull request (server-side)",
 	Long:  `Rebase the pull request's source branch on top of the target branch using server-side rebase.`,
-	Args:  cobra.RangeArgs(1, 2),
+	Args:  cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -1,95 +1,272 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/lroolle/atlas-cli/api"
 	"github.com/spf13/cobra"
 )
 
 var prMergeCmd = &cobra.Command{
-	Use:   "merge [project/repo] <pr-id>",
+	Use:   "merge [project/repo] [pr-id]",
 	Short: "Merge a pull request",
-	Args:  cobra.RangeArgs(1, 2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := cmd.Context()
-
-		var project, repo string
-		var prIDStr string
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
+	Long: `Merge a pull request.
+
+--strategy picks how the merge is done and must be enabled in the
+repository's pull request settings:
+  merge-commit   always create a merge commit (no-ff)
+  squash         squash into one commit
+  fast-forward   fast-forward when possible
+  rebase-merge   rebase, then create a merge commit
+Bitbucket strategy IDs (ff-only, squash-ff-only, rebase-ff-only, ...)
+are accepted too. Without --strategy the repository default is used.
+
+--auto waits until the pull request can be merged: no vetoes (required
+approvals, builds, tasks) and no conflicts, polling with backoff up to
+--timeout. A conflict or a closed pull request stops the wait.`,
+	Example: `  atl pr merge 142
+  atl pr merge 142 --strategy squash -m "Add CSV export (#142)"
+  atl pr merge 142 --auto --delete-branch
+  atl pr merge MYPROJ/myrepo 142 --message-file msg.txt`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: runPRMerge,
+}
+
+func runPRMerge(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
 
-		prID, err := strconv.Atoi(prIDStr)
+	project, repo, prID, _, err := resolvePRArgs(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	var opts api.MergeOptions
+	opts.Message, err = mergeMessage(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if strategy, _ := cmd.Flags().GetString("strategy"); strategy != "" {
+		config, err := client.GetMergeConfig(ctx, project, repo)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return fmt.Errorf("fetching merge strategies: %w", err)
 		}
-
-		client, err := getClient()
+		picked, err := pickMergeStrategy(config, strategy)
 		if err != nil {
 			return err
 		}
+		opts.StrategyID = picked.ID
+	}
 
-		// Get PR details first
-		pr, err := client.GetPullRequest(ctx, project, repo, prID)
-		if err != nil {
+	force, _ := cmd.Flags().GetBool("force")
+	auto, _ := cmd.Flags().GetBool("auto")
+	deleteBranch, _ := cmd.Flags().GetBool("delete-branch")
+
+	var pr *api.PullRequest
+	if auto {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		pr, err = waitForMergeable(ctx, client, project, repo, prID, !force, timeout)
+	} else {
+		pr, err = client.GetPullRequest(ctx, project, repo, prID)
+		if err == nil {
+			err = checkMergeable(pr, !force)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := client.MergePullRequest(ctx, project, repo, prID, pr.Version, opts); err != nil {
+		return fmt.Errorf("merging PR: %w", err)
+	}
+
+	if deleteBranch {
+		sourceProject := pr.FromRef.Repository.Project.Key
+		sourceRepo := pr.FromRef.Repository.Slug
+		sourceBranch := pr.FromRef.ID
+		if err := client.DeleteBranch(ctx, sourceProject, sourceRepo, sourceBranch); err != nil {
+			return fmt.Errorf("merged PR #%d but failed to delete branch %s/%s %s: %w", prID, sourceProject, sourceRepo, sourceBranch, err)
+		}
+	}
+
+	fmt.Printf("✓ Merged PR #%d: %s\n", prID, pr.Title)
+	return nil
+}
+
+func mergeMessage(cmd *cobra.Command) (string, error) {
+	message, _ := cmd.Flags().GetString("message")
+	file, _ := cmd.Flags().GetString("message-file")
+	if message != "" && file != "" {
+		return "", errors.New("--message and --message-file cannot be combined")
+	}
+	if file == "" {
+		return message, nil
+	}
+	data, err := readFileOrStdin(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// checkMergeable applies the checks made before every merge: the pull
+// request is open and, unless forced, approved by someone.
+func checkMergeable(pr *api.PullRequest, requireApproval bool) error {
+	if pr.State != "OPEN" {
+		return fmt.Errorf("PR #%d is not open (state: %s)", pr.ID, pr.State)
+	}
+	if requireApproval && !hasApproval(pr) {
+		return fmt.Errorf("PR #%d has no approvals. Use --force to merge anyway", pr.ID)
+	}
+	return nil
+}
+
+func hasApproval(pr *api.PullRequest) bool {
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.Approved {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeStrategyAliases maps --strategy names to Bitbucket strategy IDs,
+// most specific first.
+var mergeStrategyAliases = map[string][]string{
+	"merge-commit": {"no-ff"},
+	"squash":       {"squash", "squash-ff-only"},
+	"fast-forward": {"ff", "ff-only"},
+	"rebase-merge": {"rebase-no-ff"},
+}
+
+// pickMergeStrategy returns the enabled strategy that name stands for,
+// either an alias or a Bitbucket strategy ID.
+func pickMergeStrategy(config *api.MergeConfig, name string) (*api.MergeStrategy, error) {
+	ids, ok := mergeStrategyAliases[strings.ToLower(name)]
+	if !ok {
+		ids = []string{strings.ToLower(name)}
+	}
+
+	var enabled []string
+	for _, id := range ids {
+		for i, s := range config.Strategies {
+			if s.ID == id && s.Enabled {
+				return &config.Strategies[i], nil
+			}
+		}
+	}
+	for _, s := range config.Strategies {
+		if s.Enabled {
+			enabled = append(enabled, s.ID)
+		}
+	}
+	return nil, fmt.Errorf("merge strategy %q is not enabled for this repository (enabled: %s)", name, strings.Join(enabled, ", "))
+}
+
+const (
+	autoMergeFirstPoll = 10 * time.Second
+	autoMergeMaxPoll   = 2 * time.Minute
+)
+
+// nextPollDelay doubles the wait between polls up to autoMergeMaxPoll.
+func nextPollDelay(d time.Duration) time.Duration {
+	if d *= 2; d > autoMergeMaxPoll {
+		return autoMergeMaxPoll
+	}
+	return d
+}
+
+// waitForMergeable polls until the pull request passes checkMergeable and
+// Bitbucket reports no vetoes, returning its latest version.
+func waitForMergeable(ctx context.Context, client *api.BitbucketClient, project, repo string, prID int, requireApproval bool, timeout time.Duration) (*api.PullRequest, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	delay := autoMergeFirstPoll
+	lastReason := ""
+	// timedOut reports the deadline, which can expire mid-request too.
+	timedOut := func(err error) error {
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return err
 		}
+		if lastReason == "" {
+			return fmt.Errorf("timed out waiting for PR #%d", prID)
+		}
+		return fmt.Errorf("timed out waiting for PR #%d: %s", prID, lastReason)
+	}
 
+	for {
+		pr, err := client.GetPullRequest(ctx, project, repo, prID)
+		if err != nil {
+			return nil, timedOut(err)
+		}
 		if pr.State != "OPEN" {
-			return fmt.Errorf("PR #%d is not open (state: %s)", prID, pr.State)
-		}
-
-		force, _ := cmd.Flags().GetBool("force")
-		deleteBranch, _ := cmd.Flags().GetBool("delete-branch")
-		if !force {
-			// Check for approvals
-			hasApproval := false
-			for _, reviewer := range pr.Reviewers {
-				if reviewer.Approved {
-					hasApproval = true
-					break
-				}
-			}
+			return nil, fmt.Errorf("PR #%d is no longer open (state: %s)", prID, pr.State)
+		}
 
-			if !hasApproval {
-				return fmt.Errorf("PR #%d has no approvals. Use --force to merge anyway", prID)
-			}
+		result, err := client.CanMerge(ctx, project, repo, prID)
+		if err != nil {
+			return nil, timedOut(fmt.Errorf("checking merge status: %w", err))
+		}
+		if result.Conflicted {
+			return nil, fmt.Errorf("PR #%d has conflicts; resolve them before merging", prID)
 		}
 
-		// Merge the PR
-		if err := client.MergePullRequest(ctx, project, repo, prID, pr.Version); err != nil {
-			return fmt.Errorf("merging PR: %w", err)
+		reason := mergeBlocker(pr, result, requireApproval)
+		if reason == "" {
+			return pr, nil
+		}
+		if reason != lastReason {
+			fmt.Fprintf(os.Stderr, "Waiting for PR #%d: %s\n", prID, reason)
+			lastReason = reason
 		}
 
-		if deleteBranch {
-			sourceProject := pr.FromRef.Repository.Project.Key
-			sourceRepo := pr.FromRef.Repository.Slug
-			sourceBranch := pr.FromRef.ID
-			if err := client.DeleteBranch(ctx, sourceProject, sourceRepo, sourceBranch); err != nil {
-				return fmt.Errorf("merged PR #%d but failed to delete branch %s/%s %s: %w", prID, sourceProject, sourceRepo, sourceBranch, err)
-			}
+		select {
+		case <-ctx.Done():
+			return nil, timedOut(ctx.Err())
+		case <-time.After(delay):
 		}
+		delay = nextPollDelay(delay)
+	}
+}
 
-		fmt.Printf("✓ Merged PR #%d: %s\n", prID, pr.Title)
-		return nil
-	},
+// mergeBlocker describes what keeps a pull request from merging, or
+// returns "" when nothing does.
+func mergeBlocker(pr *api.PullRequest, result *api.MergeResult, requireApproval bool) string {
+	var reasons []string
+	for _, v := range result.Vetoes {
+		reasons = append(reasons, v.SummaryMessage)
+	}
+	if len(reasons) == 0 && !result.CanMerge {
+		reasons = append(reasons, "Bitbucket reports it cannot be merged")
+	}
+	if requireApproval && !hasApproval(pr) {
+		reasons = append(reasons, "no approvals yet")
+	}
+	return strings.Join(reasons, "; ")
 }
 
 func init() {
 	prCmd.AddCommand(prMergeCmd)
-	prMergeCmd.Flags().Bool("force", false, "Merge even without approvals")
-	prMergeCmd.Flags().Bool("delete-branch", false, "Delete the source branch after merge")
+	f := prMergeCmd.Flags()
+	f.Bool("force", false, "Merge even without approvals")
+	f.Bool("delete-branch", false, "Delete the source branch after merge")
+	f.String("strategy", "", "Merge strategy: merge-commit, squash, fast-forward, rebase-merge")
+	f.StringP("message", "m", "", "Merge commit message")
+	f.StringP("message-file", "F", "", "Read the merge commit message from file ('-' for stdin)")
+	f.Bool("auto", false, "Wait until the PR can be merged, then merge")
+	f.Duration("timeout", 30*time.Minute, "How long --auto waits")
 }
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestPickMergeStrategy(t *testing.T) {
+	config := &api.MergeConfig{Strategies: []api.MergeStrategy{
+		{ID: "no-ff", Enabled: true},
+		{ID: "ff", Enabled: false},
+		{ID: "ff-only", Enabled: true},
+		{ID: "squash", Enabled: true},
+		{ID: "rebase-no-ff", Enabled: false},
+	}}
+
+	tests := []struct {
+		name, want string
+	}{
+		{"merge-commit", "no-ff"},
+		{"squash", "squash"},
+		{"Fast-Forward", "ff-only"},
+		{"ff-only", "ff-only"},
+	}
+	for _, tt := range tests {
+		got, err := pickMergeStrategy(config, tt.name)
+		if err != nil {
+			t.Errorf("pickMergeStrategy(%q) returned error: %v", tt.name, err)
+			continue
+		}
+		if got.ID != tt.want {
+			t.Errorf("pickMergeStrategy(%q) = %s, want %s", tt.name, got.ID, tt.want)
+		}
+	}
+
+	_, err := pickMergeStrategy(config, "rebase-merge")
+	if err == nil || !strings.Contains(err.Error(), "enabled: no-ff, ff-only, squash") {
+		t.Errorf("disabled strategy error = %v", err)
+	}
+}
+
+func TestMergeBlocker(t *testing.T) {
+	approved := &api.PullRequest{}
+	if err := json.Unmarshal([]byte(`{"reviewers":[{"approved":true}]}`), approved); err != nil {
+		t.Fatal(err)
+	}
+	unapproved := &api.PullRequest{}
+
+	tests := []struct {
+		name     string
+		pr       *api.PullRequest
+		result   api.MergeResult
+		approval bool
+		want     string
+	}{
+		{"ready", approved, api.MergeResult{CanMerge: true}, true, ""},
+		{"vetoes", approved, api.MergeResult{Vetoes: []api.Veto{{SummaryMessage: "Build failed"}, {SummaryMessage: "Open tasks"}}}, true, "Build failed; Open tasks"},
+		{"needs approval", unapproved, api.MergeResult{CanMerge: true}, true, "no approvals yet"},
+		{"forced", unapproved, api.MergeResult{CanMerge: true}, false, ""},
+		{"no reason given", approved, api.MergeResult{}, true, "Bitbucket reports it cannot be merged"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeBlocker(tt.pr, &tt.result, tt.approval); got != tt.want {
+				t.Errorf("mergeBlocker = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextPollDelay(t *testing.T) {
+	d := autoMergeFirstPoll
+	var got []time.Duration
+	for i := 0; i < 6; i++ {
+		got = append(got, d)
+		d = nextPollDelay(d)
+	}
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 2 * time.Minute, 2 * time.Minute}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delays = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestWaitForMergeableTimesOutMidRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/merge") {
+			// The merge check outlasts the deadline.
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"id":7,"state":"OPEN"}`))
+	}))
+	defer server.Close()
+
+	client := api.NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+
+	_, err := waitForMergeable(context.Background(), client, "PROJ", "app", 7, false, 50*time.Millisecond)
+	if err == nil || err.Error() != "timed out waiting for PR #7" {
+		t.Errorf("waitForMergeable error = %v, want the timeout message", err)
+	}
+}
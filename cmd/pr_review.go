@@ -1,14 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"strconv"
 
+	"github.com/lroolle/atlas-cli/api"
 	"github.com/spf13/cobra"
 )
 
 var prReviewCmd = &cobra.Command{
-	Use:   "review [project/repo] <pr-id>",
+	Use:   "review [project/repo] [pr-id]",
 	Short: "Add a review to a pull request",
 	Long: `Add a review to a pull request with optional comment.
 
@@ -17,36 +18,36 @@ Review actions:
   --request-changes (-r)   Request changes (sets NEEDS_WORK status)
   --comment (-c)           Add a comment without changing approval status
   --discard-pending        Drop your unpublished (pending) review comments
+  --interactive (-i)       Walk the diff hunk by hunk, drafting comments
+  --publish                Publish your pending review comments
 
 If no action is specified, --comment is assumed when --body is provided.
 
-Inline comments are posted with 'atl pr comment --file --line'. Pending
-comments drafted with '--pending' are published from the pull request page.`,
-	Args: cobra.RangeArgs(1, 2),
+--publish completes a review drafted with 'pr comment --pending' (or
+--batch --pending): the pending comments become visible, -m adds a summary
+comment and --approve or --request-changes sets your status in the same
+step. It needs Bitbucket 8.0 or later.
+
+--interactive shows each hunk with its line numbers and takes short
+commands: 'c 42' drafts a comment on line 42 of the new file ('c -42' on
+the old file), 'b 42' a blocker, 'f' a file comment and 'r 331' a reply.
+Comments are drafted as pending; at the end choose to publish them
+(approving, requesting changes or neither), discard them or keep them.
+
+Inline comments are posted with 'atl pr comment --file --line'.`,
+	Example: `  atl pr review 142 --approve
+  atl pr review 142 -r -b "see inline comments"
+  atl pr review 142 --interactive
+
+  # Draft a batch review, then publish it with a verdict
+  atl pr comment 142 --batch findings.json --pending
+  atl pr review 142 --publish --request-changes -m "Two blockers, rest is nits"`,
+	Args: cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -59,6 +60,39 @@ comments drafted with '--pending' are published from the pull request page.`,
 		comment, _ := cmd.Flags().GetBool("comment")
 		body, _ := cmd.Flags().GetString("body")
 		discardPending, _ := cmd.Flags().GetBool("discard-pending")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		publish, _ := cmd.Flags().GetBool("publish")
+		message, _ := cmd.Flags().GetString("message")
+
+		if message != "" && !publish {
+			return fmt.Errorf("--message is the --publish summary; use --body for a comment")
+		}
+		if publish {
+			if comment || body != "" || discardPending || interactive {
+				return fmt.Errorf("--publish only combines with --approve, --request-changes and --message")
+			}
+			if approve && requestChanges {
+				return fmt.Errorf("only one of --approve or --request-changes can be specified")
+			}
+			pr, err := client.GetPullRequest(ctx, project, repo, prID)
+			if err != nil {
+				return fmt.Errorf("fetching PR: %w", err)
+			}
+			status := ""
+			if approve {
+				status = "APPROVED"
+			} else if requestChanges {
+				status = "NEEDS_WORK"
+			}
+			return publishReview(ctx, client, project, repo, pr, status, message)
+		}
+
+		if interactive {
+			if approve || requestChanges || comment || body != "" || discardPending {
+				return fmt.Errorf("--interactive cannot be combined with other review actions")
+			}
+			return runInteractiveReview(ctx, client, project, repo, prID)
+		}
 
 		if discardPending {
 			if approve || requestChanges || comment || body != "" {
@@ -129,34 +163,45 @@ comments drafted with '--pending' are published from the pull request page.`,
 	},
 }
 
+// publishReview completes the user's pending review on pr, optionally with a
+// summary comment and a participant status.
+func publishReview(ctx context.Context, client *api.BitbucketClient, project, repo string, pr *api.PullRequest, status, summary string) error {
+	pending, err := client.GetPendingReview(ctx, project, repo, pr.ID, 1000)
+	if err != nil {
+		return fmt.Errorf("fetching pending review: %w", err)
+	}
+	if len(pending) == 0 && status == "" && summary == "" {
+		return fmt.Errorf("no pending comments to publish on PR #%d", pr.ID)
+	}
+
+	review := api.ReviewCompletion{
+		CommentText:        summary,
+		ParticipantStatus:  status,
+		LastReviewedCommit: pr.FromRef.LatestCommit,
+	}
+	if err := client.CompleteReview(ctx, project, repo, pr.ID, review); err != nil {
+		return fmt.Errorf("publishing review: %w", err)
+	}
+
+	fmt.Printf("✓ Published %d pending comment(s) on PR #%d: %s\n", len(pending), pr.ID, pr.Title)
+	switch status {
+	case "APPROVED":
+		fmt.Println("✓ Approved")
+	case "NEEDS_WORK":
+		fmt.Println("✗ Requested changes")
+	}
+	return nil
+}
+
 var prApproveCmd = &cobra.Command{
-	Use:   "approve [project/repo] <pr-id>",
+	Use:   "approve [project/repo] [pr-id]",
 	Short: "Approve a pull request",
-	Args:  cobra.RangeArgs(1, 2),
+	Args:  cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -179,33 +224,14 @@ var prApproveCmd = &cobra.Command{
 }
 
 var prUnapproveCmd = &cobra.Command{
-	Use:   "unapprove [project/repo] <pr-id>",
+	Use:   "unapprove [project/repo] [pr-id]",
 	Short: "Remove approval from a pull request",
-	Args:  cobra.RangeArgs(1, 2),
+	Args:  cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var project, repo string
-		var prIDStr string
-
-		if len(args) == 2 {
-			var err error
-			project, repo, err = parseRepoArg(args[0])
-			if err != nil {
-				return err
-			}
-			prIDStr = args[1]
-		} else {
-			var err error
-			project, repo, err = parseRepoArg("")
-			if err != nil {
-				return fmt.Errorf("PR ID required: %w", err)
-			}
-			prIDStr = args[0]
-		}
-
-		prID, err := strconv.Atoi(prIDStr)
+		project, repo, prID, _, err := resolvePRArgs(ctx, args)
 		if err != nil {
-			return fmt.Errorf("invalid PR ID: %v", err)
+			return err
 		}
 
 		client, err := getClient()
@@ -237,4 +263,7 @@ func init() {
 	prReviewCmd.Flags().BoolP("comment", "c", false, "Add comment only")
 	prReviewCmd.Flags().StringP("body", "b", "", "Comment text")
 	prReviewCmd.Flags().Bool("discard-pending", false, "Discard your unpublished review comments")
+	prReviewCmd.Flags().BoolP("interactive", "i", false, "Review the diff hunk by hunk")
+	prReviewCmd.Flags().Bool("publish", false, "Publish your pending review comments")
+	prReviewCmd.Flags().StringP("message", "m", "", "Summary comment for --publish")
 }
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+// reviewStep is what the reviewer asked for at the hunk prompt.
+type reviewStep int
+
+const (
+	stepNext reviewStep = iota
+	stepSkipFile
+	stepQuit
+	stepHelp
+	stepComment
+	stepBlocker
+	stepFileComment
+	stepReply
+)
+
+type reviewCommand struct {
+	step      reviewStep
+	line      int
+	side      api.DiffSide
+	commentID int
+}
+
+const reviewHelp = `  c LINE     draft a comment on LINE of the new file (c -LINE: old file)
+  b LINE     draft a blocker (task) on LINE
+  f          draft a comment on the whole file
+  r ID       reply to comment ID
+  n, Enter   next hunk
+  s          skip the rest of this file
+  q          stop and finish the review
+  ?          this help`
+
+// parseReviewCommand reads one hunk prompt answer. Line numbers are as
+// pr diff shows them; a leading "-" selects the old file, like the
+// removed-line sign.
+func parseReviewCommand(input string) (reviewCommand, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return reviewCommand{step: stepNext}, nil
+	}
+
+	verb, args := strings.ToLower(fields[0]), fields[1:]
+	simple := map[string]reviewStep{"n": stepNext, "s": stepSkipFile, "q": stepQuit, "?": stepHelp, "h": stepHelp, "f": stepFileComment}
+	if step, ok := simple[verb]; ok {
+		if len(args) > 0 {
+			return reviewCommand{}, fmt.Errorf("%q takes no arguments", verb)
+		}
+		return reviewCommand{step: step}, nil
+	}
+
+	switch verb {
+	case "c", "b":
+		if len(args) != 1 {
+			return reviewCommand{}, fmt.Errorf("%q takes a line number (? for help)", verb)
+		}
+		cmd := reviewCommand{step: stepComment, side: api.SideNew}
+		if verb == "b" {
+			cmd.step = stepBlocker
+		}
+		line := args[0]
+		if strings.HasPrefix(line, "-") {
+			cmd.side, line = api.SideOld, line[1:]
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(line, "+"))
+		if err != nil || n <= 0 {
+			return reviewCommand{}, fmt.Errorf("invalid line %q", args[0])
+		}
+		cmd.line = n
+		return cmd, nil
+	case "r":
+		if len(args) != 1 {
+			return reviewCommand{}, errors.New(`"r" takes a comment ID (? for help)`)
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || id <= 0 {
+			return reviewCommand{}, fmt.Errorf("invalid comment ID %q", args[0])
+		}
+		return reviewCommand{step: stepReply, commentID: id}, nil
+	}
+	return reviewCommand{}, fmt.Errorf("unknown command %q (? for help)", input)
+}
+
+// reviewVerdict is how the reviewer finishes an interactive review.
+type reviewVerdict int
+
+const (
+	verdictKeep reviewVerdict = iota
+	verdictApprove
+	verdictNeedsWork
+	verdictDiscard
+	verdictPublish
+)
+
+func parseReviewVerdict(input string) (reviewVerdict, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "a", "approve":
+		return verdictApprove, nil
+	case "w", "needs-work", "needs work":
+		return verdictNeedsWork, nil
+	case "d", "discard":
+		return verdictDiscard, nil
+	case "p", "publish":
+		return verdictPublish, nil
+	case "", "k", "keep":
+		return verdictKeep, nil
+	}
+	return verdictKeep, fmt.Errorf("unknown choice %q", input)
+}
+
+// interactiveReview walks a pull request's diff hunk by hunk, drafting the
+// reviewer's comments as pending comments.
+type interactiveReview struct {
+	client  *api.BitbucketClient
+	project string
+	repo    string
+	prID    int
+
+	diff     *api.Diff
+	renderer diffRenderer
+	drafted  int
+}
+
+func runInteractiveReview(ctx context.Context, client *api.BitbucketClient, project, repo string, prID int) error {
+	if !isInteractive() {
+		return errors.New("--interactive needs a terminal")
+	}
+
+	pr, err := client.GetPullRequest(ctx, project, repo, prID)
+	if err != nil {
+		return fmt.Errorf("fetching PR: %w", err)
+	}
+	// The same context as 'pr comment' so the lines shown are the lines
+	// that can be anchored.
+	diff, err := client.GetPullRequestDiffJSON(ctx, project, repo, prID, anchorContextLines)
+	if err != nil {
+		return fmt.Errorf("fetching diff: %w", err)
+	}
+
+	review := &interactiveReview{
+		client:   client,
+		project:  project,
+		repo:     repo,
+		prID:     prID,
+		diff:     diff,
+		renderer: diffRenderer{w: os.Stdout, comments: true, width: terminalWidth()},
+	}
+
+	fmt.Printf("Reviewing PR #%d: %s (%d file(s), ? for help)\n", prID, pr.Title, len(diff.Diffs))
+	if err := review.walk(ctx); err != nil {
+		return err
+	}
+	return review.finish(ctx, pr)
+}
+
+func (r *interactiveReview) walk(ctx context.Context) error {
+	for i := range r.diff.Diffs {
+		f := &r.diff.Diffs[i]
+		fmt.Println()
+		fmt.Print(dim(fmt.Sprintf("[%d/%d] ", i+1, len(r.diff.Diffs))))
+		r.renderer.fileHeader(f)
+
+		// Binary files and pure renames have no hunks but can still take a
+		// file comment.
+		hunks := f.Hunks
+		if len(hunks) == 0 {
+			hunks = []api.Hunk{{}}
+		}
+
+		lang := commentSyntax(f.Path())
+		comments := r.renderer.commentIndex(f)
+	hunks:
+		for j, h := range hunks {
+			if len(h.Segments) > 0 {
+				r.renderer.hunk(h, lang, comments)
+			}
+			for {
+				input, err := promptLine(fmt.Sprintf("hunk %d/%d [c,b,f,r,n,s,q,?]: ", j+1, len(hunks)))
+				if err != nil {
+					return err
+				}
+				cmd, err := parseReviewCommand(input)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					continue
+				}
+
+				switch cmd.step {
+				case stepNext:
+					continue hunks
+				case stepSkipFile:
+					break hunks
+				case stepQuit:
+					return nil
+				case stepHelp:
+					fmt.Fprintln(os.Stderr, reviewHelp)
+				default:
+					if err := r.draft(ctx, f, cmd); err != nil {
+						fmt.Fprintln(os.Stderr, red("✗ "+err.Error()))
+					}
+				}
+			}
+		}
+	}
+	if r.diff.Truncated {
+		fmt.Println(yellow("\n(diff truncated by the server; later files were not shown)"))
+	}
+	return nil
+}
+
+// draft prompts for the comment text and posts it as a pending comment.
+// An empty text cancels.
+func (r *interactiveReview) draft(ctx context.Context, f *api.FileDiff, cmd reviewCommand) error {
+	spec := commentSpec{Pending: true, Blocker: cmd.step == stepBlocker}
+
+	var anchor *api.CommentAnchor
+	var err error
+	switch cmd.step {
+	case stepReply:
+		spec.ReplyTo = cmd.commentID
+	case stepFileComment:
+		spec.File = f.Path()
+		anchor, err = r.diff.ResolveFileAnchor(spec.File)
+	default:
+		spec.File, spec.Line, spec.Side = f.Path(), cmd.line, string(cmd.side)
+		anchor, err = r.diff.ResolveLineAnchor(spec.File, cmd.line, cmd.side)
+	}
+	if err != nil {
+		return err
+	}
+
+	spec.Body, err = promptLine(fmt.Sprintf("%s: ", describeTarget(spec, anchor)))
+	if err != nil {
+		return err
+	}
+	if spec.Body == "" {
+		fmt.Fprintln(os.Stderr, dim("(cancelled)"))
+		return nil
+	}
+
+	comment, err := r.client.CreatePullRequestComment(ctx, r.project, r.repo, r.prID, spec.request(anchor))
+	if err != nil {
+		return err
+	}
+	r.drafted++
+	fmt.Printf("%s drafted comment %d\n", green("✓"), comment.ID)
+	return nil
+}
+
+func (r *interactiveReview) finish(ctx context.Context, pr *api.PullRequest) error {
+	fmt.Printf("\n%d comment(s) drafted.\n", r.drafted)
+	var verdict reviewVerdict
+	for {
+		input, err := promptLine("Finish review: [a]pprove, needs [w]ork, [p]ublish only, [d]iscard drafts, [k]eep drafts: ")
+		if err != nil {
+			return err
+		}
+		if verdict, err = parseReviewVerdict(input); err == nil {
+			break
+		}
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	var status string
+	switch verdict {
+	case verdictKeep:
+		if r.drafted > 0 {
+			fmt.Printf("Kept the drafts; publish them with 'atl pr review %d --publish'\n", r.prID)
+		}
+		return nil
+	case verdictDiscard:
+		if err := r.client.DiscardPendingReview(ctx, r.project, r.repo, r.prID); err != nil {
+			return fmt.Errorf("discarding pending review: %w", err)
+		}
+		fmt.Printf("Discarded pending review comments on PR #%d\n", r.prID)
+		return nil
+	case verdictApprove:
+		status = "APPROVED"
+	case verdictNeedsWork:
+		status = "NEEDS_WORK"
+	}
+
+	summary, err := promptLine("Summary comment (optional): ")
+	if err != nil {
+		return err
+	}
+	return r.complete(ctx, pr, status, summary)
+}
+
+// complete publishes the drafts with the verdict. Without drafts only the
+// participant status is set.
+func (r *interactiveReview) complete(ctx context.Context, pr *api.PullRequest, status, summary string) error {
+	if r.drafted == 0 && status != "" {
+		return r.setStatus(ctx, pr, status, summary)
+	}
+	err := publishReview(ctx, r.client, r.project, r.repo, pr, status, summary)
+	if reviewsUnsupported(err) {
+		// Before Bitbucket 8.0 comments are published as they are made and
+		// there is no review to complete.
+		return r.setStatus(ctx, pr, status, summary)
+	}
+	return err
+}
+
+// setStatus approves or asks for work through the participant status, which
+// unlike completing a review works on every Bitbucket version, after
+// posting the summary as a plain comment.
+func (r *interactiveReview) setStatus(ctx context.Context, pr *api.PullRequest, status, summary string) error {
+	if summary != "" {
+		if _, err := r.client.AddPullRequestComment(ctx, r.project, r.repo, r.prID, summary); err != nil {
+			return fmt.Errorf("posting summary: %w", err)
+		}
+	}
+	if status == "" {
+		return nil
+	}
+	if err := r.client.SetReviewerStatus(ctx, r.project, r.repo, r.prID, status); err != nil {
+		return fmt.Errorf("setting review status: %w", err)
+	}
+	switch status {
+	case "APPROVED":
+		fmt.Printf("✓ Approved PR #%d: %s\n", pr.ID, pr.Title)
+	case "NEEDS_WORK":
+		fmt.Printf("✗ Requested changes on PR #%d: %s\n", pr.ID, pr.Title)
+	}
+	return nil
+}
+
+// reviewsUnsupported reports whether err is a server without the review
+// endpoints, which arrived in Bitbucket 8.0.
+func reviewsUnsupported(err error) bool {
+	var unexpected *api.ErrUnexpectedResponse
+	return errors.As(err, &unexpected) &&
+		(unexpected.StatusCode == http.StatusNotFound || unexpected.StatusCode == http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestParseReviewCommand(t *testing.T) {
+	tests := []struct {
+		input string
+		want  reviewCommand
+	}{
+		{"", reviewCommand{step: stepNext}},
+		{"  n ", reviewCommand{step: stepNext}},
+		{"s", reviewCommand{step: stepSkipFile}},
+		{"Q", reviewCommand{step: stepQuit}},
+		{"?", reviewCommand{step: stepHelp}},
+		{"f", reviewCommand{step: stepFileComment}},
+		{"c 42", reviewCommand{step: stepComment, line: 42, side: api.SideNew}},
+		{"c +42", reviewCommand{step: stepComment, line: 42, side: api.SideNew}},
+		{"c -17", reviewCommand{step: stepComment, line: 17, side: api.SideOld}},
+		{"b 8", reviewCommand{step: stepBlocker, line: 8, side: api.SideNew}},
+		{"r 331", reviewCommand{step: stepReply, commentID: 331}},
+		{"r #331", reviewCommand{step: stepReply, commentID: 331}},
+	}
+	for _, tt := range tests {
+		got, err := parseReviewCommand(tt.input)
+		if err != nil {
+			t.Errorf("parseReviewCommand(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseReviewCommand(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"c", "c x", "c 0", "b -", "r", "r abc", "n 3", "x", "c 1 2"} {
+		if _, err := parseReviewCommand(input); err == nil {
+			t.Errorf("parseReviewCommand(%q) should fail", input)
+		}
+	}
+}
+
+func TestParseReviewVerdict(t *testing.T) {
+	tests := map[string]reviewVerdict{
+		"a":          verdictApprove,
+		"Approve":    verdictApprove,
+		"w":          verdictNeedsWork,
+		"needs-work": verdictNeedsWork,
+		"d":          verdictDiscard,
+		"p":          verdictPublish,
+		"":           verdictKeep,
+		"k":          verdictKeep,
+	}
+	for input, want := range tests {
+		got, err := parseReviewVerdict(input)
+		if err != nil || got != want {
+			t.Errorf("parseReviewVerdict(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := parseReviewVerdict("maybe"); err == nil {
+		t.Error("unknown verdict should fail")
+	}
+}
+
+func TestInteractiveReviewCompleteBefore8(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		switch {
+		case r.URL.Path == "/rest/api/1.0/projects/PROJ/repos/app/pull-requests/7/review":
+			// Bitbucket 7.x has no review endpoint.
+			http.NotFound(w, r)
+		case r.Method == http.MethodPost:
+			w.Write([]byte(`{"id":1}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewBitbucketClient(server.URL, "tester", "token")
+	client.HTTPClient = server.Client()
+	pr := &api.PullRequest{ID: 7, Title: "Add CSV export"}
+
+	for _, drafted := range []int{0, 2} {
+		requests = nil
+		review := &interactiveReview{client: client, project: "PROJ", repo: "app", prID: 7, drafted: drafted}
+		if err := review.complete(context.Background(), pr, "APPROVED", "LGTM"); err != nil {
+			t.Fatalf("drafted %d: complete = %v", drafted, err)
+		}
+		last := requests[len(requests)-2:]
+		if last[0] != `POST /rest/api/1.0/projects/PROJ/repos/app/pull-requests/7/comments {"text":"LGTM"}` ||
+			last[1] != `PUT /rest/api/1.0/projects/PROJ/repos/app/pull-requests/7/participants/tester {"status":"APPROVED"}` {
+			t.Errorf("drafted %d: requests = %q, want the summary comment then the participant status", drafted, requests)
+		}
+		if drafted == 0 && len(requests) != 2 {
+			t.Errorf("without drafts the review endpoint should not be used: %q", requests)
+		}
+	}
+}
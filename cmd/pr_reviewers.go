@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/spf13/viper"
+)
+
+// maxBlameFiles caps the changed files blamed for --suggest-reviewers;
+// blame is slow and the first files are usually representative.
+const maxBlameFiles = 30
+
+// expandReviewerGroups replaces @GROUP entries with the members of the
+// bitbucket.reviewer_groups group, keeping the first occurrence of each
+// reviewer. Entries that look like emails are left alone.
+func expandReviewerGroups(values []string, groups map[string][]string) ([]string, error) {
+	var expanded []string
+	add := func(name string) {
+		if !slices.ContainsFunc(expanded, func(e string) bool { return strings.EqualFold(e, name) }) {
+			expanded = append(expanded, name)
+		}
+	}
+
+	for _, v := range values {
+		group, isGroup := strings.CutPrefix(v, "@")
+		if !isGroup || strings.Contains(group, "@") {
+			add(v)
+			continue
+		}
+		members, ok := groups[strings.ToLower(group)]
+		if !ok {
+			return nil, fmt.Errorf("unknown reviewer group %q (set bitbucket.reviewer_groups.%s)", v, group)
+		}
+		for _, m := range members {
+			add(m)
+		}
+	}
+	return expanded, nil
+}
+
+// reviewerGroups reads bitbucket.reviewer_groups. Viper lower-cases the
+// group names.
+func reviewerGroups() map[string][]string {
+	return viper.GetStringMapStringSlice("bitbucket.reviewer_groups")
+}
+
+// defaultReviewers asks the default reviewers plugin who must review a pull
+// request from fromProject/fromRepo's head branch into project/repo's base.
+func defaultReviewers(ctx context.Context, client *api.BitbucketClient, fromProject, fromRepo, project, repo, head, base string) ([]string, error) {
+	target, err := client.GetRepository(ctx, project, repo)
+	if err != nil {
+		return nil, err
+	}
+	source := target
+	if !strings.EqualFold(fromProject, project) || !strings.EqualFold(fromRepo, repo) {
+		if source, err = client.GetRepository(ctx, fromProject, fromRepo); err != nil {
+			return nil, err
+		}
+	}
+
+	users, err := client.GetDefaultReviewers(ctx, project, repo, source.ID, target.ID, "refs/heads/"+head, "refs/heads/"+base)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(users))
+	for i, u := range users {
+		names[i] = u.Name
+	}
+	return names, nil
+}
+
+// mergeReviewers appends extra to reviewers, skipping duplicates and the
+// pull request's author, whom Bitbucket refuses as a reviewer.
+func mergeReviewers(reviewers, extra []string, author api.User) []string {
+	for _, name := range extra {
+		if strings.EqualFold(name, author.Name) || (author.Slug != "" && strings.EqualFold(name, author.Slug)) || slices.ContainsFunc(reviewers, func(r string) bool { return strings.EqualFold(r, name) }) {
+			continue
+		}
+		reviewers = append(reviewers, name)
+	}
+	return reviewers
+}
+
+// reviewerCandidate is someone who worked on the files a change touches.
+type reviewerCandidate struct {
+	Email   string
+	Name    string
+	Commits int
+	Lines   int
+}
+
+// suggestReviewers ranks the authors of the changed files' history: commits
+// touching them on baseRef, then lines they last changed (git blame).
+func suggestReviewers(baseRef, head string) ([]reviewerCandidate, error) {
+	out, err := exec.Command("git", "diff", "--name-only", "--diff-filter=MDR", baseRef+"..."+head).Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing changed files: %w", err)
+	}
+	paths := strings.Fields(string(out))
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	candidates := map[string]*reviewerCandidate{}
+	candidate := func(email, name string) *reviewerCandidate {
+		key := strings.ToLower(email)
+		c, ok := candidates[key]
+		if !ok {
+			c = &reviewerCandidate{Email: email, Name: name}
+			candidates[key] = c
+		}
+		return c
+	}
+
+	logArgs := append([]string{"log", "--no-merges", "-n", "200", "--format=%aE\t%aN", baseRef, "--"}, paths...)
+	if out, err := exec.Command("git", logArgs...).Output(); err == nil {
+		for email, n := range countLogAuthors(string(out)) {
+			candidate(email, n.name).Commits += n.count
+		}
+	}
+
+	for _, path := range paths[:min(len(paths), maxBlameFiles)] {
+		out, err := exec.Command("git", "blame", "--line-porcelain", baseRef, "--", path).Output()
+		if err != nil {
+			continue
+		}
+		for email, n := range countBlameAuthors(string(out)) {
+			candidate(email, n.name).Lines += n.count
+		}
+	}
+
+	list := make([]reviewerCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		list = append(list, *c)
+	}
+	return rankCandidates(list, gitUserEmail()), nil
+}
+
+type authorCount struct {
+	name  string
+	count int
+}
+
+// countLogAuthors counts commits per author email in `git log
+// --format=%aE\t%aN` output.
+func countLogAuthors(out string) map[string]authorCount {
+	counts := map[string]authorCount{}
+	for _, line := range strings.Split(out, "\n") {
+		email, name, ok := strings.Cut(line, "\t")
+		if !ok || email == "" {
+			continue
+		}
+		counts[email] = authorCount{name: name, count: counts[email].count + 1}
+	}
+	return counts
+}
+
+// countBlameAuthors counts lines per author email in `git blame
+// --line-porcelain` output.
+func countBlameAuthors(out string) map[string]authorCount {
+	counts := map[string]authorCount{}
+	var name string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "author "):
+			name = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-mail "):
+			email := strings.Trim(strings.TrimPrefix(line, "author-mail "), "<>")
+			if email == "" || email == "not.committed.yet" {
+				continue
+			}
+			counts[email] = authorCount{name: name, count: counts[email].count + 1}
+		}
+	}
+	return counts
+}
+
+// rankCandidates orders candidates by commits, then blamed lines, leaving
+// out self (the change's author).
+func rankCandidates(candidates []reviewerCandidate, self string) []reviewerCandidate {
+	ranked := slices.DeleteFunc(candidates, func(c reviewerCandidate) bool {
+		return self != "" && strings.EqualFold(c.Email, self)
+	})
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Commits != ranked[j].Commits {
+			return ranked[i].Commits > ranked[j].Commits
+		}
+		if ranked[i].Lines != ranked[j].Lines {
+			return ranked[i].Lines > ranked[j].Lines
+		}
+		return ranked[i].Email < ranked[j].Email
+	})
+	return ranked
+}
+
+func gitUserEmail() string {
+	out, err := exec.Command("git", "config", "user.email").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// parseSelection reads a comma- or space-separated list of 1-based indexes
+// into a list of n entries.
+func parseSelection(input string, n int) ([]int, error) {
+	var picked []int
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		i, err := strconv.Atoi(field)
+		if err != nil || i < 1 || i > n {
+			return nil, fmt.Errorf("invalid choice %q: pick numbers from 1 to %d", field, n)
+		}
+		if !slices.Contains(picked, i-1) {
+			picked = append(picked, i-1)
+		}
+	}
+	return picked, nil
+}
+
+// pickSuggestedReviewers shows the top candidates and returns the emails of
+// the ones the user picks. Without a terminal it only lists them.
+func pickSuggestedReviewers(candidates []reviewerCandidate, limit int) ([]string, error) {
+	if len(candidates) == 0 {
+		fmt.Fprintln(os.Stderr, "No reviewer suggestions: the changed files have no history")
+		return nil, nil
+	}
+	candidates = candidates[:min(len(candidates), limit)]
+
+	fmt.Fprintln(os.Stderr, bold("Suggested reviewers"))
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	for i, c := range candidates {
+		fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", i+1, c.Name, dim(c.Email), dim(fmt.Sprintf("%d commits, %d lines", c.Commits, c.Lines)))
+	}
+	w.Flush()
+
+	if !isInteractive() {
+		return nil, nil
+	}
+	for {
+		input, err := promptLine("Add reviewers (e.g. 1,3; Enter for none): ")
+		if err != nil {
+			return nil, err
+		}
+		picked, err := parseSelection(input, len(candidates))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		emails := make([]string, len(picked))
+		for i, p := range picked {
+			emails[i] = candidates[p].Email
+		}
+		return emails, nil
+	}
+}
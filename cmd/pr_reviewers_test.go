@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestExpandReviewerGroups(t *testing.T) {
+	groups := map[string][]string{
+		"backend": {"alice", "bob"},
+		"leads":   {"bob", "carol"},
+	}
+
+	got, err := expandReviewerGroups([]string{"dave", "@Backend", "@leads", "Alice", "erin@example.com"}, groups)
+	if err != nil {
+		t.Fatalf("expandReviewerGroups returned error: %v", err)
+	}
+	want := []string{"dave", "alice", "bob", "carol", "erin@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandReviewerGroups = %v, want %v", got, want)
+	}
+
+	if _, err := expandReviewerGroups([]string{"@nobody"}, groups); err == nil {
+		t.Error("unknown group should fail")
+	}
+}
+
+func TestMergeReviewers(t *testing.T) {
+	got := mergeReviewers([]string{"alice"}, []string{"Alice", "me", "jme", "bob", "bob"}, api.User{Name: "ME", Slug: "jme"})
+	want := []string{"alice", "bob"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeReviewers = %v, want %v", got, want)
+	}
+}
+
+func TestCountBlameAuthors(t *testing.T) {
+	out := `4e3f1a 1 1 2
+author Alice
+author-mail <alice@example.com>
+author-time 1700000000
+filename main.go
+	package main
+4e3f1a 2 2
+author Alice
+author-mail <alice@example.com>
+filename main.go
+	
+9b7c2d 3 3 1
+author Bob
+author-mail <bob@example.com>
+filename main.go
+	func main() {}
+0000000 4 4 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+filename main.go
+	// wip
+`
+	got := countBlameAuthors(out)
+	want := map[string]authorCount{
+		"alice@example.com": {name: "Alice", count: 2},
+		"bob@example.com":   {name: "Bob", count: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("countBlameAuthors = %v, want %v", got, want)
+	}
+}
+
+func TestCountLogAuthors(t *testing.T) {
+	got := countLogAuthors("alice@example.com\tAlice\nbob@example.com\tBob\nalice@example.com\tAlice\n")
+	if got["alice@example.com"].count != 2 || got["bob@example.com"].count != 1 || len(got) != 2 {
+		t.Errorf("countLogAuthors = %v", got)
+	}
+}
+
+func TestRankCandidates(t *testing.T) {
+	candidates := []reviewerCandidate{
+		{Email: "carol@example.com", Commits: 1, Lines: 300},
+		{Email: "me@example.com", Commits: 9, Lines: 900},
+		{Email: "alice@example.com", Commits: 4, Lines: 10},
+		{Email: "bob@example.com", Commits: 4, Lines: 50},
+	}
+	ranked := rankCandidates(candidates, "ME@example.com")
+	var got []string
+	for _, c := range ranked {
+		got = append(got, c.Email)
+	}
+	want := []string{"bob@example.com", "alice@example.com", "carol@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rankCandidates = %v, want %v", got, want)
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	got, err := parseSelection("1, 3 3", 3)
+	if err != nil || !reflect.DeepEqual(got, []int{0, 2}) {
+		t.Errorf("parseSelection = %v, %v; want [0 2]", got, err)
+	}
+	if got, err := parseSelection("", 3); err != nil || len(got) != 0 {
+		t.Errorf("empty selection = %v, %v", got, err)
+	}
+	for _, input := range []string{"0", "4", "x"} {
+		if _, err := parseSelection(input, 3); err == nil {
+			t.Errorf("parseSelection(%q) should fail", input)
+		}
+	}
+}
@@ -13,7 +13,8 @@ import (
 var prStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show status of relevant pull requests",
-	Long:  `Show status of pull requests relevant to you (created by you, requesting your review, etc.)`,
+	Long: `Show status of pull requests relevant to you (created by you, requesting your review, etc.)
+in the current repository. 'atl pr dashboard' covers all repositories.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		client, err := getClient()
@@ -23,7 +24,7 @@ var prStatusCmd = &cobra.Command{
 
 		project, repo, err := parseRepoArg("")
 		if err != nil {
-			fmt.Printf("No default repository configured: %v\n", err)
+			fmt.Printf("Could not determine the repository: %v\n", err)
 			fmt.Println("Use 'atl pr list PROJECT/REPO' instead.")
 			return nil
 		}
@@ -51,12 +52,15 @@ var prStatusCmd = &cobra.Command{
 			}
 		}
 
+		builds := prBuildStats(ctx, client, append(append([]api.PullRequest(nil), createdByMe...), requestingReview...))
+
 		if len(createdByMe) > 0 {
 			fmt.Println("Created by you")
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 			for _, pr := range createdByMe {
 				reviewStatus := getReviewStatus(pr)
-				fmt.Fprintf(w, "  #%d\t%s\t%s\n", pr.ID, cmdutil.Truncate(pr.Title, cmdutil.TitleTruncateNormal), reviewStatus)
+				fmt.Fprintf(w, "  #%d\t%s\t%s\t%s\n", pr.ID, cmdutil.Truncate(pr.Title, cmdutil.TitleTruncateNormal), reviewStatus,
+					buildSummary(builds[pr.FromRef.LatestCommit]))
 			}
 			w.Flush()
 			fmt.Println()
@@ -66,7 +70,8 @@ var prStatusCmd = &cobra.Command{
 			fmt.Println("Requesting your review")
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 			for _, pr := range requestingReview {
-				fmt.Fprintf(w, "  #%d\t%s\t%s\n", pr.ID, cmdutil.Truncate(pr.Title, cmdutil.TitleTruncateNormal), pr.Author.User.Name)
+				fmt.Fprintf(w, "  #%d\t%s\t%s\t%s\n", pr.ID, cmdutil.Truncate(pr.Title, cmdutil.TitleTruncateNormal), pr.Author.User.Name,
+					buildSummary(builds[pr.FromRef.LatestCommit]))
 			}
 			w.Flush()
 		}
@@ -79,34 +84,28 @@ var prStatusCmd = &cobra.Command{
 	},
 }
 
+// getReviewStatus describes reviewState(pr) with reviewer counts.
 func getReviewStatus(pr api.PullRequest) string {
 	approved := 0
-	needsWork := 0
-	pending := 0
-
 	for _, reviewer := range pr.Reviewers {
 		if reviewer.Approved {
 			approved++
-		} else if reviewer.Status == "NEEDS_WORK" {
-			needsWork++
-		} else {
-			pending++
 		}
 	}
+	pending := len(pr.Reviewers) - approved
 
-	if needsWork > 0 {
+	switch reviewState(pr) {
+	case "NONE":
+		return "No reviewers"
+	case "NEEDS_WORK":
 		return "❌ Changes requested"
-	}
-	if approved > 0 && pending == 0 {
+	case "APPROVED":
 		return fmt.Sprintf("✓ Approved by %d", approved)
 	}
 	if approved > 0 {
 		return fmt.Sprintf("◐ %d approved, %d pending", approved, pending)
 	}
-	if pending > 0 {
-		return fmt.Sprintf("○ %d pending review", pending)
-	}
-	return "No reviewers"
+	return fmt.Sprintf("○ %d pending review", pending)
 }
 
 func init() {
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var prTasksCmd = &cobra.Command{
+	Use:   "tasks [project/repo] [pr-id]",
+	Short: "List the tasks (blocker comments) of a pull request",
+	Long: `List the tasks of a pull request: comments posted with
+'atl pr comment --blocker'. Open tasks block the merge.
+
+Resolve and reopen them with 'atl pr comment --resolve ID' and
+'--reopen ID'.`,
+	Example: `  atl pr tasks 142
+  atl pr tasks 142 --state open
+  atl pr comment 142 --resolve 331`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: runPRTasks,
+}
+
+func runPRTasks(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	project, repo, prID, _, err := resolvePRArgs(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	state, _ := cmd.Flags().GetString("state")
+	limit, _ := cmd.Flags().GetInt("limit")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	state = strings.ToUpper(state)
+	if err := validateEnum("state", state, api.CommentStateOpen, api.CommentStateResolved, "ALL"); err != nil {
+		return err
+	}
+	if state == "ALL" {
+		state = ""
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	tasks, err := client.GetBlockerComments(ctx, project, repo, prID, state, limit)
+	if err != nil {
+		return fmt.Errorf("fetching tasks: %w", err)
+	}
+	sortTasks(tasks)
+
+	if jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(tasks)
+	}
+	if len(tasks) == 0 {
+		fmt.Println("No tasks found")
+		return nil
+	}
+
+	open := 0
+	for _, t := range tasks {
+		if !isResolved(t) {
+			open++
+		}
+	}
+	fmt.Printf("%d open, %d resolved\n\n", open, len(tasks)-open)
+	if len(tasks) == limit {
+		fmt.Fprintf(os.Stderr, "Warning: stopped at --limit %d tasks; the counts may be incomplete\n\n", limit)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tLOCATION\tAUTHOR\tTASK")
+	for _, t := range tasks {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			t.ID,
+			taskStateLabel(t),
+			valueOr(t.Anchor.Location(), "-"),
+			t.Author.Name,
+			cmdutil.Truncate(firstLine(t.Text), cmdutil.TitleTruncateNormal),
+		)
+	}
+	return w.Flush()
+}
+
+// sortTasks lists open tasks before resolved ones, oldest first.
+func sortTasks(tasks []api.Comment) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if ri, rj := isResolved(tasks[i]), isResolved(tasks[j]); ri != rj {
+			return !ri
+		}
+		return tasks[i].CreatedDate < tasks[j].CreatedDate
+	})
+}
+
+func taskStateLabel(t api.Comment) string {
+	if isResolved(t) {
+		return green("✓ resolved")
+	}
+	return yellow("○ open")
+}
+
+func init() {
+	prCmd.AddCommand(prTasksCmd)
+	prTasksCmd.Flags().String("state", "all", "Filter by state: open, resolved, all")
+	prTasksCmd.Flags().Int("limit", cmdutil.DefaultLimit, "Maximum number of tasks")
+	prTasksCmd.Flags().Bool("json", false, "Output as JSON")
+}
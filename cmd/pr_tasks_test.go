@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestSortTasks(t *testing.T) {
+	tasks := []api.Comment{
+		{ID: 1, State: api.CommentStateResolved, CreatedDate: 100},
+		{ID: 2, State: api.CommentStateOpen, CreatedDate: 300},
+		{ID: 3, State: api.CommentStateOpen, CreatedDate: 200},
+		{ID: 4, State: api.CommentStateResolved, CreatedDate: 50},
+	}
+
+	sortTasks(tasks)
+
+	want := []int{3, 2, 4, 1}
+	for i, id := range want {
+		if tasks[i].ID != id {
+			t.Fatalf("order = %v, want %v", []int{tasks[0].ID, tasks[1].ID, tasks[2].ID, tasks[3].ID}, want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// stdinReader is shared by every prompt so buffered input is not lost
+// between questions.
+var stdinReader = bufio.NewReader(os.Stdin)
+
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// promptLine prints label to stderr and reads one line from stdin, without
+// the trailing newline.
+func promptLine(label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	input, err := stdinReader.ReadString('\n')
+	if err != nil && input == "" {
+		return "", fmt.Errorf("reading input: %w", err)
+	}
+	return strings.TrimSpace(input), nil
+}
@@ -0,0 +1,566 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/lroolle/atlas-cli/pkg/cmd/page/shared"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Manage JIRA project versions and releases",
+	Long: `Commands for creating, releasing and archiving JIRA project versions,
+and for writing release notes from their issues.
+
+Versions are named or given by ID. --project defaults to
+jira.default_project.`,
+}
+
+var releaseListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List a project's versions",
+	Aliases: []string{"ls"},
+	Example: `  atl release list
+  atl release list -p MYPROJ --all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		project, err := releaseProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		versions, err := client.GetProjectVersions(ctx, project)
+		if err != nil {
+			return err
+		}
+		if all, _ := cmd.Flags().GetBool("all"); !all {
+			versions = unarchivedVersions(versions)
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(versions)
+		}
+
+		if len(versions) == 0 {
+			fmt.Printf("No versions in %s\n", project)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSTATE\tSTART\tRELEASE\tDESCRIPTION")
+		for _, v := range versions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				v.ID, v.Name, versionState(v), valueOr(v.StartDate, "-"), valueOr(v.ReleaseDate, "-"),
+				cmdutil.Truncate(v.Description, cmdutil.TitleTruncateNormal))
+		}
+		return w.Flush()
+	},
+}
+
+var releaseCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a version",
+	Example: `  atl release create 1.4.0
+  atl release create 1.4.0 -d "Payments rework" --start 2024-05-01 --release-date 2024-05-31`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		project, err := releaseProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		req := api.VersionRequest{Name: args[0], Project: project}
+		req.Description, _ = cmd.Flags().GetString("description")
+		for flag, dst := range map[string]*string{"start": &req.StartDate, "release-date": &req.ReleaseDate} {
+			value, _ := cmd.Flags().GetString(flag)
+			if value == "" {
+				continue
+			}
+			if _, err := time.Parse(dateLayout, value); err != nil {
+				return fmt.Errorf("invalid --%s %q: use YYYY-MM-DD", flag, value)
+			}
+			*dst = value
+		}
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		version, err := client.CreateVersion(ctx, req)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created version %s in %s (ID %s)\n", version.Name, project, version.ID)
+		return nil
+	},
+}
+
+var releaseReleaseCmd = &cobra.Command{
+	Use:   "release [version]",
+	Short: "Mark a version released",
+	Long: `Mark a version released, dated today unless --date is given.
+
+A version with unresolved issues is not released unless --force is
+given; 'atl release status' lists them.`,
+	Example: `  atl release release 1.4.0
+  atl release release 1.4.0 --date 2024-05-31 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		project, err := releaseProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		date, _ := cmd.Flags().GetString("date")
+		if date == "" {
+			date = time.Now().Format(dateLayout)
+		} else if _, err := time.Parse(dateLayout, date); err != nil {
+			return fmt.Errorf("invalid --date %q: use YYYY-MM-DD", date)
+		}
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		version, err := findVersion(ctx, client, project, args[0])
+		if err != nil {
+			return err
+		}
+		if version.Released {
+			fmt.Printf("Version %s is already released\n", version.Name)
+			return nil
+		}
+
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			open, err := client.SearchIssues(ctx, unresolvedVersionJQL(project, *version), 1)
+			if err != nil {
+				return err
+			}
+			if len(open) > 0 {
+				return fmt.Errorf("version %s has unresolved issues (see 'atl release status %s'); use --force to release anyway", version.Name, version.Name)
+			}
+		}
+
+		if _, err := client.UpdateVersion(ctx, version.ID, map[string]interface{}{"released": true, "releaseDate": date}); err != nil {
+			return err
+		}
+		fmt.Printf("Released %s on %s\n", version.Name, date)
+		return nil
+	},
+}
+
+var releaseArchiveCmd = &cobra.Command{
+	Use:   "archive [version]",
+	Short: "Archive a version",
+	Long:  `Archive a version, hiding it from version pickers. --undo restores it.`,
+	Example: `  atl release archive 1.2.0
+  atl release archive 1.2.0 --undo`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		project, err := releaseProject(cmd)
+		if err != nil {
+			return err
+		}
+		undo, _ := cmd.Flags().GetBool("undo")
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		version, err := findVersion(ctx, client, project, args[0])
+		if err != nil {
+			return err
+		}
+		if _, err := client.UpdateVersion(ctx, version.ID, map[string]interface{}{"archived": !undo}); err != nil {
+			return err
+		}
+
+		verb := "Archived"
+		if undo {
+			verb = "Unarchived"
+		}
+		fmt.Printf("%s version %s\n", verb, version.Name)
+		return nil
+	},
+}
+
+var releaseStatusCmd = &cobra.Command{
+	Use:   "status [version]",
+	Short: "Show the unresolved issues blocking a version",
+	Long: `Show the unresolved issues whose fix version is the given version.
+
+Without a version, the earliest unreleased, unarchived one is used.`,
+	Example: `  atl release status
+  atl release status 1.4.0 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		project, err := releaseProject(cmd)
+		if err != nil {
+			return err
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		var version *api.Version
+		if len(args) == 1 {
+			version, err = findVersion(ctx, client, project, args[0])
+		} else {
+			version, err = nextVersion(ctx, client, project)
+		}
+		if err != nil {
+			return err
+		}
+
+		issues, err := client.SearchIssues(ctx, unresolvedVersionJQL(project, *version), limit)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"version": version, "unresolved": issues})
+		}
+
+		fmt.Printf("%s %s [%s]", bold(project), bold(version.Name), versionState(*version))
+		if version.ReleaseDate != "" {
+			fmt.Printf(", due %s", version.ReleaseDate)
+		}
+		fmt.Println()
+
+		if len(issues) == 0 {
+			fmt.Println("No unresolved issues; ready to release")
+			return nil
+		}
+
+		fmt.Printf("%d unresolved issue(s):\n\n", len(issues))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tTYPE\tSTATUS\tASSIGNEE\tSUMMARY")
+		for _, issue := range issues {
+			assignee := "Unassigned"
+			if issue.Fields.Assignee != nil {
+				assignee = issue.Fields.Assignee.DisplayName
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				issue.Key, issue.Fields.IssueType.Name, issueStatus(issue.Fields.Status), assignee,
+				cmdutil.Truncate(issue.Fields.Summary, cmdutil.TitleTruncateNormal))
+		}
+		return w.Flush()
+	},
+}
+
+var releaseNotesCmd = &cobra.Command{
+	Use:   "notes [version]",
+	Short: "Generate release notes for a version",
+	Long: `Generate Markdown release notes from the issues whose fix version is the
+given version, grouped by issue type.
+
+--publish creates a Confluence page with the notes in --space (default
+confluence.default_space), optionally under --parent.`,
+	Example: `  atl release notes 1.4.0
+  atl release notes 1.4.0 > CHANGELOG-1.4.0.md
+  atl release notes 1.4.0 --publish --space ENG --parent "Release Notes"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReleaseNotes,
+}
+
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	project, err := releaseProject(cmd)
+	if err != nil {
+		return err
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	client, err := api.GetJiraClient()
+	cmdutil.ExitIfError(err)
+
+	version, err := findVersion(ctx, client, project, args[0])
+	if err != nil {
+		return err
+	}
+
+	jql := fmt.Sprintf("project = '%s' AND fixVersion = %s ORDER BY issuetype ASC, key ASC", escapeJQL(project), version.ID)
+	issues, err := client.SearchIssues(ctx, jql, limit)
+	if err != nil {
+		return err
+	}
+
+	if len(issues) == limit {
+		fmt.Fprintf(os.Stderr, "Warning: stopped at --limit %d issues; the notes may be incomplete\n", limit)
+	}
+
+	notes := buildReleaseNotes(project, *version, issues, client.BaseURL)
+	if notes.Unresolved > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d issue(s) in %s are unresolved\n", notes.Unresolved, version.Name)
+	}
+
+	if publish, _ := cmd.Flags().GetBool("publish"); !publish {
+		fmt.Print(notes.markdown())
+		return nil
+	}
+
+	space, _ := cmd.Flags().GetString("space")
+	if space == "" {
+		space = viper.GetString("confluence.default_space")
+	}
+	if space == "" {
+		return fmt.Errorf("space required: use --space or set confluence.default_space in config")
+	}
+	title, _ := cmd.Flags().GetString("title")
+	if title == "" {
+		title = notes.Title
+	}
+
+	confluence, err := shared.GetConfluenceClient()
+	if err != nil {
+		return err
+	}
+	parent, _ := cmd.Flags().GetString("parent")
+	parentID, err := shared.ResolvePage(ctx, confluence, parent, space)
+	if err != nil {
+		return err
+	}
+
+	page, err := confluence.CreatePage(ctx, space, title, notes.storage(), parentID)
+	if err != nil {
+		return fmt.Errorf("publishing release notes: %w", err)
+	}
+	fmt.Printf("Published %q to %s (page %s)\n", page.Title, space, page.ID)
+	if webUI, ok := page.Links["webui"]; ok {
+		fmt.Printf("%s%s\n", confluence.BaseURL, webUI)
+	}
+	return nil
+}
+
+func releaseProject(cmd *cobra.Command) (string, error) {
+	project := projectFromFlags(cmd)
+	if project == "" {
+		return "", fmt.Errorf("project required: use --project or set jira.default_project in config")
+	}
+	return project, nil
+}
+
+func findVersion(ctx context.Context, client *api.JiraClient, project, value string) (*api.Version, error) {
+	versions, err := client.GetProjectVersions(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	return matchVersion(versions, project, value)
+}
+
+// matchVersion finds a version by ID or name, preferring an exact name.
+func matchVersion(versions []api.Version, project, value string) (*api.Version, error) {
+	for i, v := range versions {
+		if v.Name == value || v.ID == value {
+			return &versions[i], nil
+		}
+	}
+	for i, v := range versions {
+		if strings.EqualFold(v.Name, value) {
+			return &versions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no version %q in %s", value, project)
+}
+
+// nextVersion is the version a team is working toward: the first
+// unreleased, unarchived one in the project's order.
+func nextVersion(ctx context.Context, client *api.JiraClient, project string) (*api.Version, error) {
+	versions, err := client.GetProjectVersions(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range versions {
+		if !v.Released && !v.Archived {
+			return &versions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no unreleased versions", project)
+}
+
+func unarchivedVersions(versions []api.Version) []api.Version {
+	var kept []api.Version
+	for _, v := range versions {
+		if !v.Archived {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+func versionState(v api.Version) string {
+	switch {
+	case v.Archived:
+		return dim("archived")
+	case v.Released:
+		return green("released")
+	case v.Overdue:
+		return red("overdue")
+	}
+	return "unreleased"
+}
+
+// unresolvedVersionJQL selects the issues still open in a version. The
+// version ID is used because names need not be unique across projects.
+func unresolvedVersionJQL(project string, v api.Version) string {
+	return fmt.Sprintf("project = '%s' AND fixVersion = %s AND resolution = EMPTY ORDER BY priority DESC, key ASC", escapeJQL(project), v.ID)
+}
+
+// releaseNotes is a version's issues grouped by type, ready to render as
+// Markdown or as Confluence storage format.
+type releaseNotes struct {
+	Title       string
+	Released    string
+	Description string
+	Groups      []releaseGroup
+	Unresolved  int
+	browseURL   string
+}
+
+type releaseGroup struct {
+	Type   string
+	Issues []api.Issue
+}
+
+// releaseTypeOrder puts what users care about first; other types follow
+// alphabetically.
+var releaseTypeOrder = []string{"epic", "new feature", "feature", "story", "improvement", "bug", "task", "sub-task", "subtask"}
+
+func buildReleaseNotes(project string, v api.Version, issues []api.Issue, baseURL string) releaseNotes {
+	notes := releaseNotes{
+		Title:       fmt.Sprintf("%s %s release notes", project, v.Name),
+		Description: v.Description,
+		browseURL:   strings.TrimSuffix(baseURL, "/") + "/browse/",
+	}
+	if v.Released {
+		notes.Released = valueOr(v.ReleaseDate, "yes")
+	}
+
+	index := map[string]int{}
+	for _, issue := range issues {
+		if issue.Fields.Resolution == nil {
+			notes.Unresolved++
+		}
+		t := valueOr(issue.Fields.IssueType.Name, "Other")
+		i, ok := index[t]
+		if !ok {
+			i = len(notes.Groups)
+			index[t] = i
+			notes.Groups = append(notes.Groups, releaseGroup{Type: t})
+		}
+		notes.Groups[i].Issues = append(notes.Groups[i].Issues, issue)
+	}
+
+	rank := func(t string) int {
+		for i, known := range releaseTypeOrder {
+			if strings.EqualFold(t, known) {
+				return i
+			}
+		}
+		return len(releaseTypeOrder)
+	}
+	sort.SliceStable(notes.Groups, func(i, j int) bool {
+		ri, rj := rank(notes.Groups[i].Type), rank(notes.Groups[j].Type)
+		if ri != rj {
+			return ri < rj
+		}
+		return notes.Groups[i].Type < notes.Groups[j].Type
+	})
+	return notes
+}
+
+func (n releaseNotes) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", n.Title)
+	if n.Released != "" {
+		fmt.Fprintf(&b, "\nReleased: %s\n", n.Released)
+	}
+	if n.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", n.Description)
+	}
+	if len(n.Groups) == 0 {
+		b.WriteString("\nNo issues.\n")
+	}
+	for _, g := range n.Groups {
+		fmt.Fprintf(&b, "\n## %s\n\n", g.Type)
+		for _, issue := range g.Issues {
+			fmt.Fprintf(&b, "- [%s](%s%s) %s\n", issue.Key, n.browseURL, issue.Key, issue.Fields.Summary)
+		}
+	}
+	return b.String()
+}
+
+// storage renders the notes as Confluence storage format (XHTML). The
+// page title carries the heading.
+func (n releaseNotes) storage() string {
+	var b strings.Builder
+	if n.Released != "" {
+		fmt.Fprintf(&b, "<p><strong>Released:</strong> %s</p>", html.EscapeString(n.Released))
+	}
+	if n.Description != "" {
+		fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(n.Description))
+	}
+	if len(n.Groups) == 0 {
+		b.WriteString("<p>No issues.</p>")
+	}
+	for _, g := range n.Groups {
+		fmt.Fprintf(&b, "<h2>%s</h2><ul>", html.EscapeString(g.Type))
+		for _, issue := range g.Issues {
+			fmt.Fprintf(&b, `<li><a href="%s">%s</a> %s</li>`,
+				html.EscapeString(n.browseURL+issue.Key), html.EscapeString(issue.Key), html.EscapeString(issue.Fields.Summary))
+		}
+		b.WriteString("</ul>")
+	}
+	return b.String()
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+	releaseCmd.AddCommand(releaseListCmd)
+	releaseCmd.AddCommand(releaseCreateCmd)
+	releaseCmd.AddCommand(releaseReleaseCmd)
+	releaseCmd.AddCommand(releaseArchiveCmd)
+	releaseCmd.AddCommand(releaseStatusCmd)
+	releaseCmd.AddCommand(releaseNotesCmd)
+
+	releaseCmd.PersistentFlags().StringP("project", "p", "", "Project key (default: jira.default_project)")
+
+	releaseListCmd.Flags().Bool("all", false, "Include archived versions")
+	releaseListCmd.Flags().Bool("json", false, "Output as JSON")
+
+	releaseCreateCmd.Flags().StringP("description", "d", "", "Version description")
+	releaseCreateCmd.Flags().String("start", "", "Start date (YYYY-MM-DD)")
+	releaseCreateCmd.Flags().String("release-date", "", "Planned release date (YYYY-MM-DD)")
+
+	releaseReleaseCmd.Flags().String("date", "", "Release date (YYYY-MM-DD, default today)")
+	releaseReleaseCmd.Flags().Bool("force", false, "Release even with unresolved issues")
+
+	releaseArchiveCmd.Flags().Bool("undo", false, "Unarchive the version")
+
+	releaseStatusCmd.Flags().Int("limit", 100, "Maximum number of issues")
+	releaseStatusCmd.Flags().Bool("json", false, "Output as JSON")
+
+	releaseNotesCmd.Flags().Int("limit", 500, "Maximum number of issues")
+	releaseNotesCmd.Flags().Bool("publish", false, "Publish the notes as a Confluence page")
+	releaseNotesCmd.Flags().StringP("space", "s", "", "Confluence space for --publish")
+	releaseNotesCmd.Flags().String("parent", "", "Parent page for --publish: ID, title or URL")
+	releaseNotesCmd.Flags().StringP("title", "t", "", "Page title for --publish")
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestMatchVersion(t *testing.T) {
+	versions := []api.Version{
+		{ID: "100", Name: "1.4.0"},
+		{ID: "101", Name: "Beta"},
+		{ID: "102", Name: "beta"},
+	}
+
+	tests := []struct {
+		value, wantID string
+	}{
+		{"1.4.0", "100"},
+		{"101", "101"},
+		{"beta", "102"},
+		{"BETA", "101"},
+	}
+	for _, tt := range tests {
+		v, err := matchVersion(versions, "MYPROJ", tt.value)
+		if err != nil {
+			t.Errorf("matchVersion(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if v.ID != tt.wantID {
+			t.Errorf("matchVersion(%q) = %s, want %s", tt.value, v.ID, tt.wantID)
+		}
+	}
+
+	if _, err := matchVersion(versions, "MYPROJ", "2.0"); err == nil || !strings.Contains(err.Error(), "MYPROJ") {
+		t.Errorf("missing version error = %v", err)
+	}
+}
+
+func TestReleaseNotes(t *testing.T) {
+	issue := func(key, typ, summary string, resolved bool) api.Issue {
+		i := api.Issue{Key: key, Fields: api.IssueFields{Summary: summary, IssueType: api.IssueType{Name: typ}}}
+		if resolved {
+			i.Fields.Resolution = &api.Resolution{Name: "Done"}
+		}
+		return i
+	}
+	issues := []api.Issue{
+		issue("MYPROJ-3", "Bug", "Fix <script> escaping", true),
+		issue("MYPROJ-1", "Chore", "Bump deps", true),
+		issue("MYPROJ-2", "Story", "Export to CSV", false),
+		issue("MYPROJ-4", "Bug", "Crash on start", true),
+	}
+	version := api.Version{Name: "1.4.0", Released: true, ReleaseDate: "2024-05-31", Description: "Payments & exports"}
+
+	notes := buildReleaseNotes("MYPROJ", version, issues, "https://jira.example.com/")
+	if notes.Unresolved != 1 {
+		t.Errorf("unresolved = %d, want 1", notes.Unresolved)
+	}
+
+	wantMD := `# MYPROJ 1.4.0 release notes
+
+Released: 2024-05-31
+
+Payments & exports
+
+## Story
+
+- [MYPROJ-2](https://jira.example.com/browse/MYPROJ-2) Export to CSV
+
+## Bug
+
+- [MYPROJ-3](https://jira.example.com/browse/MYPROJ-3) Fix <script> escaping
+- [MYPROJ-4](https://jira.example.com/browse/MYPROJ-4) Crash on start
+
+## Chore
+
+- [MYPROJ-1](https://jira.example.com/browse/MYPROJ-1) Bump deps
+`
+	if got := notes.markdown(); got != wantMD {
+		t.Errorf("markdown:\n%s\nwant:\n%s", got, wantMD)
+	}
+
+	storage := notes.storage()
+	for _, want := range []string{
+		"<p>Payments &amp; exports</p>",
+		"<h2>Story</h2><ul><li>",
+		`<a href="https://jira.example.com/browse/MYPROJ-3">MYPROJ-3</a> Fix &lt;script&gt; escaping`,
+	} {
+		if !strings.Contains(storage, want) {
+			t.Errorf("storage missing %q:\n%s", want, storage)
+		}
+	}
+}
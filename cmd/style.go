@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/lroolle/atlas-cli/pkg/converter"
+	"golang.org/x/term"
+)
+
+// colorEnabled is decided once: styling is for people reading a terminal,
+// never for pipes, and NO_COLOR (https://no-color.org) turns it off.
+var colorEnabled = os.Getenv("NO_COLOR") == "" && term.IsTerminal(int(os.Stdout.Fd()))
+
+func ansi(code, s string) string {
+	if !colorEnabled || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func bold(s string) string   { return ansi("1", s) }
+func dim(s string) string    { return ansi("2", s) }
+func red(s string) string    { return ansi("31", s) }
+func green(s string) string  { return ansi("32", s) }
+func yellow(s string) string { return ansi("33", s) }
+func blue(s string) string   { return ansi("34", s) }
+func cyan(s string) string   { return ansi("36", s) }
+
+// statusColor colors a JIRA status by its category, the way boards do.
+func statusColor(name, category string) string {
+	switch category {
+	case "done":
+		return green(name)
+	case "indeterminate":
+		return yellow(name)
+	case "new":
+		return blue(name)
+	}
+	return name
+}
+
+var styleCode = regexp.MustCompile("`([^`]+)`")
+
+// renderMarkdown styles Markdown for the terminal: headings bold, code
+// blocks indented and dimmed, quotes dimmed, **bold** and `code` spans
+// highlighted. Without color the text is returned unchanged.
+func renderMarkdown(md string) string {
+	if !colorEnabled {
+		return md
+	}
+
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inCode = !inCode
+		case inCode:
+			out = append(out, dim("    "+line))
+		case strings.HasPrefix(trimmed, "#"):
+			out = append(out, bold(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))))
+		case strings.HasPrefix(trimmed, ">"):
+			out = append(out, dim(line))
+		default:
+			line = converter.ReplaceMarkdownBold(line, bold)
+			line = styleCode.ReplaceAllStringFunc(line, func(m string) string {
+				return cyan(styleCode.FindStringSubmatch(m)[1])
+			})
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
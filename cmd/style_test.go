@@ -0,0 +1,15 @@
+package cmd
+
+import "testing"
+
+func TestRenderMarkdownBoldSpans(t *testing.T) {
+	saved := colorEnabled
+	colorEnabled = true
+	defer func() { colorEnabled = saved }()
+
+	got := renderMarkdown("**a** and **b**")
+	want := bold("a") + " and " + bold("b")
+	if got != want {
+		t.Errorf("renderMarkdown = %q, want %q", got, want)
+	}
+}
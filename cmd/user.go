@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lroolle/atlas-cli/api"
+	"github.com/lroolle/atlas-cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Look up JIRA and Bitbucket users",
+	Long: `Look up users by username, email or display name.
+
+The same lookup runs wherever atl takes a user: issue create/edit
+--assignee, issue list --assignee, pr create --reviewer and pr edit
+--add-reviewer accept an email or "Display Name" as well as a username.`,
+}
+
+var userSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search users by username, email or name",
+	Example: `  atl user search jane
+  atl user search jane.doe@example.com --json
+  atl user search doe --bitbucket`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		limit, _ := cmd.Flags().GetInt("limit")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+		if bitbucket, _ := cmd.Flags().GetBool("bitbucket"); bitbucket {
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			users, err := client.SearchUsers(ctx, args[0], limit)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return json.NewEncoder(os.Stdout).Encode(users)
+			}
+			if len(users) == 0 {
+				fmt.Printf("No Bitbucket users match %q\n", args[0])
+				return nil
+			}
+			fmt.Fprintln(w, "NAME\tDISPLAY NAME\tEMAIL\tACTIVE")
+			for _, u := range users {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", u.Name, u.DisplayName, u.EmailAddress, u.Active)
+			}
+			return w.Flush()
+		}
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		users, err := client.SearchUsers(ctx, args[0], limit)
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(users)
+		}
+		if len(users) == 0 {
+			fmt.Printf("No JIRA users match %q\n", args[0])
+			return nil
+		}
+
+		idHeader := "NAME"
+		if client.IsCloud() {
+			idHeader = "ACCOUNT ID"
+		}
+		fmt.Fprintf(w, "%s\tDISPLAY NAME\tEMAIL\tACTIVE\n", idHeader)
+		for _, u := range users {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", jiraUserID(client, u), u.DisplayName, u.EmailAddress, u.Active)
+		}
+		return w.Flush()
+	},
+}
+
+var userViewCmd = &cobra.Command{
+	Use:   "view [user]",
+	Short: "Show a JIRA user (default: you)",
+	Example: `  atl user view
+  atl user view jdoe
+  atl user view "Jane Doe"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		query := "me"
+		if len(args) > 0 {
+			query = args[0]
+		}
+
+		client, err := api.GetJiraClient()
+		cmdutil.ExitIfError(err)
+
+		var user *api.JiraUser
+		if query == "me" {
+			user, err = client.GetMyself(ctx)
+		} else {
+			user, err = resolveJiraUser(ctx, client, query)
+			if err == nil {
+				user, err = client.GetUser(ctx, jiraUserID(client, *user))
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return json.NewEncoder(os.Stdout).Encode(user)
+		}
+
+		fmt.Printf("%s\n", bold(user.DisplayName))
+		if user.Name != "" {
+			printDetail("Username", user.Name)
+		}
+		if user.AccountID != "" {
+			printDetail("Account ID", user.AccountID)
+		}
+		if user.EmailAddress != "" {
+			printDetail("Email", user.EmailAddress)
+		}
+		if user.TimeZone != "" {
+			printDetail("Time zone", user.TimeZone)
+		}
+		printDetail("Active", fmt.Sprintf("%t", user.Active))
+		return nil
+	},
+}
+
+// Cloud account IDs: a 24-digit hex ID, or a numeric prefix and a UUID.
+var accountIDPattern = regexp.MustCompile(`^(?:[0-9a-f]{24}|\d+:[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+
+// resolveJiraUser turns "me", a username, email, account ID or display
+// name into a user. Server usernames and Cloud account IDs are used as
+// given; anything else goes through the user search API.
+func resolveJiraUser(ctx context.Context, client *api.JiraClient, value string) (*api.JiraUser, error) {
+	if value == "me" {
+		if !client.IsCloud() {
+			if name := currentJiraUsername(); name != "" {
+				return &api.JiraUser{Name: name}, nil
+			}
+		}
+		return client.GetMyself(ctx)
+	}
+
+	if client.IsCloud() && accountIDPattern.MatchString(value) {
+		return &api.JiraUser{AccountID: value}, nil
+	}
+	if !client.IsCloud() && !strings.ContainsAny(value, "@ ") {
+		return &api.JiraUser{Name: value}, nil
+	}
+	return findJiraUser(ctx, client, value)
+}
+
+// jiraUserID is how the installation identifies a user in fields and JQL:
+// the account ID on Cloud, the username on Server.
+func jiraUserID(client *api.JiraClient, user api.JiraUser) string {
+	if client.IsCloud() {
+		return user.AccountID
+	}
+	return user.Name
+}
+
+// jiraUserRef is the value for a user field such as assignee.
+func jiraUserRef(client *api.JiraClient, user api.JiraUser) map[string]string {
+	if client.IsCloud() {
+		return map[string]string{"accountId": user.AccountID}
+	}
+	return map[string]string{"name": user.Name}
+}
+
+// findJiraUser looks a user up by username, email, account ID or display
+// name. An exact match wins; otherwise the search must find exactly one.
+func findJiraUser(ctx context.Context, client *api.JiraClient, query string) (*api.JiraUser, error) {
+	users, err := client.SearchUsers(ctx, query, 10)
+	if err != nil {
+		return nil, err
+	}
+	return matchJiraUser(users, query)
+}
+
+func matchJiraUser(users []api.JiraUser, query string) (*api.JiraUser, error) {
+	return matchUser(users, query, "JIRA", jiraUserIDs,
+		func(u api.JiraUser) string { return userLabel(u.DisplayName, u.Name, u.EmailAddress) })
+}
+
+func jiraUserIDs(u api.JiraUser) []string {
+	return []string{u.Name, u.AccountID, u.EmailAddress, u.DisplayName}
+}
+
+// findMentionedUser looks up the user an @mention names. Unlike
+// findJiraUser it takes no near matches: a mention notifies the user, so
+// @jo must not reach whoever the search happens to return.
+func findMentionedUser(ctx context.Context, client *api.JiraClient, query string) (*api.JiraUser, error) {
+	users, err := client.SearchUsers(ctx, query, 10)
+	if err != nil {
+		return nil, err
+	}
+	return matchMentionedUser(users, query)
+}
+
+func matchMentionedUser(users []api.JiraUser, query string) (*api.JiraUser, error) {
+	if u, ok := exactUser(users, query, jiraUserIDs); ok {
+		return u, nil
+	}
+	return nil, fmt.Errorf("no JIRA user has the username, account ID, email or name %q", query)
+}
+
+// resolveBitbucketUsers maps reviewers given as usernames, emails or
+// display names to Bitbucket usernames. Plain usernames pass through
+// without a lookup.
+func resolveBitbucketUsers(ctx context.Context, client *api.BitbucketClient, values []string) ([]string, error) {
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		if !strings.ContainsAny(v, "@ ") {
+			names = append(names, v)
+			continue
+		}
+		users, err := client.SearchUsers(ctx, v, 10)
+		if err != nil {
+			return nil, fmt.Errorf("looking up %q: %w", v, err)
+		}
+		user, err := matchBitbucketUser(users, v)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, user.Name)
+	}
+	return names, nil
+}
+
+// currentBitbucketUser looks up the configured Bitbucket login, which can
+// be an email rather than the username.
+func currentBitbucketUser(ctx context.Context, client *api.BitbucketClient) (*api.User, error) {
+	users, err := client.SearchUsers(ctx, client.Username, 10)
+	if err != nil {
+		return nil, err
+	}
+	return matchBitbucketUser(users, client.Username)
+}
+
+func matchBitbucketUser(users []api.User, query string) (*api.User, error) {
+	return matchUser(users, query, "Bitbucket",
+		func(u api.User) []string { return []string{u.Name, u.Slug, u.EmailAddress, u.DisplayName} },
+		func(u api.User) string { return userLabel(u.DisplayName, u.Name, u.EmailAddress) })
+}
+
+// matchUser picks the user one of whose identifiers equals query
+// (case-insensitively), else the only search result.
+func matchUser[U any](users []U, query, service string, ids func(U) []string, label func(U) string) (*U, error) {
+	if u, ok := exactUser(users, query, ids); ok {
+		return u, nil
+	}
+
+	switch len(users) {
+	case 0:
+		return nil, fmt.Errorf("no %s user matches %q", service, query)
+	case 1:
+		return &users[0], nil
+	}
+	labels := make([]string, len(users))
+	for i, u := range users {
+		labels[i] = label(u)
+	}
+	return nil, fmt.Errorf("%q matches several %s users: %s", query, service, strings.Join(labels, ", "))
+}
+
+// exactUser returns the user one of whose identifiers equals query
+// (case-insensitively).
+func exactUser[U any](users []U, query string, ids func(U) []string) (*U, bool) {
+	for i, u := range users {
+		for _, id := range ids(u) {
+			if id != "" && strings.EqualFold(id, query) {
+				return &users[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+func userLabel(displayName, name, email string) string {
+	id := name
+	if id == "" {
+		id = email
+	}
+	if id == "" {
+		return displayName
+	}
+	return fmt.Sprintf("%s (%s)", displayName, id)
+}
+
+func init() {
+	rootCmd.AddCommand(userCmd)
+	userCmd.AddCommand(userSearchCmd)
+	userCmd.AddCommand(userViewCmd)
+
+	userSearchCmd.Flags().Int("limit", 20, "Maximum number of users")
+	userSearchCmd.Flags().Bool("bitbucket", false, "Search Bitbucket users instead of JIRA")
+	userSearchCmd.Flags().Bool("json", false, "Output as JSON")
+
+	userViewCmd.Flags().Bool("json", false, "Output as JSON")
+}
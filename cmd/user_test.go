@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/lroolle/atlas-cli/api"
+)
+
+func TestMatchJiraUser(t *testing.T) {
+	users := []api.JiraUser{
+		{Name: "jdoe", DisplayName: "Jane Doe", EmailAddress: "jane@example.com"},
+		{Name: "jdoerr", DisplayName: "John Doerr", EmailAddress: "john@example.com"},
+	}
+
+	tests := []struct {
+		query   string
+		users   []api.JiraUser
+		want    string
+		wantErr bool
+	}{
+		{"jdoe", users, "jdoe", false},
+		{"JANE@example.com", users, "jdoe", false},
+		{"john doerr", users, "jdoerr", false},
+		{"jd", users, "", true},
+		{"jd", users[1:], "jdoerr", false},
+		{"nobody", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := matchJiraUser(tt.users, tt.query)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("matchJiraUser(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.Name != tt.want {
+			t.Errorf("matchJiraUser(%q) = %s, want %s", tt.query, got.Name, tt.want)
+		}
+	}
+}
+
+func TestMatchMentionedUser(t *testing.T) {
+	jdoerr := []api.JiraUser{{Name: "jdoerr", AccountID: "5b10ac8d", DisplayName: "John Doerr", EmailAddress: "john@example.com"}}
+
+	for _, query := range []string{"jdoerr", "5b10ac8d", "JOHN@example.com", "john doerr"} {
+		if u, err := matchMentionedUser(jdoerr, query); err != nil || u.Name != "jdoerr" {
+			t.Errorf("matchMentionedUser(%q) = %+v, %v; want jdoerr", query, u, err)
+		}
+	}
+	// The only search result is not a mention unless it matches exactly.
+	for _, query := range []string{"jo", "here"} {
+		if u, err := matchMentionedUser(jdoerr, query); err == nil {
+			t.Errorf("matchMentionedUser(%q) = %s, want an error", query, u.Name)
+		}
+	}
+}
+
+func TestMatchBitbucketUser(t *testing.T) {
+	users := []api.User{
+		{Name: "jdoe", Slug: "jdoe", DisplayName: "Jane Doe", EmailAddress: "jane@example.com"},
+		{Name: "jsmith", Slug: "jsmith", DisplayName: "Jane Smith", EmailAddress: "smith@example.com"},
+	}
+
+	if u, err := matchBitbucketUser(users, "Jane Smith"); err != nil || u.Name != "jsmith" {
+		t.Errorf("match by display name = %+v, %v", u, err)
+	}
+	if _, err := matchBitbucketUser(users, "Jane"); err == nil {
+		t.Error("expected an ambiguity error for Jane")
+	}
+}
+
+func TestResolveJiraUserWithoutLookup(t *testing.T) {
+	server := api.NewJiraClient("https://jira.example.com", "tester", "token")
+	server.InstallationType = api.InstallationTypeServer
+	cloud := api.NewJiraClient("https://example.atlassian.net", "tester", "token")
+
+	tests := []struct {
+		name   string
+		client *api.JiraClient
+		value  string
+		want   map[string]string
+	}{
+		{"server username", server, "jdoe", map[string]string{"name": "jdoe"}},
+		{"cloud account ID", cloud, "5b10a2844c20165700ede21f", map[string]string{"accountId": "5b10a2844c20165700ede21f"}},
+		{"cloud prefixed account ID", cloud, "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077", map[string]string{"accountId": "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := resolveJiraUser(context.Background(), tt.client, tt.value)
+			if err != nil {
+				t.Fatalf("resolveJiraUser(%q) error: %v", tt.value, err)
+			}
+			if got := jiraUserRef(tt.client, *user); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jiraUserRef = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,628 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ADFNode is a node of an Atlassian Document Format document, the JSON
+// representation of rich text in the JIRA Cloud v3 REST API. A document is
+// a "doc" node whose content is block nodes (paragraph, heading, codeBlock,
+// lists, ...); text lives in "text" leaf nodes carrying marks.
+type ADFNode struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []*ADFNode             `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []ADFMark              `json:"marks,omitempty"`
+}
+
+// ADFMark formats a text node: strong, em, code, strike, underline, link.
+type ADFMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+var (
+	adfMDFence    = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([\\w+#.-]*)")
+	adfMDRule     = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	adfMDTaskBox  = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	adfMDTableSep = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	adfMDLink     = regexp.MustCompile(`^\[([^\]]+)\]\(([^)\s]+)\)`)
+	adfMDMention  = regexp.MustCompile(`^\[~accountid:([^\]]+)\]`)
+	adfMDBareURL  = regexp.MustCompile(`^https?://[^\s<>()]+[^\s<>().,;:!?'"]`)
+)
+
+// MarkdownToADF converts Markdown to an ADF document: headings, paragraphs
+// (line breaks kept as hard breaks), fenced code, block quotes, nested and
+// task lists, tables, rules, and inline bold, italic, strikethrough, code
+// and links. [~accountid:ID] becomes a user mention.
+func MarkdownToADF(markdown string) *ADFNode {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	return &ADFNode{Type: "doc", Version: 1, Content: parseMarkdownBlocks(lines)}
+}
+
+func parseMarkdownBlocks(lines []string) []*ADFNode {
+	var blocks []*ADFNode
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case adfMDFence.MatchString(line):
+			m := adfMDFence.FindStringSubmatch(line)
+			fence := m[1]
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // closing fence
+			node := &ADFNode{Type: "codeBlock"}
+			if m[2] != "" {
+				node.Attrs = map[string]interface{}{"language": m[2]}
+			}
+			if text := strings.Join(code, "\n"); text != "" {
+				node.Content = []*ADFNode{{Type: "text", Text: text}}
+			}
+			blocks = append(blocks, node)
+
+		case mdHeading.MatchString(line):
+			m := mdHeading.FindStringSubmatch(line)
+			blocks = append(blocks, &ADFNode{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": len(m[1])},
+				Content: parseMarkdownInline(m[2], nil),
+			})
+			i++
+
+		case adfMDRule.MatchString(line):
+			blocks = append(blocks, &ADFNode{Type: "rule"})
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(q, " "))
+				i++
+			}
+			blocks = append(blocks, &ADFNode{Type: "blockquote", Content: parseMarkdownBlocks(quoted)})
+
+		case mdListItem.MatchString(line):
+			start := i
+			i++
+			for i < len(lines) {
+				l := lines[i]
+				if strings.TrimSpace(l) == "" {
+					// A blank line continues the list only if it goes on.
+					if i+1 < len(lines) && (mdListItem.MatchString(lines[i+1]) || leadingSpaces(lines[i+1]) > 0) {
+						i++
+						continue
+					}
+					break
+				}
+				if !mdListItem.MatchString(l) && leadingSpaces(l) == 0 {
+					break
+				}
+				i++
+			}
+			blocks = append(blocks, parseMarkdownList(lines[start:i]))
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && adfMDTableSep.MatchString(lines[i+1]):
+			table := &ADFNode{Type: "table", Content: []*ADFNode{markdownTableRow(lines[i], "tableHeader")}}
+			i += 2
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
+				table.Content = append(table.Content, markdownTableRow(lines[i], "tableCell"))
+				i++
+			}
+			blocks = append(blocks, table)
+
+		default:
+			var para []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(para) == 0 || !startsBlock(lines, i)) {
+				para = append(para, strings.TrimSpace(lines[i]))
+				i++
+			}
+			p := &ADFNode{Type: "paragraph"}
+			for n, l := range para {
+				if n > 0 {
+					p.Content = append(p.Content, &ADFNode{Type: "hardBreak"})
+				}
+				p.Content = append(p.Content, parseMarkdownInline(l, nil)...)
+			}
+			blocks = append(blocks, p)
+		}
+	}
+	return blocks
+}
+
+// startsBlock reports whether lines[i] opens something other than a
+// paragraph, ending the paragraph before it.
+func startsBlock(lines []string, i int) bool {
+	line := lines[i]
+	trimmed := strings.TrimSpace(line)
+	return adfMDFence.MatchString(line) || mdHeading.MatchString(line) ||
+		strings.HasPrefix(trimmed, ">") || mdListItem.MatchString(line) ||
+		adfMDRule.MatchString(line) ||
+		(strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && adfMDTableSep.MatchString(lines[i+1]))
+}
+
+func leadingSpaces(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " \t"))
+}
+
+// parseMarkdownList builds a list from the lines of one list. Items start
+// at the list's own indentation; deeper lines belong to the item above
+// and are parsed as blocks of their own, which yields nested lists.
+func parseMarkdownList(lines []string) *ADFNode {
+	first := mdListItem.FindStringSubmatch(lines[0])
+	indent := len(first[1])
+
+	list := &ADFNode{Type: "bulletList"}
+	ordered := unicode.IsDigit(rune(first[2][0]))
+	if ordered {
+		list.Type = "orderedList"
+		if n, _ := strconv.Atoi(strings.TrimRight(first[2], ".)")); n > 1 {
+			list.Attrs = map[string]interface{}{"order": n}
+		}
+	}
+
+	type item struct {
+		text string
+		rest []string
+	}
+	var items []*item
+	for _, l := range lines {
+		if m := mdListItem.FindStringSubmatch(l); m != nil && len(m[1]) <= indent {
+			items = append(items, &item{text: m[3]})
+			continue
+		}
+		if len(items) == 0 {
+			continue
+		}
+		cur := items[len(items)-1]
+		if strings.TrimSpace(l) == "" {
+			cur.rest = append(cur.rest, "")
+			continue
+		}
+		// Strip the item's indentation so nested content parses at column 0.
+		strip := indent + len(first[2]) + 1
+		if n := leadingSpaces(l); n < strip {
+			strip = n
+		}
+		cur.rest = append(cur.rest, l[strip:])
+	}
+
+	tasks := !ordered
+	for _, it := range items {
+		if !adfMDTaskBox.MatchString(it.text) {
+			tasks = false
+		}
+	}
+	if tasks {
+		list.Type = "taskList"
+		list.Attrs = map[string]interface{}{"localId": ""}
+	}
+
+	for n, it := range items {
+		if tasks {
+			m := adfMDTaskBox.FindStringSubmatch(it.text)
+			state := "TODO"
+			if m[1] != " " {
+				state = "DONE"
+			}
+			list.Content = append(list.Content, &ADFNode{
+				Type:    "taskItem",
+				Attrs:   map[string]interface{}{"localId": strconv.Itoa(n + 1), "state": state},
+				Content: parseMarkdownInline(strings.TrimSpace(it.text[len(m[0]):]), nil),
+			})
+			continue
+		}
+		content := parseMarkdownBlocks(append([]string{it.text}, it.rest...))
+		if len(content) == 0 {
+			content = []*ADFNode{{Type: "paragraph"}}
+		}
+		list.Content = append(list.Content, &ADFNode{Type: "listItem", Content: content})
+	}
+	return list
+}
+
+func markdownTableRow(line, cellType string) *ADFNode {
+	row := &ADFNode{Type: "tableRow"}
+	for _, cell := range markdownCells(line) {
+		row.Content = append(row.Content, &ADFNode{
+			Type:    cellType,
+			Content: []*ADFNode{{Type: "paragraph", Content: parseMarkdownInline(cell, nil)}},
+		})
+	}
+	return row
+}
+
+// parseMarkdownInline turns one line of Markdown into text nodes, adding
+// marks to the ones already in effect.
+func parseMarkdownInline(s string, marks []ADFMark) []*ADFNode {
+	var nodes []*ADFNode
+	var buf strings.Builder
+
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = append(nodes, textNode(buf.String(), marks))
+			buf.Reset()
+		}
+	}
+	emit := func(inner []*ADFNode) {
+		flush()
+		nodes = append(nodes, inner...)
+	}
+
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		c := s[i]
+
+		if c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_~[]()#>|-!", s[i+1]) >= 0 {
+			buf.WriteByte(s[i+1])
+			i += 2
+			continue
+		}
+
+		if c == '`' {
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				emit([]*ADFNode{textNode(rest[1:end+1], withMark(codeMarks(marks), ADFMark{Type: "code"}))})
+				i += end + 2
+				continue
+			}
+		}
+
+		if m := adfMDMention.FindStringSubmatch(rest); m != nil {
+			emit([]*ADFNode{{Type: "mention", Attrs: map[string]interface{}{"id": m[1]}}})
+			i += len(m[0])
+			continue
+		}
+
+		if m := adfMDLink.FindStringSubmatch(rest); m != nil {
+			link := ADFMark{Type: "link", Attrs: map[string]interface{}{"href": m[2]}}
+			emit(parseMarkdownInline(m[1], withMark(marks, link)))
+			i += len(m[0])
+			continue
+		}
+
+		if c == 'h' && (i == 0 || !isWordByte(s[i-1])) {
+			if m := adfMDBareURL.FindString(rest); m != "" {
+				link := ADFMark{Type: "link", Attrs: map[string]interface{}{"href": m}}
+				emit([]*ADFNode{textNode(m, withMark(marks, link))})
+				i += len(m)
+				continue
+			}
+		}
+
+		if delim, mark := emphasisAt(s, i); delim != "" {
+			if end := closingDelim(s, i+len(delim), delim); end > 0 {
+				emit(parseMarkdownInline(s[i+len(delim):end], withMark(marks, ADFMark{Type: mark})))
+				i = end + len(delim)
+				continue
+			}
+		}
+
+		buf.WriteByte(c)
+		i++
+	}
+	flush()
+	return nodes
+}
+
+// emphasisAt returns the emphasis delimiter opening at s[i] and its mark.
+// Underscores only count at word boundaries, so snake_case stays as is.
+func emphasisAt(s string, i int) (string, string) {
+	rest := s[i:]
+	if i+1 >= len(s) || s[i+1] == ' ' {
+		return "", ""
+	}
+	switch {
+	case strings.HasPrefix(rest, "**"):
+		return "**", "strong"
+	case strings.HasPrefix(rest, "__") && (i == 0 || !isWordByte(s[i-1])):
+		return "__", "strong"
+	case strings.HasPrefix(rest, "~~"):
+		return "~~", "strike"
+	case rest[0] == '*':
+		return "*", "em"
+	case rest[0] == '_' && (i == 0 || !isWordByte(s[i-1])):
+		return "_", "em"
+	}
+	return "", ""
+}
+
+// closingDelim finds the delimiter closing an emphasis that starts at
+// from: not preceded by a space and, for underscores, not inside a word.
+func closingDelim(s string, from int, delim string) int {
+	for j := from + 1; j+len(delim) <= len(s); j++ {
+		if s[j:j+len(delim)] != delim || s[j-1] == ' ' {
+			continue
+		}
+		after := j + len(delim)
+		if after < len(s) && s[after] == delim[0] {
+			continue // part of a longer run, e.g. ** when looking for *
+		}
+		if delim[0] == '_' && after < len(s) && isWordByte(s[after]) {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+func textNode(text string, marks []ADFMark) *ADFNode {
+	return &ADFNode{Type: "text", Text: text, Marks: marks}
+}
+
+func withMark(marks []ADFMark, mark ADFMark) []ADFMark {
+	out := make([]ADFMark, len(marks), len(marks)+1)
+	copy(out, marks)
+	return append(out, mark)
+}
+
+// codeMarks keeps only the marks ADF allows alongside code: links.
+func codeMarks(marks []ADFMark) []ADFMark {
+	var out []ADFMark
+	for _, m := range marks {
+		if m.Type == "link" {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// ADFToMarkdown converts an ADF document to Markdown. Nodes without a
+// Markdown equivalent are reduced to their text: panels become quotes,
+// mentions @names, media an [attachment] placeholder.
+func ADFToMarkdown(doc *ADFNode) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(adfBlocks(doc.Content))
+}
+
+func adfBlocks(nodes []*ADFNode) string {
+	var parts []string
+	for _, n := range nodes {
+		if s := adfBlock(n); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func adfBlock(n *ADFNode) string {
+	switch n.Type {
+	case "paragraph":
+		return adfInline(n.Content)
+	case "heading":
+		level := intAttr(n, "level")
+		if level < 1 || level > 6 {
+			level = 1
+		}
+		return strings.Repeat("#", level) + " " + adfInline(n.Content)
+	case "codeBlock":
+		return "```" + stringAttr(n, "language") + "\n" + adfPlainText(n.Content) + "\n```"
+	case "blockquote", "panel":
+		return prefixLines(adfBlocks(n.Content), "> ", "> ")
+	case "bulletList", "orderedList":
+		return adfList(n)
+	case "taskList", "decisionList":
+		var items []string
+		for _, item := range n.Content {
+			box := ""
+			if item.Type == "taskItem" {
+				box = "[ ] "
+				if stringAttr(item, "state") == "DONE" {
+					box = "[x] "
+				}
+			}
+			items = append(items, "- "+box+adfInline(item.Content))
+		}
+		return strings.Join(items, "\n")
+	case "rule":
+		return "---"
+	case "table":
+		return adfTable(n)
+	case "mediaSingle", "mediaGroup":
+		return adfBlocks(n.Content)
+	case "media":
+		if alt := stringAttr(n, "alt"); alt != "" {
+			return "[attachment: " + alt + "]"
+		}
+		return "[attachment]"
+	case "expand", "nestedExpand":
+		body := adfBlocks(n.Content)
+		if title := stringAttr(n, "title"); title != "" {
+			return "**" + title + "**\n\n" + body
+		}
+		return body
+	}
+	if len(n.Content) > 0 && isInlineNode(n.Content[0]) {
+		return adfInline(n.Content)
+	}
+	if len(n.Content) > 0 {
+		return adfBlocks(n.Content)
+	}
+	return adfInline([]*ADFNode{n})
+}
+
+func adfList(n *ADFNode) string {
+	start := intAttr(n, "order")
+	if start < 1 {
+		start = 1
+	}
+	var items []string
+	for i, item := range n.Content {
+		marker := "- "
+		if n.Type == "orderedList" {
+			marker = fmt.Sprintf("%d. ", start+i)
+		}
+		// Blocks inside an item are separated by single newlines so that a
+		// nested list stays attached to its parent item.
+		var parts []string
+		for _, child := range item.Content {
+			if s := adfBlock(child); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, prefixLines(strings.Join(parts, "\n"), marker, indent))
+	}
+	return strings.Join(items, "\n")
+}
+
+func adfTable(n *ADFNode) string {
+	var rows []string
+	for i, row := range n.Content {
+		var cells []string
+		for _, cell := range row.Content {
+			text := strings.ReplaceAll(adfBlocks(cell.Content), "\n\n", " ")
+			text = strings.ReplaceAll(text, "\n", " ")
+			cells = append(cells, strings.ReplaceAll(text, "|", "\\|"))
+		}
+		rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			seps := make([]string, len(cells))
+			for j := range seps {
+				seps[j] = "---"
+			}
+			rows = append(rows, "| "+strings.Join(seps, " | ")+" |")
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+func adfInline(nodes []*ADFNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(applyMarks(n.Text, n.Marks))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			name := stringAttr(n, "text")
+			if name == "" {
+				name = stringAttr(n, "id")
+			}
+			b.WriteString("@" + strings.TrimPrefix(name, "@"))
+		case "emoji":
+			if text := stringAttr(n, "text"); text != "" {
+				b.WriteString(text)
+			} else {
+				b.WriteString(stringAttr(n, "shortName"))
+			}
+		case "inlineCard", "blockCard":
+			b.WriteString(stringAttr(n, "url"))
+		case "date":
+			ms, err := strconv.ParseInt(stringAttr(n, "timestamp"), 10, 64)
+			if err == nil {
+				b.WriteString(time.UnixMilli(ms).UTC().Format("2006-01-02"))
+			}
+		case "status":
+			b.WriteString("[" + stringAttr(n, "text") + "]")
+		default:
+			if len(n.Content) > 0 {
+				b.WriteString(adfInline(n.Content))
+			} else {
+				b.WriteString(n.Text)
+			}
+		}
+	}
+	return b.String()
+}
+
+func applyMarks(text string, marks []ADFMark) string {
+	var href string
+	for _, m := range marks {
+		if m.Type == "code" {
+			text = "`" + text + "`"
+		}
+	}
+	for _, m := range marks {
+		switch m.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "*" + text + "*"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			href, _ = m.Attrs["href"].(string)
+		}
+	}
+	if href != "" && href != text {
+		text = "[" + text + "](" + href + ")"
+	}
+	return text
+}
+
+func adfPlainText(nodes []*ADFNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		if n.Type == "hardBreak" {
+			b.WriteString("\n")
+		}
+		b.WriteString(n.Text)
+		b.WriteString(adfPlainText(n.Content))
+	}
+	return b.String()
+}
+
+func isInlineNode(n *ADFNode) bool {
+	switch n.Type {
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "date", "status":
+		return true
+	}
+	return false
+}
+
+// prefixLines puts first before the first line of s and rest before the
+// others, skipping the indentation of blank lines.
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		p := rest
+		if i == 0 {
+			p = first
+		}
+		if l == "" && i > 0 {
+			p = strings.TrimRight(p, " ")
+		}
+		lines[i] = p + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+func stringAttr(n *ADFNode, key string) string {
+	switch v := n.Attrs[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	}
+	return ""
+}
+
+func intAttr(n *ADFNode, key string) int {
+	i, _ := strconv.Atoi(stringAttr(n, key))
+	return i
+}
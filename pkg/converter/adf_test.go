@@ -0,0 +1,138 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarkdownToADF(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			"paragraph with marks",
+			"**bold** *it* ~~old~~ `a_b` snake_case",
+			`[{"type":"paragraph","content":[{"type":"text","text":"bold","marks":[{"type":"strong"}]},{"type":"text","text":" "},{"type":"text","text":"it","marks":[{"type":"em"}]},{"type":"text","text":" "},{"type":"text","text":"old","marks":[{"type":"strike"}]},{"type":"text","text":" "},{"type":"text","text":"a_b","marks":[{"type":"code"}]},{"type":"text","text":" snake_case"}]}]`,
+		},
+		{
+			"line breaks",
+			"one\ntwo",
+			`[{"type":"paragraph","content":[{"type":"text","text":"one"},{"type":"hardBreak"},{"type":"text","text":"two"}]}]`,
+		},
+		{
+			"links and mention",
+			"[docs](https://x/y) https://a.b/c. [~accountid:123]",
+			`[{"type":"paragraph","content":[{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://x/y"}}]},{"type":"text","text":" "},{"type":"text","text":"https://a.b/c","marks":[{"type":"link","attrs":{"href":"https://a.b/c"}}]},{"type":"text","text":". "},{"type":"mention","attrs":{"id":"123"}}]}]`,
+		},
+		{
+			"heading and code",
+			"## Steps\n```go\nx := *p\n```",
+			`[{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Steps"}]},{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := *p"}]}]`,
+		},
+		{
+			"nested list",
+			"- a\n  1. b",
+			`[{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]},{"type":"orderedList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}]}]}]}]`,
+		},
+		{
+			"table",
+			"| A |\n| --- |\n| b |",
+			`[{"type":"table","content":[{"type":"tableRow","content":[{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"A"}]}]}]},{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}]}]}]`,
+		},
+		{
+			"quote and rule",
+			"> quoted\n\n---",
+			`[{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"quoted"}]}]},{"type":"rule"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := MarkdownToADF(tt.in)
+			if doc.Type != "doc" || doc.Version != 1 {
+				t.Fatalf("root = %s v%d, want doc v1", doc.Type, doc.Version)
+			}
+			got, err := json.Marshal(doc.Content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarkdownToADF(%q)\n got: %s\nwant: %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestADFToMarkdown(t *testing.T) {
+	doc := `{"type":"doc","version":1,"content":[
+		{"type":"heading","attrs":{"level":3},"content":[{"type":"text","text":"Title"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Hi "},
+			{"type":"mention","attrs":{"id":"123","text":"@Jane Doe"}},
+			{"type":"text","text":", see "},
+			{"type":"text","text":"this","marks":[{"type":"strong"},{"type":"link","attrs":{"href":"https://x"}}]},
+			{"type":"hardBreak"},
+			{"type":"emoji","attrs":{"shortName":":smile:","text":"😄"}},
+			{"type":"text","text":" "},
+			{"type":"status","attrs":{"text":"DONE"}}
+		]},
+		{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"note"}]}]},
+		{"type":"orderedList","attrs":{"order":3},"content":[
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"three"}]},
+				{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"sub"}]}]}]}
+			]}
+		]},
+		{"type":"taskList","attrs":{"localId":"x"},"content":[
+			{"type":"taskItem","attrs":{"localId":"1","state":"DONE"},"content":[{"type":"text","text":"done"}]}
+		]},
+		{"type":"codeBlock","content":[{"type":"text","text":"a\nb"}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"abc"}}]},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"K"}]}]}]},
+			{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"a|b"}]}]}]}
+		]}
+	]}`
+
+	var node ADFNode
+	if err := json.Unmarshal([]byte(doc), &node); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "### Title\n\n" +
+		"Hi @Jane Doe, see [**this**](https://x)\n😄 [DONE]\n\n" +
+		"> note\n\n" +
+		"3. three\n   - sub\n\n" +
+		"- [x] done\n\n" +
+		"```\na\nb\n```\n\n" +
+		"[attachment]\n\n" +
+		"| K |\n| --- |\n| a\\|b |"
+	if got := ADFToMarkdown(&node); got != want {
+		t.Errorf("ADFToMarkdown\n got: %q\nwant: %q", got, want)
+	}
+
+	if got := ADFToMarkdown(nil); got != "" {
+		t.Errorf("ADFToMarkdown(nil) = %q", got)
+	}
+}
+
+func TestMarkdownADFRoundTrip(t *testing.T) {
+	inputs := []string{
+		"## Summary\n\nFixed **null** handling in `parse()`, see [PR](https://x/pr/1).",
+		"- one\n- two\n  - nested",
+		"1. first\n2. second",
+		"- [ ] todo\n- [x] done",
+		"```sql\nSELECT *\nFROM t\n```",
+		"> quoted\n> twice",
+		"| A | B |\n| --- | --- |\n| 1 | 2 |",
+		"first line\nsecond line\n\n---\n\n~~gone~~ *now*",
+	}
+
+	for _, in := range inputs {
+		if got := ADFToMarkdown(MarkdownToADF(in)); got != in {
+			t.Errorf("round trip of %q = %q", in, got)
+		}
+	}
+}
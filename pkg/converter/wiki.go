@@ -0,0 +1,431 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// JIRA wiki markup (the format of descriptions and comments in the v2 REST
+// API) and Markdown differ mostly in their markers, so both directions are
+// line-oriented rewrites. Code spans and links are swapped out for
+// placeholders first so inline rules never touch their contents.
+
+var (
+	wikiHeading     = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	wikiListItem    = regexp.MustCompile(`^([*#-]+)\s+(.*)$`)
+	wikiCodeOpen    = regexp.MustCompile(`^\s*\{(code|noformat)(?::([^}]*))?\}(.*)$`)
+	wikiQuoteTag    = regexp.MustCompile(`^\s*\{quote\}\s*$`)
+	wikiPanelTag    = regexp.MustCompile(`\{panel(?::[^}]*)?\}`)
+	wikiColorTag    = regexp.MustCompile(`\{color(?::[^}]*)?\}`)
+	wikiMonospace   = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	wikiLink        = regexp.MustCompile(`\[([^\[\]]+?)\]`)
+	wikiImage       = regexp.MustCompile(`!([^!\s|][^!|]*?)(?:\|[^!]*)?!`)
+	wikiBold        = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*`)
+	wikiItalic      = regexp.MustCompile(`_([^_\s](?:[^_]*[^_\s])?)_`)
+	wikiStrike      = regexp.MustCompile(`(^|\s)-(\S(?:[^-]*?\S)?)-($|\s|[.,;:!?])`)
+	placeholderText = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// WikiToMarkdown converts JIRA wiki markup to Markdown: headings, bold,
+// italic, strikethrough, monospace, {code}/{noformat} blocks, {quote} and
+// bq., lists, tables, links, [~mentions], attachments and images.
+// Unsupported macros are left as they are.
+func WikiToMarkdown(wiki string) string {
+	lines := strings.Split(strings.ReplaceAll(wiki, "\r\n", "\n"), "\n")
+	var out []string
+
+	inCode, codeTag := false, ""
+	inQuote := false
+	inTable := false
+
+	for _, line := range lines {
+		if inCode {
+			if idx := strings.Index(line, "{"+codeTag+"}"); idx >= 0 {
+				if before := line[:idx]; strings.TrimSpace(before) != "" {
+					out = append(out, before)
+				}
+				out = append(out, "```")
+				inCode = false
+				if rest := strings.TrimSpace(line[idx+len(codeTag)+2:]); rest != "" {
+					out = append(out, wikiInline(rest))
+				}
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+
+		if m := wikiCodeOpen.FindStringSubmatch(line); m != nil {
+			codeTag = m[1]
+			out = append(out, "```"+codeLanguage(m[1], m[2]))
+			rest := m[3]
+			// Single-line blocks: {code}x = 1{code}
+			if idx := strings.Index(rest, "{"+codeTag+"}"); idx >= 0 {
+				out = append(out, rest[:idx], "```")
+				continue
+			}
+			if strings.TrimSpace(rest) != "" {
+				out = append(out, rest)
+			}
+			inCode = true
+			continue
+		}
+
+		if wikiQuoteTag.MatchString(line) {
+			inQuote = !inQuote
+			continue
+		}
+
+		isRow := strings.HasPrefix(strings.TrimSpace(line), "|")
+		if isRow {
+			row, header := wikiTableRow(strings.TrimSpace(line))
+			if !inTable && !header {
+				// Markdown tables need a header row.
+				out = append(out, markdownRow(make([]string, len(row))), markdownSeparator(len(row)))
+			}
+			out = append(out, markdownRow(row))
+			if header {
+				out = append(out, markdownSeparator(len(row)))
+			}
+			inTable = true
+			continue
+		}
+		inTable = false
+
+		converted := wikiBlockLine(line)
+		if inQuote {
+			converted = "> " + converted
+		}
+		out = append(out, converted)
+	}
+	if inCode {
+		out = append(out, "```")
+	}
+
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+func codeLanguage(tag, params string) string {
+	if tag != "code" || params == "" {
+		return ""
+	}
+	for _, p := range strings.Split(params, "|") {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			if strings.TrimSpace(k) == "language" {
+				return strings.TrimSpace(v)
+			}
+			continue
+		}
+		return strings.TrimSpace(p)
+	}
+	return ""
+}
+
+func wikiBlockLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+	line = wikiPanelTag.ReplaceAllString(line, "")
+
+	switch {
+	case trimmed == "----":
+		return "---"
+	case strings.HasPrefix(trimmed, "bq. "):
+		return "> " + wikiInline(strings.TrimPrefix(trimmed, "bq. "))
+	}
+	if m := wikiHeading.FindStringSubmatch(trimmed); m != nil {
+		return strings.Repeat("#", int(m[1][0]-'0')) + " " + wikiInline(m[2])
+	}
+	if m := wikiListItem.FindStringSubmatch(trimmed); m != nil {
+		markers := m[1]
+		// A lone "-" item is a list; "--" or more is text (e.g. "-- signature").
+		if !(strings.Contains(markers, "-") && markers != "-") {
+			indent := strings.Repeat("  ", len(markers)-1)
+			bullet := "-"
+			if markers[len(markers)-1] == '#' {
+				bullet = "1."
+			}
+			return indent + bullet + " " + wikiInline(m[2])
+		}
+	}
+	return wikiInline(line)
+}
+
+func wikiInline(s string) string {
+	var held []string
+	hold := func(v string) string {
+		held = append(held, v)
+		return fmt.Sprintf("\x00%d\x00", len(held)-1)
+	}
+
+	s = wikiMonospace.ReplaceAllStringFunc(s, func(m string) string {
+		return hold("`" + wikiMonospace.FindStringSubmatch(m)[1] + "`")
+	})
+	s = wikiImage.ReplaceAllStringFunc(s, func(m string) string {
+		return hold("![](" + wikiImage.FindStringSubmatch(m)[1] + ")")
+	})
+	s = wikiLink.ReplaceAllStringFunc(s, func(m string) string {
+		return hold(wikiLinkToMarkdown(wikiLink.FindStringSubmatch(m)[1]))
+	})
+
+	s = wikiColorTag.ReplaceAllString(s, "")
+	s = replaceDelimited(s, wikiBold, '*', func(inner string) string { return "**" + inner + "**" })
+	s = replaceDelimited(s, wikiItalic, '_', func(inner string) string { return "*" + inner + "*" })
+	s = wikiStrike.ReplaceAllString(s, "$1~~$2~~$3")
+	s = strings.ReplaceAll(s, `\\`, "  \n")
+
+	return placeholderText.ReplaceAllStringFunc(s, func(m string) string {
+		var i int
+		fmt.Sscanf(placeholderText.FindStringSubmatch(m)[1], "%d", &i)
+		return held[i]
+	})
+}
+
+func wikiLinkToMarkdown(inner string) string {
+	switch {
+	case strings.HasPrefix(inner, "~accountid:"):
+		return "@" + strings.TrimPrefix(inner, "~accountid:")
+	case strings.HasPrefix(inner, "~"):
+		return "@" + inner[1:]
+	case strings.HasPrefix(inner, "^"):
+		return "`" + inner[1:] + "`"
+	}
+
+	text, target, ok := strings.Cut(inner, "|")
+	if !ok {
+		if isURL(inner) {
+			return "<" + inner + ">"
+		}
+		// [PROJ-123] and anchors have no Markdown equivalent.
+		return "[" + inner + "]"
+	}
+	return "[" + text + "](" + target + ")"
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "mailto:")
+}
+
+// wikiTableRow splits "||a||b||" (header) or "|a|b|" into cells.
+func wikiTableRow(line string) ([]string, bool) {
+	header := strings.HasPrefix(line, "||")
+	sep := "|"
+	if header {
+		sep = "||"
+	}
+	line = strings.TrimSuffix(strings.TrimPrefix(line, sep), sep)
+
+	// Links may contain | themselves; keep [..|..] intact.
+	var cells []string
+	depth, start := 0, 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case '|':
+			if depth > 0 {
+				continue
+			}
+			cells = append(cells, line[start:i])
+			if header && i+1 < len(line) && line[i+1] == '|' {
+				i++
+			}
+			start = i + 1
+		}
+	}
+	cells = append(cells, line[start:])
+
+	for i, c := range cells {
+		cells[i] = strings.ReplaceAll(wikiInline(strings.TrimSpace(c)), "|", `\|`)
+	}
+	return cells, header
+}
+
+func markdownRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+func markdownSeparator(n int) string {
+	return "|" + strings.Repeat(" --- |", n)
+}
+
+var (
+	mdHeading    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdListItem   = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdFence      = regexp.MustCompile("^\\s*```\\s*([\\w+-]*)\\s*$")
+	mdCodeSpan   = regexp.MustCompile("`([^`]+)`")
+	mdImage      = regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdAutoLink   = regexp.MustCompile(`<((?:https?|mailto):[^>]+)>`)
+	mdBoldStar   = regexp.MustCompile(`\*\*([^*\s](?:[^*]*[^*\s])?)\*\*`)
+	mdBoldLine   = regexp.MustCompile(`__([^_\s](?:[^_]*[^_\s])?)__`)
+	mdItalic     = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*`)
+	mdStrike     = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	mdTableSep   = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+	boldSentinel = "\x01"
+)
+
+// MarkdownToWiki converts Markdown to JIRA wiki markup, covering the same
+// constructs as WikiToMarkdown. @name is left as text: only a user lookup
+// can tell a mention, which callers do before converting.
+func MarkdownToWiki(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	var out []string
+	inCode := false
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			switch {
+			case inCode:
+				out = append(out, "{code}")
+			case m[1] != "":
+				out = append(out, "{code:"+m[1]+"}")
+			default:
+				out = append(out, "{code}")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, line)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "|") {
+			// A header row is the one followed by a separator row.
+			if i+1 < len(lines) && mdTableSep.MatchString(strings.TrimSpace(lines[i+1])) {
+				out = append(out, "||"+strings.Join(markdownCells(trimmed), "||")+"||")
+				i++
+				continue
+			}
+			out = append(out, "|"+strings.Join(markdownCells(trimmed), "|")+"|")
+			continue
+		}
+
+		out = append(out, markdownBlockLine(line))
+	}
+	if inCode {
+		out = append(out, "{code}")
+	}
+
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+func markdownBlockLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "---" || trimmed == "***" || trimmed == "___":
+		return "----"
+	case strings.HasPrefix(trimmed, ">"):
+		return "bq. " + markdownInline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+	}
+	if m := mdHeading.FindStringSubmatch(trimmed); m != nil {
+		return fmt.Sprintf("h%d. %s", len(m[1]), markdownInline(m[2]))
+	}
+	if m := mdListItem.FindStringSubmatch(line); m != nil {
+		depth := len(strings.ReplaceAll(m[1], "\t", "  "))/2 + 1
+		marker := "*"
+		if m[2][0] >= '0' && m[2][0] <= '9' {
+			marker = "#"
+		}
+		return strings.Repeat(marker, depth) + " " + markdownInline(m[3])
+	}
+	return markdownInline(line)
+}
+
+func markdownInline(s string) string {
+	var held []string
+	hold := func(v string) string {
+		held = append(held, v)
+		return fmt.Sprintf("\x00%d\x00", len(held)-1)
+	}
+
+	s = mdCodeSpan.ReplaceAllStringFunc(s, func(m string) string {
+		return hold("{{" + mdCodeSpan.FindStringSubmatch(m)[1] + "}}")
+	})
+	s = mdImage.ReplaceAllStringFunc(s, func(m string) string {
+		return hold("!" + mdImage.FindStringSubmatch(m)[1] + "!")
+	})
+	s = mdLink.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLink.FindStringSubmatch(m)
+		return hold("[" + sub[1] + "|" + sub[2] + "]")
+	})
+	s = mdAutoLink.ReplaceAllStringFunc(s, func(m string) string {
+		return hold("[" + mdAutoLink.FindStringSubmatch(m)[1] + "]")
+	})
+
+	s = ReplaceMarkdownBold(s, func(inner string) string { return boldSentinel + inner + boldSentinel })
+	s = replaceDelimited(s, mdItalic, '*', func(inner string) string { return "_" + inner + "_" })
+	s = strings.ReplaceAll(s, boldSentinel, "*")
+	s = mdStrike.ReplaceAllString(s, "-$1-")
+
+	return placeholderText.ReplaceAllStringFunc(s, func(m string) string {
+		var i int
+		fmt.Sscanf(placeholderText.FindStringSubmatch(m)[1], "%d", &i)
+		return held[i]
+	})
+}
+
+// ReplaceMarkdownBold rewrites each **bold** or __bold__ span of s with
+// repl of its text. Spans do not contain their own delimiter, so several
+// on one line stay separate.
+func ReplaceMarkdownBold(s string, repl func(inner string) string) string {
+	for _, re := range []*regexp.Regexp{mdBoldStar, mdBoldLine} {
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			return repl(re.FindStringSubmatch(m)[1])
+		})
+	}
+	return s
+}
+
+// replaceDelimited rewrites the matches of re (a span between two delim
+// characters) with repl of the span's text, where the characters around
+// the match are neither word characters nor delim. The boundaries are
+// checked rather than matched, so adjacent spans can share one.
+func replaceDelimited(s string, re *regexp.Regexp, delim byte, repl func(inner string) string) string {
+	var out strings.Builder
+	pos := 0
+	for pos < len(s) {
+		loc := re.FindStringSubmatchIndex(s[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if !isSpanBoundary(s, start-1, delim) || !isSpanBoundary(s, end, delim) {
+			out.WriteString(s[pos : start+1])
+			pos = start + 1
+			continue
+		}
+		out.WriteString(s[pos:start])
+		out.WriteString(repl(s[pos+loc[2] : pos+loc[3]]))
+		pos = end
+	}
+	out.WriteString(s[pos:])
+	return out.String()
+}
+
+// isSpanBoundary reports whether s[i] may border an emphasis span: the
+// start or end of s, or anything but a word character or delim.
+func isSpanBoundary(s string, i int, delim byte) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+	c := s[i]
+	isWord := c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	return !isWord && c != delim
+}
+
+func markdownCells(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i, c := range cells {
+		cells[i] = markdownInline(strings.TrimSpace(c))
+	}
+	return cells
+}
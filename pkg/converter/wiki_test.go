@@ -0,0 +1,97 @@
+package converter
+
+import "testing"
+
+func TestWikiToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"heading", "h2. Steps to reproduce", "## Steps to reproduce"},
+		{"inline", "*bold* and _italic_ and -gone- and {{a_b*c}}", "**bold** and *italic* and ~~gone~~ and `a_b*c`"},
+		{"link with text", "see [the docs|https://example.com/a_b]", "see [the docs](https://example.com/a_b)"},
+		{"bare link", "[https://example.com]", "<https://example.com>"},
+		{"mentions", "ping [~jdoe] and [~accountid:5b10a2844c20165700ede21g]", "ping @jdoe and @5b10a2844c20165700ede21g"},
+		{"attachment and image", "[^trace.log] !screen.png|thumbnail!", "`trace.log` ![](screen.png)"},
+		{"lists", "* one\n** nested\n# first\n## sub", "- one\n  - nested\n1. first\n  1. sub"},
+		{"quote macro", "{quote}\nsaid *this*\n{quote}", "> said **this**"},
+		{"bq", "bq. quoted", "> quoted"},
+		{"rule", "----", "---"},
+		{"color", "{color:red}alert{color}", "alert"},
+		{"adjacent spans", "*a* *b* and _c_ _d_", "**a** **b** and *c* *d*"},
+		{"intraword stays", "2*3*4 and snake_case_name", "2*3*4 and snake_case_name"},
+		{"hyphenated words stay", "a well-known non-issue - really", "a well-known non-issue - really"},
+		{
+			"code block",
+			"{code:java}\nint *p = a_b;\n{code}",
+			"```java\nint *p = a_b;\n```",
+		},
+		{
+			"code block with title param",
+			"{code:title=Foo.go|language=go}\nx := 1\n{code}",
+			"```go\nx := 1\n```",
+		},
+		{"noformat", "{noformat}\n*raw*\n{noformat}", "```\n*raw*\n```"},
+		{"one-line code", "{code}x = 1{code}", "```\nx = 1\n```"},
+		{
+			"table with header",
+			"||Name||Link||\n|api|[docs|https://x/y]|",
+			"| Name | Link |\n| --- | --- |\n| api | [docs](https://x/y) |",
+		},
+		{
+			"table without header",
+			"|a|b|",
+			"|  |  |\n| --- | --- |\n| a | b |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WikiToMarkdown(tt.in); got != tt.want {
+				t.Errorf("WikiToMarkdown(%q) =\n%q\nwant\n%q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownToWiki(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"heading", "### Notes", "h3. Notes"},
+		{"inline", "**bold** and *italic* and ~~gone~~ and `a*b`", "*bold* and _italic_ and -gone- and {{a*b}}"},
+		{"two bold spans", "**a** and **b**", "*a* and *b*"},
+		{"mismatched bold delimiters", "**x__", "**x__"},
+		{"underscore bold", "__a__ and __b__", "*a* and *b*"},
+		{"adjacent italics", "*a* *b*", "_a_ _b_"},
+		{"link", "[docs](https://example.com/a_b)", "[docs|https://example.com/a_b]"},
+		{"autolink", "<https://example.com>", "[https://example.com]"},
+		{"image", "![shot](screen.png)", "!screen.png!"},
+		{"mention stays text", "thanks @jdoe, mail jdoe@example.com", "thanks @jdoe, mail jdoe@example.com"},
+		{"resolved mention kept", "thanks [~jdoe]", "thanks [~jdoe]"},
+		{"lists", "- one\n  - nested\n1. first", "* one\n** nested\n# first"},
+		{"quote", "> quoted", "bq. quoted"},
+		{"rule", "---", "----"},
+		{"code", "```go\nx := *p\n```", "{code:go}\nx := *p\n{code}"},
+		{"plain code", "```\nraw\n```", "{code}\nraw\n{code}"},
+		{"table", "| a | b |\n|---|---|\n| 1 | 2 |", "||a||b||\n|1|2|"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownToWiki(tt.in); got != tt.want {
+				t.Errorf("MarkdownToWiki(%q) =\n%q\nwant\n%q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWikiMarkdownRoundTrip(t *testing.T) {
+	wiki := "h1. Title\n*bold* _it_ {{code}} [x|https://e.com]\n* a\n** b\n{code:sh}\necho *\n{code}"
+	if got := MarkdownToWiki(WikiToMarkdown(wiki)); got != wiki {
+		t.Errorf("round trip =\n%q\nwant\n%q", got, wiki)
+	}
+}